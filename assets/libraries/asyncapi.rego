@@ -0,0 +1,56 @@
+package generic.asyncapi
+
+import data.generic.common as common_lib
+
+check_asyncapi(doc) = version {
+	regex.match("^2\\.\\d+\\.\\d+$", doc.asyncapi)
+	version = "2.0"
+} else = version {
+	version = "undefined"
+}
+
+# operations available in the channels of AsyncAPI 2.x documents
+operations := {"publish", "subscribe"}
+
+# It verifies if the object defines at least one security requirement and none of them is empty
+# (an empty requirement makes the authentication optional)
+has_security(obj) {
+	is_array(obj.security)
+	count(obj.security) > 0
+	count({x | x := obj.security[_]; count(x) == 0}) == 0
+}
+
+# It returns the names of the servers where the channel is available, all servers when the channel does not restrict them
+channel_servers(doc, channel) = servers {
+	is_array(channel.servers)
+	servers := {name | name := channel.servers[_]}
+} else = servers {
+	servers := {name | doc.servers[name]}
+}
+
+# It verifies if all servers where the channel is available require authentication
+secured_by_servers(doc, channel) {
+	servers := channel_servers(doc, channel)
+	count(servers) > 0
+	secured := {name | name := servers[_]; has_security(doc.servers[name])}
+	count(secured) == count(servers)
+}
+
+# It returns the messages of an operation, as pairs of path (from the operation) and message,
+# the messages of 'oneOf' are returned one by one
+get_messages(operation) = messages {
+	is_array(operation.message.oneOf)
+	messages := {[path, message] |
+		message := operation.message.oneOf[idx]
+		path := ["message", "oneOf", sprintf("%d", [idx])]
+	}
+} else = messages {
+	common_lib.valid_key(operation, "message")
+	messages := {[["message"], operation.message]}
+}
+
+# It verifies if the object is a '$ref' that KICS could not resolve (e.g. remote references)
+is_unresolved_ref(obj) {
+	common_lib.valid_key(obj, "$ref")
+	not common_lib.valid_key(obj, "_kics_ref")
+}
@@ -0,0 +1,117 @@
+package generic.cicd
+
+# top level keywords of GitLab CI pipelines, any other top level object is a job
+gitlab_keywords := {
+	"default",
+	"include",
+	"stages",
+	"variables",
+	"workflow",
+	"image",
+	"services",
+	"cache",
+	"before_script",
+	"after_script",
+}
+
+# GitLab CI job properties that contain shell scripts
+gitlab_script_keys := {"script", "before_script", "after_script"}
+
+# GitHub Actions contexts that can be controlled by whoever opens an issue, a pull request or pushes a commit
+untrusted_input_regex := `github\.(head_ref|event\.(issue\.(title|body)|pull_request\.(title|body|head\.(ref|label|repo\.default_branch))|comment\.body|review\.body|review_comment\.body|discussion\.(title|body)|pages\.[^.\s]+\.page_name|commits\.[^.\s]+\.(message|author\.(email|name))|head_commit\.(message|author\.(email|name))|workflow_run\.(head_branch|head_commit\.(message|author\.(email|name)))))`
+
+# It verifies if the document is a GitHub Actions workflow
+is_github_workflow(doc) {
+	is_object(doc.jobs)
+	_ = doc.on
+}
+
+# It verifies if the document is a GitLab CI pipeline
+is_gitlab_ci(doc) {
+	not is_github_workflow(doc)
+	_ = gitlab_jobs(doc)[_]
+}
+
+# It returns the events that trigger a GitHub Actions workflow ('on' may be a string, a list or a map)
+triggers(doc) = events {
+	is_string(doc.on)
+	events := {doc.on}
+} else = events {
+	is_array(doc.on)
+	events := {event | event := doc.on[_]}
+} else = events {
+	is_object(doc.on)
+	events := {event | _ = doc.on[event]}
+} else = events {
+	events := set()
+}
+
+# It returns the names of the jobs of a GitLab CI pipeline
+gitlab_jobs(doc) = jobs {
+	jobs := {name |
+		job := doc[name]
+		is_object(job)
+		not gitlab_keywords[name]
+		_ = job[gitlab_script_keys[_]]
+	}
+}
+
+# It returns the '${{ ... }}' expressions of a value
+expressions(value) = exprs {
+	is_string(value)
+	exprs := regex.find_n(`\$\{\{[^}]*\}\}`, value, -1)
+} else = exprs {
+	exprs := []
+}
+
+# It returns the shell scripts of a GitHub Actions workflow or a GitLab CI pipeline as
+# [script, path] pairs, path being the search path of the script in the document
+scripts(doc) = result {
+	is_github_workflow(doc)
+	result := {[script, path] |
+		step := doc.jobs[job].steps[idx]
+		[script, key] := step_scripts(step)[_]
+		path := array.concat(["jobs", job, "steps", idx], key)
+	}
+} else = result {
+	is_gitlab_ci(doc)
+	result := {[script, path] |
+		job := gitlab_jobs(doc)[_]
+		key := gitlab_script_keys[_]
+		[script, suffix] := gitlab_scripts(doc[job][key])[_]
+		path := array.concat([job, key], suffix)
+	}
+} else = result {
+	result := set()
+}
+
+step_scripts(step) = result {
+	is_string(step.run)
+	result := {[step.run, ["run"]]}
+} else = result {
+	startswith(step.uses, "actions/github-script@")
+	is_string(step["with"].script)
+	result := {[step["with"].script, ["with", "script"]]}
+} else = result {
+	result := set()
+}
+
+gitlab_scripts(value) = result {
+	is_string(value)
+	result := {[value, []]}
+} else = result {
+	result := {[script, [idx]] | script := value[idx]; is_string(script)}
+}
+
+# It verifies if the reference of an action or reusable workflow is a full length commit SHA
+is_pinned(uses) {
+	ref := split(uses, "@")[1]
+	regex.match(`^[0-9a-f]{40}$`, ref)
+}
+
+# It verifies if the action or reusable workflow is defined in the same repository or is a Docker image
+is_local_or_docker(uses) {
+	startswith(uses, "./")
+} else {
+	startswith(uses, "docker://")
+}
@@ -0,0 +1,88 @@
+package generic.dockercompose
+
+# namespaces of the host that should not be shared with the services
+host_namespaces := {"network_mode", "pid", "ipc", "userns_mode", "uts"}
+
+# capabilities that allow a container to escape to the host or to tamper with the host network or processes
+dangerous_capabilities := {
+	"ALL",
+	"SYS_ADMIN",
+	"NET_ADMIN",
+	"SYS_PTRACE",
+	"SYS_MODULE",
+	"SYS_RAWIO",
+	"DAC_READ_SEARCH",
+	"SYS_BOOT",
+	"SYS_TIME",
+	"MAC_ADMIN",
+	"MAC_OVERRIDE",
+	"BPF",
+}
+
+docker_socket_paths := {"/var/run/docker.sock", "/run/docker.sock"}
+
+# It verifies if the document is a Docker Compose file
+check_compose(doc) {
+	is_object(doc.services)
+}
+
+# It returns the host path (or volume name) of a volume defined with the short or the long syntax
+volume_source(volume) = source {
+	is_string(volume)
+	source := split(volume, ":")[0]
+} else = source {
+	is_object(volume)
+	source := volume.source
+}
+
+# It verifies if the volume mounts the Docker daemon socket
+is_docker_socket(volume) {
+	source := trim_right(volume_source(volume), "/")
+	docker_socket_paths[source]
+}
+
+# It verifies if the service has a memory limit, either with the legacy 'mem_limit' or with 'deploy.resources.limits'
+has_memory_limit(service) {
+	service.mem_limit
+} else {
+	service.deploy.resources.limits.memory
+}
+
+# It verifies if the service has a CPU limit, either with the legacy 'cpus' or with 'deploy.resources.limits'
+has_cpu_limit(service) {
+	service.cpus
+} else {
+	service.deploy.resources.limits.cpus
+}
+
+# It returns the environment variables of the service as [key, value, search path] triples,
+# supporting both the map and the list ("KEY=value") syntax
+environment(service) = variables {
+	is_object(service.environment)
+	variables := {[key, value, [key]] | value := service.environment[key]}
+} else = variables {
+	is_array(service.environment)
+	variables := {[key, value, [idx]] |
+		entry := service.environment[idx]
+		is_string(entry)
+		parts := split(entry, "=")
+		count(parts) > 1
+		key := parts[0]
+		value := substring(entry, count(key) + 1, -1)
+	}
+} else = variables {
+	variables := set()
+}
+
+# It verifies if the value is a variable interpolation or substitution (e.g. ${DB_PASSWORD} or $DB_PASSWORD)
+is_interpolated(value) {
+	startswith(value, "$")
+}
+
+# It verifies if the service is fully defined in the document, services of override files usually
+# only define the properties that change and should not be reported for missing properties
+is_complete_service(service) {
+	service.image
+} else {
+	service.build
+}
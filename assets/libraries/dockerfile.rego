@@ -28,3 +28,8 @@ withVersion(pack) {
 arrayContains(array, list) {
 	contains(array[_], list[_])
 }
+
+# isFinalStage checks if the commands of the key belong to the final stage, the image that is shipped
+isFinalStage(document, name) {
+	document.stages[name].Final == true
+}
@@ -0,0 +1,55 @@
+package generic.graphql
+
+# names of the root operation types when the schema definition is omitted
+default_root_types := {"Query", "Mutation", "Subscription"}
+
+# directives that control the access to types and fields in the most common GraphQL servers and gateways
+# (Apollo, AWS AppSync, Hasura, GraphQL Shield, Redwood and others), explicitly public fields are included
+access_control_directives := {
+	"auth",
+	"authenticated",
+	"authorize",
+	"authorized",
+	"aws_api_key",
+	"aws_auth",
+	"aws_cognito_user_pools",
+	"aws_iam",
+	"aws_lambda",
+	"aws_oidc",
+	"hasrole",
+	"haspermission",
+	"isauthenticated",
+	"policy",
+	"requireauth",
+	"requiresscopes",
+	"skipauth",
+	"public",
+}
+
+# pagination arguments used to bound the number of items returned by a list field
+pagination_arguments := {"first", "last", "limit", "take", "top", "pagesize", "perpage", "size", "count"}
+
+# It returns the names of the query, mutation and subscription types of the schema
+root_types(doc) = roots {
+	is_object(doc.schema)
+	roots := {name | op := ["query", "mutation", "subscription"][_]; name := doc.schema[op]}
+} else = roots {
+	roots := default_root_types
+}
+
+# It verifies if the definition has at least one access control directive
+has_access_control(obj) {
+	directive := obj.directives[_]
+	access_control_directives[lower(directive.name)]
+}
+
+# It verifies if the type is an object, interface or union type (types whose lists can grow unbounded)
+is_composite_type(doc, name) {
+	doc.types[name].kind == {"OBJECT", "INTERFACE", "UNION"}[_]
+}
+
+# It verifies if the field has an argument to paginate its results
+has_pagination_argument(field) {
+	field.arguments[name]
+	pagination_arguments[lower(name)]
+}
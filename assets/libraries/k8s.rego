@@ -15,3 +15,38 @@ getSpecInfo(document) = specInfo { # this one can be also used for the result
 checkKind(currentKind, listKinds) {
 	currentKind == listKinds[i]
 }
+
+# It returns the API group of the apiVersion of a resource, empty for the core group
+apiGroup(apiVersion) = group {
+	parts := split(apiVersion, "/")
+	count(parts) == 2
+	group := parts[0]
+} else = ""
+
+# It returns the Crossplane managed resources of the document with the kind and one of the API groups given,
+# including the bases of the resources of a Composition, with the searchKey and the path of each resource
+crossplaneResources(document, kind, groups) = resources {
+	managed := {x |
+		document.kind == kind
+		apiGroup(document.apiVersion) == groups[_]
+		x := {"resource": document, "searchKey": sprintf("metadata.name={{%s}}", [document.metadata.name]), "path": []}
+	}
+	composed := {x |
+		document.kind == "Composition"
+		apiGroup(document.apiVersion) == "apiextensions.crossplane.io"
+		composedResource := document.spec.resources[idx]
+		base := composedResource.base
+		base.kind == kind
+		apiGroup(base.apiVersion) == groups[_]
+		x := {
+			"resource": base,
+			"searchKey": sprintf("metadata.name={{%s}}.spec.resources.name={{%s}}.base", [document.metadata.name, composedResourceName(composedResource, idx)]),
+			"path": ["spec", "resources", idx, "base"],
+		}
+	}
+	resources := managed | composed
+}
+
+composedResourceName(composedResource, idx) = composedResource.name {
+	is_string(composedResource.name)
+} else = sprintf("%d", [idx])
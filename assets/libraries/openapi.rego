@@ -316,3 +316,8 @@ is_missing_attribute_and_ref(obj, attr) {
 	not valid_key(obj, attr)
 	not valid_key(obj, "$ref")
 }
+
+# It verifies if the object is the content of a '$ref' resolved by KICS
+is_resolved_ref(obj) {
+	valid_key(obj, "_kics_ref")
+}
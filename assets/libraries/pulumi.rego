@@ -0,0 +1,26 @@
+package generic.pulumi
+
+# It verifies if the document is a Pulumi YAML program
+check_program(doc) {
+	is_object(doc.resources)
+}
+
+# It returns the type token of the resource without the module member ('aws:s3/bucket:Bucket' is returned as 'aws:s3:Bucket')
+resource_type(resource) = type {
+	parts := split(resource.type, ":")
+	count(parts) == 3
+	type := sprintf("%s:%s:%s", [parts[0], split(parts[1], "/")[0], parts[2]])
+} else = type {
+	type := resource.type
+}
+
+# It verifies if the resource has one of the type tokens given
+is_type(resource, types) {
+	resource_type(resource) == types[_]
+}
+
+# It returns the resources of the program with one of the type tokens given
+resources(doc, types) = {name: resource |
+	resource := doc.resources[name]
+	is_type(resource, types)
+}
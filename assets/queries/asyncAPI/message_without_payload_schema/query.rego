@@ -0,0 +1,25 @@
+package Cx
+
+import data.generic.asyncapi as asyncapi_lib
+import data.generic.common as common_lib
+
+CxPolicy[result] {
+	doc := input.document[i]
+	asyncapi_lib.check_asyncapi(doc) == "2.0"
+
+	operation := doc.channels[name][op]
+	asyncapi_lib.operations[op]
+
+	[path, message] := asyncapi_lib.get_messages(operation)[_]
+	not asyncapi_lib.is_unresolved_ref(message)
+	not common_lib.valid_key(message, "payload")
+
+	result := {
+		"documentId": doc.id,
+		"searchKey": sprintf("channels.{{%s}}.%s.%s", [name, op, concat(".", path)]),
+		"issueType": "MissingAttribute",
+		"keyExpectedValue": sprintf("channels.{{%s}}.%s.%s.payload should be defined", [name, op, concat(".", path)]),
+		"keyActualValue": sprintf("channels.{{%s}}.%s.%s.payload is undefined", [name, op, concat(".", path)]),
+		"searchLine": common_lib.build_search_line(array.concat(["channels", name, op], path), []),
+	}
+}
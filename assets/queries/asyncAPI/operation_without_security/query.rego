@@ -0,0 +1,25 @@
+package Cx
+
+import data.generic.asyncapi as asyncapi_lib
+import data.generic.common as common_lib
+
+CxPolicy[result] {
+	doc := input.document[i]
+	asyncapi_lib.check_asyncapi(doc) == "2.0"
+
+	channel := doc.channels[name]
+	operation := channel[op]
+	asyncapi_lib.operations[op]
+
+	not asyncapi_lib.has_security(operation)
+	not asyncapi_lib.secured_by_servers(doc, channel)
+
+	result := {
+		"documentId": doc.id,
+		"searchKey": sprintf("channels.{{%s}}.%s", [name, op]),
+		"issueType": "MissingAttribute",
+		"keyExpectedValue": sprintf("channels.{{%s}}.%s should require authentication", [name, op]),
+		"keyActualValue": sprintf("channels.{{%s}}.%s does not require authentication", [name, op]),
+		"searchLine": common_lib.build_search_line(["channels", name, op], []),
+	}
+}
@@ -0,0 +1,30 @@
+package Cx
+
+import data.generic.cicd as cicd_lib
+import data.generic.common as common_lib
+
+print_regex := `(?i)\b(echo|printf|print|write-host|write-output)\b`
+
+secret_regex := `\$\{\{\s*secrets\.|\$\{?[A-Za-z0-9_]*(TOKEN|PASSWORD|PASSWD|SECRET|API_KEY|PRIVATE_KEY)[A-Za-z0-9_]*\}?`
+
+CxPolicy[result] {
+	doc := input.document[i]
+	[script, path] := cicd_lib.scripts(doc)[_]
+	prints_secret(script)
+
+	result := {
+		"documentId": doc.id,
+		"searchKey": common_lib.concat_path(path),
+		"issueType": "IncorrectValue",
+		"keyExpectedValue": sprintf("%s should not print secrets", [common_lib.concat_path(path)]),
+		"keyActualValue": sprintf("%s prints a secret", [common_lib.concat_path(path)]),
+		"searchLine": common_lib.build_search_line(path, []),
+	}
+}
+
+prints_secret(script) {
+	line := split(script, "\n")[_]
+	regex.match(print_regex, line)
+	regex.match(secret_regex, line)
+	not contains(line, "::add-mask::")
+}
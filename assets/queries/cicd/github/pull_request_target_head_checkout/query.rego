@@ -0,0 +1,24 @@
+package Cx
+
+import data.generic.cicd as cicd_lib
+import data.generic.common as common_lib
+
+head_ref_regex := `github\.(event\.pull_request\.head\.(sha|ref)|head_ref)|refs/pull/`
+
+CxPolicy[result] {
+	doc := input.document[i]
+	cicd_lib.is_github_workflow(doc)
+	cicd_lib.triggers(doc)["pull_request_target"]
+	step := doc.jobs[job].steps[idx]
+	startswith(step.uses, "actions/checkout@")
+	regex.match(head_ref_regex, step["with"].ref)
+
+	result := {
+		"documentId": doc.id,
+		"searchKey": sprintf("jobs.{{%s}}.steps.with.ref", [job]),
+		"issueType": "IncorrectValue",
+		"keyExpectedValue": sprintf("jobs.{{%s}}.steps.with.ref should not check out the pull request head in a 'pull_request_target' workflow", [job]),
+		"keyActualValue": sprintf("jobs.{{%s}}.steps.with.ref checks out '%s' in a 'pull_request_target' workflow", [job, step["with"].ref]),
+		"searchLine": common_lib.build_search_line(["jobs", job, "steps", idx, "with", "ref"], []),
+	}
+}
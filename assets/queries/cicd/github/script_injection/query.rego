@@ -0,0 +1,21 @@
+package Cx
+
+import data.generic.cicd as cicd_lib
+import data.generic.common as common_lib
+
+CxPolicy[result] {
+	doc := input.document[i]
+	cicd_lib.is_github_workflow(doc)
+	[script, path] := cicd_lib.scripts(doc)[_]
+	expression := cicd_lib.expressions(script)[_]
+	regex.match(cicd_lib.untrusted_input_regex, expression)
+
+	result := {
+		"documentId": doc.id,
+		"searchKey": common_lib.concat_path(path),
+		"issueType": "IncorrectValue",
+		"keyExpectedValue": sprintf("%s should not interpolate untrusted input directly", [common_lib.concat_path(path)]),
+		"keyActualValue": sprintf("%s interpolates '%s'", [common_lib.concat_path(path), expression]),
+		"searchLine": common_lib.build_search_line(path, []),
+	}
+}
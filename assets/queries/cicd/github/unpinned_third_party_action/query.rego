@@ -0,0 +1,36 @@
+package Cx
+
+import data.generic.cicd as cicd_lib
+import data.generic.common as common_lib
+
+# actions maintained by GitHub
+trusted_owners := {"actions", "github"}
+
+CxPolicy[result] {
+	doc := input.document[i]
+	cicd_lib.is_github_workflow(doc)
+	[uses, path] := uses_references(doc)[_]
+	not cicd_lib.is_local_or_docker(uses)
+	not trusted_owners[lower(split(uses, "/")[0])]
+	not cicd_lib.is_pinned(uses)
+
+	result := {
+		"documentId": doc.id,
+		"searchKey": sprintf("%s=%s", [common_lib.concat_path(path), uses]),
+		"issueType": "IncorrectValue",
+		"keyExpectedValue": sprintf("'%s' should be pinned to a full length commit SHA", [uses]),
+		"keyActualValue": sprintf("'%s' is not pinned to a full length commit SHA", [uses]),
+		"searchLine": common_lib.build_search_line(path, []),
+	}
+}
+
+# actions used by steps and reusable workflows used by jobs
+uses_references(doc) = references {
+	references := {[uses, path] |
+		uses := doc.jobs[job].steps[idx].uses
+		path := ["jobs", job, "steps", idx, "uses"]
+	} | {[uses, path] |
+		uses := doc.jobs[job].uses
+		path := ["jobs", job, "uses"]
+	}
+}
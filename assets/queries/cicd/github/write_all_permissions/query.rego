@@ -0,0 +1,60 @@
+package Cx
+
+import data.generic.cicd as cicd_lib
+import data.generic.common as common_lib
+
+# scopes of the GITHUB_TOKEN granted by 'write-all'
+write_all_scopes := {
+	"actions",
+	"checks",
+	"contents",
+	"deployments",
+	"issues",
+	"packages",
+	"pages",
+	"pull-requests",
+	"repository-projects",
+	"security-events",
+	"statuses",
+}
+
+CxPolicy[result] {
+	doc := input.document[i]
+	cicd_lib.is_github_workflow(doc)
+	grant := write_all(doc.permissions)
+
+	result := {
+		"documentId": doc.id,
+		"searchKey": "permissions",
+		"issueType": "IncorrectValue",
+		"keyExpectedValue": "permissions should only grant the scopes needed by the workflow",
+		"keyActualValue": sprintf("permissions %s", [grant]),
+		"searchLine": common_lib.build_search_line(["permissions"], []),
+	}
+}
+
+CxPolicy[result] {
+	doc := input.document[i]
+	cicd_lib.is_github_workflow(doc)
+	grant := write_all(doc.jobs[job].permissions)
+
+	result := {
+		"documentId": doc.id,
+		"searchKey": sprintf("jobs.{{%s}}.permissions", [job]),
+		"issueType": "IncorrectValue",
+		"keyExpectedValue": sprintf("jobs.{{%s}}.permissions should only grant the scopes needed by the job", [job]),
+		"keyActualValue": sprintf("jobs.{{%s}}.permissions %s", [job, grant]),
+		"searchLine": common_lib.build_search_line(["jobs", job, "permissions"], []),
+	}
+}
+
+write_all(permissions) = "is set to 'write-all'" {
+	permissions == "write-all"
+}
+
+# a map granting 'write' to every scope of 'write-all', and to no scope with other access, is the same as 'write-all'
+write_all(permissions) = "grants 'write' to every scope" {
+	is_object(permissions)
+	count({scope | permissions[scope]; not startswith(scope, "_kics"); permissions[scope] != "write"}) == 0
+	count({scope | scope := write_all_scopes[_]; permissions[scope] == "write"}) == count(write_all_scopes)
+}
@@ -0,0 +1,21 @@
+package Cx
+
+import data.generic.common as common_lib
+import data.generic.dockercompose as compose_lib
+
+CxPolicy[result] {
+	doc := input.document[i]
+	compose_lib.check_compose(doc)
+	service := doc.services[name]
+	compose_lib.is_complete_service(service)
+	not compose_lib.has_cpu_limit(service)
+
+	result := {
+		"documentId": doc.id,
+		"searchKey": sprintf("services.{{%s}}", [name]),
+		"issueType": "MissingAttribute",
+		"keyExpectedValue": sprintf("services.{{%s}}.deploy.resources.limits.cpus should be defined", [name]),
+		"keyActualValue": sprintf("services.{{%s}} does not limit its CPUs", [name]),
+		"searchLine": common_lib.build_search_line(["services", name], []),
+	}
+}
@@ -0,0 +1,20 @@
+package Cx
+
+import data.generic.common as common_lib
+import data.generic.dockercompose as compose_lib
+
+CxPolicy[result] {
+	doc := input.document[i]
+	compose_lib.check_compose(doc)
+	capability := doc.services[name].cap_add[idx]
+	compose_lib.dangerous_capabilities[trim_prefix(upper(capability), "CAP_")]
+
+	result := {
+		"documentId": doc.id,
+		"searchKey": sprintf("services.{{%s}}.cap_add", [name]),
+		"issueType": "IncorrectValue",
+		"keyExpectedValue": sprintf("services.{{%s}}.cap_add should not include '%s'", [name, capability]),
+		"keyActualValue": sprintf("services.{{%s}}.cap_add includes '%s'", [name, capability]),
+		"searchLine": common_lib.build_search_line(["services", name, "cap_add", idx], []),
+	}
+}
@@ -0,0 +1,20 @@
+package Cx
+
+import data.generic.common as common_lib
+import data.generic.dockercompose as compose_lib
+
+CxPolicy[result] {
+	doc := input.document[i]
+	compose_lib.check_compose(doc)
+	volume := doc.services[name].volumes[idx]
+	compose_lib.is_docker_socket(volume)
+
+	result := {
+		"documentId": doc.id,
+		"searchKey": sprintf("services.{{%s}}.volumes", [name]),
+		"issueType": "IncorrectValue",
+		"keyExpectedValue": sprintf("services.{{%s}}.volumes should not mount the Docker socket", [name]),
+		"keyActualValue": sprintf("services.{{%s}}.volumes mounts the Docker socket '%s'", [name, compose_lib.volume_source(volume)]),
+		"searchLine": common_lib.build_search_line(["services", name, "volumes", idx], []),
+	}
+}
@@ -0,0 +1,21 @@
+package Cx
+
+import data.generic.common as common_lib
+import data.generic.dockercompose as compose_lib
+
+CxPolicy[result] {
+	doc := input.document[i]
+	compose_lib.check_compose(doc)
+	service := doc.services[name]
+	namespace := compose_lib.host_namespaces[_]
+	service[namespace] == "host"
+
+	result := {
+		"documentId": doc.id,
+		"searchKey": sprintf("services.{{%s}}.%s", [name, namespace]),
+		"issueType": "IncorrectValue",
+		"keyExpectedValue": sprintf("services.{{%s}}.%s should not be set to 'host'", [name, namespace]),
+		"keyActualValue": sprintf("services.{{%s}}.%s is set to 'host'", [name, namespace]),
+		"searchLine": common_lib.build_search_line(["services", name, namespace], []),
+	}
+}
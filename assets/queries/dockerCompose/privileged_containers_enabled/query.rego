@@ -0,0 +1,20 @@
+package Cx
+
+import data.generic.common as common_lib
+import data.generic.dockercompose as compose_lib
+
+CxPolicy[result] {
+	doc := input.document[i]
+	compose_lib.check_compose(doc)
+	service := doc.services[name]
+	service.privileged == true
+
+	result := {
+		"documentId": doc.id,
+		"searchKey": sprintf("services.{{%s}}.privileged", [name]),
+		"issueType": "IncorrectValue",
+		"keyExpectedValue": sprintf("services.{{%s}}.privileged should be set to false", [name]),
+		"keyActualValue": sprintf("services.{{%s}}.privileged is set to true", [name]),
+		"searchLine": common_lib.build_search_line(["services", name, "privileged"], []),
+	}
+}
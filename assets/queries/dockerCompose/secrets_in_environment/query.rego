@@ -0,0 +1,34 @@
+package Cx
+
+import data.generic.common as common_lib
+import data.generic.dockercompose as compose_lib
+
+secret_key_regex := `(?i)(passw(or)?d|secret|token|api_?key|access_?key|private_?key|credential)`
+
+CxPolicy[result] {
+	doc := input.document[i]
+	compose_lib.check_compose(doc)
+	[key, value, path] := compose_lib.environment(doc.services[name])[_]
+	regex.match(secret_key_regex, key)
+	not endswith(upper(key), "_FILE")
+	is_literal_secret(value)
+
+	result := {
+		"documentId": doc.id,
+		"searchKey": sprintf("services.{{%s}}.environment.%s", [name, key]),
+		"issueType": "IncorrectValue",
+		"keyExpectedValue": sprintf("services.{{%s}}.environment.%s should not be defined as a literal value", [name, key]),
+		"keyActualValue": sprintf("services.{{%s}}.environment.%s is defined as a literal value", [name, key]),
+		"searchLine": common_lib.build_search_line(array.concat(["services", name, "environment"], path), []),
+	}
+}
+
+is_literal_secret(value) {
+	is_string(value)
+	value != ""
+	not compose_lib.is_interpolated(value)
+}
+
+is_literal_secret(value) {
+	is_number(value)
+}
@@ -1,7 +1,10 @@
 package Cx
 
+import data.generic.dockerfile as dockerLib
+
 CxPolicy[result] {
 	resource := input.document[i].command[name]
+	dockerLib.isFinalStage(input.document[i], name)
 	userCmd := [x | resource[j].Cmd == "user"; x := resource[j]]
 	userCmd[minus(count(userCmd), 1)].Value[0] == "root"
 
@@ -13,3 +16,23 @@ CxPolicy[result] {
 		"keyActualValue": "Last User is root",
 	}
 }
+
+CxPolicy[result] {
+	resource := input.document[i].command[name]
+	dockerLib.isFinalStage(input.document[i], name)
+	not hasUserInstruction(resource)
+	input.document[i].stages[name].User == "root"
+
+	result := {
+		"documentId": input.document[i].id,
+		"searchKey": sprintf("FROM={{%s}}", [name]),
+		"issueType": "IncorrectValue",
+		"keyExpectedValue": "Last User isn't root",
+		"keyActualValue": "Last User is root, inherited from the base stage",
+	}
+}
+
+hasUserInstruction(resource) {
+	some j
+	resource[j].Cmd == "user"
+}
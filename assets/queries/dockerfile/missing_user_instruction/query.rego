@@ -1,10 +1,14 @@
 package Cx
 
+import data.generic.dockerfile as dockerLib
+
 CxPolicy[result] {
 	resource := input.document[i].command[name]
 
 	not name == "scratch"
+	dockerLib.isFinalStage(input.document[i], name)
 	not hasUserInstruction(resource)
+	input.document[i].stages[name].User == ""
 
 	result := {
 		"documentId": input.document[i].id,
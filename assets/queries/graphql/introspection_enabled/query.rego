@@ -0,0 +1,17 @@
+package Cx
+
+import data.generic.common as common_lib
+
+CxPolicy[result] {
+	doc := input.document[i]
+	doc.supergraph.introspection == true
+
+	result := {
+		"documentId": doc.id,
+		"searchKey": "supergraph.introspection",
+		"issueType": "IncorrectValue",
+		"keyExpectedValue": "supergraph.introspection should be set to false",
+		"keyActualValue": "supergraph.introspection is set to true",
+		"searchLine": common_lib.build_search_line(["supergraph", "introspection"], []),
+	}
+}
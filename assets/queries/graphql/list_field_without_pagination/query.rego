@@ -0,0 +1,24 @@
+package Cx
+
+import data.generic.common as common_lib
+import data.generic.graphql as graphql_lib
+
+CxPolicy[result] {
+	doc := input.document[i]
+	objectType := doc.types[typeName]
+	objectType.kind == {"OBJECT", "INTERFACE"}[_]
+
+	field := objectType.fields[name]
+	field.list == true
+	graphql_lib.is_composite_type(doc, field.namedType)
+	not graphql_lib.has_pagination_argument(field)
+
+	result := {
+		"documentId": doc.id,
+		"searchKey": sprintf("types.{{%s}}.fields.{{%s}}", [typeName, name]),
+		"issueType": "MissingAttribute",
+		"keyExpectedValue": sprintf("'%s.%s' should have pagination arguments", [typeName, name]),
+		"keyActualValue": sprintf("'%s.%s' returns an unbounded list of '%s'", [typeName, name, field.namedType]),
+		"searchLine": common_lib.build_search_line(["types", typeName, "fields", name], []),
+	}
+}
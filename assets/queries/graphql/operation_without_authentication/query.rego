@@ -0,0 +1,25 @@
+package Cx
+
+import data.generic.common as common_lib
+import data.generic.graphql as graphql_lib
+
+CxPolicy[result] {
+	doc := input.document[i]
+	not graphql_lib.has_access_control(object.get(doc, "schema", {}))
+
+	typeName := graphql_lib.root_types(doc)[_]
+	rootType := doc.types[typeName]
+	not graphql_lib.has_access_control(rootType)
+
+	field := rootType.fields[name]
+	not graphql_lib.has_access_control(field)
+
+	result := {
+		"documentId": doc.id,
+		"searchKey": sprintf("types.{{%s}}.fields.{{%s}}", [typeName, name]),
+		"issueType": "MissingAttribute",
+		"keyExpectedValue": sprintf("'%s.%s' should require authentication", [typeName, name]),
+		"keyActualValue": sprintf("'%s.%s' does not require authentication", [typeName, name]),
+		"searchLine": common_lib.build_search_line(["types", typeName, "fields", name], []),
+	}
+}
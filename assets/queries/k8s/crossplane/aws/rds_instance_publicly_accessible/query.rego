@@ -0,0 +1,24 @@
+package Cx
+
+import data.generic.common as common_lib
+import data.generic.k8s as k8sLib
+
+rds_kinds := {
+	"RDSInstance": ["database.aws.crossplane.io"],
+	"Instance": ["rds.aws.upbound.io"],
+}
+
+CxPolicy[result] {
+	document := input.document[i]
+	managed := k8sLib.crossplaneResources(document, kind, rds_kinds[kind])[_]
+	managed.resource.spec.forProvider.publiclyAccessible == true
+
+	result := {
+		"documentId": document.id,
+		"searchKey": sprintf("%s.spec.forProvider.publiclyAccessible", [managed.searchKey]),
+		"issueType": "IncorrectValue",
+		"keyExpectedValue": "spec.forProvider.publiclyAccessible should be set to false",
+		"keyActualValue": "spec.forProvider.publiclyAccessible is set to true",
+		"searchLine": common_lib.build_search_line(managed.path, ["spec", "forProvider", "publiclyAccessible"]),
+	}
+}
@@ -0,0 +1,39 @@
+package Cx
+
+import data.generic.common as common_lib
+import data.generic.k8s as k8sLib
+
+rds_kinds := {
+	"RDSInstance": ["database.aws.crossplane.io"],
+	"Instance": ["rds.aws.upbound.io"],
+}
+
+CxPolicy[result] {
+	document := input.document[i]
+	managed := k8sLib.crossplaneResources(document, kind, rds_kinds[kind])[_]
+	not common_lib.valid_key(managed.resource.spec.forProvider, "storageEncrypted")
+
+	result := {
+		"documentId": document.id,
+		"searchKey": sprintf("%s.spec.forProvider", [managed.searchKey]),
+		"issueType": "MissingAttribute",
+		"keyExpectedValue": "spec.forProvider.storageEncrypted should be defined and set to true",
+		"keyActualValue": "spec.forProvider.storageEncrypted is undefined",
+		"searchLine": common_lib.build_search_line(managed.path, ["spec", "forProvider"]),
+	}
+}
+
+CxPolicy[result] {
+	document := input.document[i]
+	managed := k8sLib.crossplaneResources(document, kind, rds_kinds[kind])[_]
+	managed.resource.spec.forProvider.storageEncrypted == false
+
+	result := {
+		"documentId": document.id,
+		"searchKey": sprintf("%s.spec.forProvider.storageEncrypted", [managed.searchKey]),
+		"issueType": "IncorrectValue",
+		"keyExpectedValue": "spec.forProvider.storageEncrypted should be set to true",
+		"keyActualValue": "spec.forProvider.storageEncrypted is set to false",
+		"searchLine": common_lib.build_search_line(managed.path, ["spec", "forProvider", "storageEncrypted"]),
+	}
+}
@@ -0,0 +1,27 @@
+package Cx
+
+import data.generic.common as common_lib
+import data.generic.k8s as k8sLib
+
+public_acls := {"public-read", "public-read-write"}
+
+bucket_kinds := {
+	"Bucket": ["s3.aws.crossplane.io"],
+	"BucketACL": ["s3.aws.upbound.io"],
+}
+
+CxPolicy[result] {
+	document := input.document[i]
+	managed := k8sLib.crossplaneResources(document, kind, bucket_kinds[kind])[_]
+	acl := managed.resource.spec.forProvider.acl
+	public_acls[acl]
+
+	result := {
+		"documentId": document.id,
+		"searchKey": sprintf("%s.spec.forProvider.acl", [managed.searchKey]),
+		"issueType": "IncorrectValue",
+		"keyExpectedValue": "spec.forProvider.acl should not be 'public-read' or 'public-read-write'",
+		"keyActualValue": sprintf("spec.forProvider.acl is '%s'", [acl]),
+		"searchLine": common_lib.build_search_line(managed.path, ["spec", "forProvider", "acl"]),
+	}
+}
@@ -0,0 +1,22 @@
+package Cx
+
+import data.generic.common as common_lib
+import data.generic.k8s as k8sLib
+
+CxPolicy[result] {
+	document := input.document[i]
+	managed := k8sLib.crossplaneResources(document, "SecurityGroup", ["ec2.aws.crossplane.io"])[_]
+	rule := managed.resource.spec.forProvider.ingress[idx]
+	rule.ipRanges[_].cidrIp == "0.0.0.0/0"
+	rule.fromPort <= 22
+	rule.toPort >= 22
+
+	result := {
+		"documentId": document.id,
+		"searchKey": sprintf("%s.spec.forProvider.ingress", [managed.searchKey]),
+		"issueType": "IncorrectValue",
+		"keyExpectedValue": sprintf("spec.forProvider.ingress[%d] should not allow port 22 from 0.0.0.0/0", [idx]),
+		"keyActualValue": sprintf("spec.forProvider.ingress[%d] allows port 22 from 0.0.0.0/0", [idx]),
+		"searchLine": common_lib.build_search_line(managed.path, ["spec", "forProvider", "ingress", idx]),
+	}
+}
@@ -0,0 +1,19 @@
+package Cx
+
+import data.generic.common as common_lib
+import data.generic.k8s as k8sLib
+
+CxPolicy[result] {
+	document := input.document[i]
+	managed := k8sLib.crossplaneResources(document, "Account", ["storage.azure.upbound.io"])[_]
+	managed.resource.spec.forProvider.enableHttpsTrafficOnly == false
+
+	result := {
+		"documentId": document.id,
+		"searchKey": sprintf("%s.spec.forProvider.enableHttpsTrafficOnly", [managed.searchKey]),
+		"issueType": "IncorrectValue",
+		"keyExpectedValue": "spec.forProvider.enableHttpsTrafficOnly should be set to true",
+		"keyActualValue": "spec.forProvider.enableHttpsTrafficOnly is set to false",
+		"searchLine": common_lib.build_search_line(managed.path, ["spec", "forProvider", "enableHttpsTrafficOnly"]),
+	}
+}
@@ -0,0 +1,22 @@
+package Cx
+
+import data.generic.common as common_lib
+import data.generic.k8s as k8sLib
+
+public_members := {"allUsers", "allAuthenticatedUsers"}
+
+CxPolicy[result] {
+	document := input.document[i]
+	managed := k8sLib.crossplaneResources(document, "BucketIAMMember", ["storage.gcp.upbound.io"])[_]
+	member := managed.resource.spec.forProvider.member
+	public_members[member]
+
+	result := {
+		"documentId": document.id,
+		"searchKey": sprintf("%s.spec.forProvider.member", [managed.searchKey]),
+		"issueType": "IncorrectValue",
+		"keyExpectedValue": "spec.forProvider.member should not be 'allUsers' or 'allAuthenticatedUsers'",
+		"keyActualValue": sprintf("spec.forProvider.member is '%s'", [member]),
+		"searchLine": common_lib.build_search_line(managed.path, ["spec", "forProvider", "member"]),
+	}
+}
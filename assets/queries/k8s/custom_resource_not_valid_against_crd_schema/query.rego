@@ -0,0 +1,21 @@
+package Cx
+
+import data.generic.common as common_lib
+
+CxPolicy[result] {
+	document := input.document[i]
+	crdError := document._kics_crd_errors[_]
+
+	result := {
+		"documentId": document.id,
+		"searchKey": search_key(document.metadata.name, crdError),
+		"issueType": "IncorrectValue",
+		"keyExpectedValue": sprintf("%s should be valid against the schema of the CustomResourceDefinition", [crdError.field]),
+		"keyActualValue": crdError.message,
+		"searchLine": common_lib.build_search_line(crdError.path, []),
+	}
+}
+
+search_key(name, crdError) = sprintf("metadata.name={{%s}}", [name]) {
+	count(crdError.path) == 0
+} else = sprintf("metadata.name={{%s}}.%s", [name, crdError.field])
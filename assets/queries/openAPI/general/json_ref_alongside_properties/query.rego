@@ -10,6 +10,7 @@ CxPolicy[result] {
 
 	[path, value] := walk(doc)
 	common_lib.valid_key(value, "$ref")
+	not openapi_lib.is_resolved_ref(value)
 	count(value) > 1
 
 	result := {
@@ -0,0 +1,20 @@
+package Cx
+
+import data.generic.common as common_lib
+import data.generic.pulumi as pulumi_lib
+
+CxPolicy[result] {
+	doc := input.document[i]
+	pulumi_lib.check_program(doc)
+	resource := pulumi_lib.resources(doc, ["aws:rds:Instance"])[name]
+	resource.properties.publiclyAccessible == true
+
+	result := {
+		"documentId": doc.id,
+		"searchKey": sprintf("resources.{{%s}}.properties.publiclyAccessible", [name]),
+		"issueType": "IncorrectValue",
+		"keyExpectedValue": sprintf("resources.{{%s}}.properties.publiclyAccessible should be set to false", [name]),
+		"keyActualValue": sprintf("resources.{{%s}}.properties.publiclyAccessible is set to true", [name]),
+		"searchLine": common_lib.build_search_line(["resources", name, "properties", "publiclyAccessible"], []),
+	}
+}
@@ -0,0 +1,36 @@
+package Cx
+
+import data.generic.common as common_lib
+import data.generic.pulumi as pulumi_lib
+
+CxPolicy[result] {
+	doc := input.document[i]
+	pulumi_lib.check_program(doc)
+	resource := pulumi_lib.resources(doc, ["aws:rds:Instance"])[name]
+	not common_lib.valid_key(resource.properties, "storageEncrypted")
+
+	result := {
+		"documentId": doc.id,
+		"searchKey": sprintf("resources.{{%s}}", [name]),
+		"issueType": "MissingAttribute",
+		"keyExpectedValue": sprintf("resources.{{%s}}.properties.storageEncrypted should be defined and set to true", [name]),
+		"keyActualValue": sprintf("resources.{{%s}}.properties.storageEncrypted is undefined", [name]),
+		"searchLine": common_lib.build_search_line(["resources", name], []),
+	}
+}
+
+CxPolicy[result] {
+	doc := input.document[i]
+	pulumi_lib.check_program(doc)
+	resource := pulumi_lib.resources(doc, ["aws:rds:Instance"])[name]
+	resource.properties.storageEncrypted == false
+
+	result := {
+		"documentId": doc.id,
+		"searchKey": sprintf("resources.{{%s}}.properties.storageEncrypted", [name]),
+		"issueType": "IncorrectValue",
+		"keyExpectedValue": sprintf("resources.{{%s}}.properties.storageEncrypted should be set to true", [name]),
+		"keyActualValue": sprintf("resources.{{%s}}.properties.storageEncrypted is set to false", [name]),
+		"searchLine": common_lib.build_search_line(["resources", name, "properties", "storageEncrypted"], []),
+	}
+}
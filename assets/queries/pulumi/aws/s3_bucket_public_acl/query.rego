@@ -0,0 +1,23 @@
+package Cx
+
+import data.generic.common as common_lib
+import data.generic.pulumi as pulumi_lib
+
+public_acls := {"public-read", "public-read-write"}
+
+CxPolicy[result] {
+	doc := input.document[i]
+	pulumi_lib.check_program(doc)
+	resource := pulumi_lib.resources(doc, ["aws:s3:Bucket", "aws:s3:BucketAclV2"])[name]
+	acl := resource.properties.acl
+	public_acls[acl]
+
+	result := {
+		"documentId": doc.id,
+		"searchKey": sprintf("resources.{{%s}}.properties.acl", [name]),
+		"issueType": "IncorrectValue",
+		"keyExpectedValue": sprintf("resources.{{%s}}.properties.acl should not be 'public-read' or 'public-read-write'", [name]),
+		"keyActualValue": sprintf("resources.{{%s}}.properties.acl is '%s'", [name, acl]),
+		"searchLine": common_lib.build_search_line(["resources", name, "properties", "acl"], []),
+	}
+}
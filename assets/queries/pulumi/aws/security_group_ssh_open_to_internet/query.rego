@@ -0,0 +1,23 @@
+package Cx
+
+import data.generic.common as common_lib
+import data.generic.pulumi as pulumi_lib
+
+CxPolicy[result] {
+	doc := input.document[i]
+	pulumi_lib.check_program(doc)
+	resource := pulumi_lib.resources(doc, ["aws:ec2:SecurityGroup"])[name]
+	rule := resource.properties.ingress[idx]
+	rule.cidrBlocks[_] == "0.0.0.0/0"
+	rule.fromPort <= 22
+	rule.toPort >= 22
+
+	result := {
+		"documentId": doc.id,
+		"searchKey": sprintf("resources.{{%s}}.properties.ingress", [name]),
+		"issueType": "IncorrectValue",
+		"keyExpectedValue": sprintf("resources.{{%s}}.properties.ingress[%d] should not allow port 22 from 0.0.0.0/0", [name, idx]),
+		"keyActualValue": sprintf("resources.{{%s}}.properties.ingress[%d] allows port 22 from 0.0.0.0/0", [name, idx]),
+		"searchLine": common_lib.build_search_line(["resources", name, "properties", "ingress", idx], []),
+	}
+}
@@ -0,0 +1,20 @@
+package Cx
+
+import data.generic.common as common_lib
+import data.generic.pulumi as pulumi_lib
+
+CxPolicy[result] {
+	doc := input.document[i]
+	pulumi_lib.check_program(doc)
+	resource := pulumi_lib.resources(doc, ["azure-native:storage:StorageAccount", "azure:storage:Account"])[name]
+	resource.properties.enableHttpsTrafficOnly == false
+
+	result := {
+		"documentId": doc.id,
+		"searchKey": sprintf("resources.{{%s}}.properties.enableHttpsTrafficOnly", [name]),
+		"issueType": "IncorrectValue",
+		"keyExpectedValue": sprintf("resources.{{%s}}.properties.enableHttpsTrafficOnly should be set to true", [name]),
+		"keyActualValue": sprintf("resources.{{%s}}.properties.enableHttpsTrafficOnly is set to false", [name]),
+		"searchLine": common_lib.build_search_line(["resources", name, "properties", "enableHttpsTrafficOnly"], []),
+	}
+}
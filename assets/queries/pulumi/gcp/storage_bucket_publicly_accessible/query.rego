@@ -0,0 +1,40 @@
+package Cx
+
+import data.generic.common as common_lib
+import data.generic.pulumi as pulumi_lib
+
+public_members := {"allUsers", "allAuthenticatedUsers"}
+
+CxPolicy[result] {
+	doc := input.document[i]
+	pulumi_lib.check_program(doc)
+	resource := pulumi_lib.resources(doc, ["gcp:storage:BucketIAMMember"])[name]
+	member := resource.properties.member
+	public_members[member]
+
+	result := {
+		"documentId": doc.id,
+		"searchKey": sprintf("resources.{{%s}}.properties.member", [name]),
+		"issueType": "IncorrectValue",
+		"keyExpectedValue": sprintf("resources.{{%s}}.properties.member should not be 'allUsers' or 'allAuthenticatedUsers'", [name]),
+		"keyActualValue": sprintf("resources.{{%s}}.properties.member is '%s'", [name, member]),
+		"searchLine": common_lib.build_search_line(["resources", name, "properties", "member"], []),
+	}
+}
+
+CxPolicy[result] {
+	doc := input.document[i]
+	pulumi_lib.check_program(doc)
+	resource := pulumi_lib.resources(doc, ["gcp:storage:BucketIAMBinding"])[name]
+	member := resource.properties.members[idx]
+	public_members[member]
+
+	result := {
+		"documentId": doc.id,
+		"searchKey": sprintf("resources.{{%s}}.properties.members", [name]),
+		"issueType": "IncorrectValue",
+		"keyExpectedValue": sprintf("resources.{{%s}}.properties.members should not contain 'allUsers' or 'allAuthenticatedUsers'", [name]),
+		"keyActualValue": sprintf("resources.{{%s}}.properties.members contains '%s'", [name, member]),
+		"searchLine": common_lib.build_search_line(["resources", name, "properties", "members"], []),
+	}
+}
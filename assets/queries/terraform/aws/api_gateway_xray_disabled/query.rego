@@ -12,6 +12,7 @@ CxPolicy[result] {
 		"issueType": "IncorrectValue",
 		"keyExpectedValue": sprintf("'aws_api_gateway_stage[%s].xray_tracing_enabled' is true", [name]),
 		"keyActualValue": sprintf("'aws_api_gateway_stage[%s].xray_tracing_enabled' is false", [name]),
+		"remediation": {"before": "false", "after": "true"},
 	}
 }
 
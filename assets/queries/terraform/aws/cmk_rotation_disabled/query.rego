@@ -29,6 +29,7 @@ CxPolicy[result] {
 		"issueType": "IncorrectValue",
 		"keyExpectedValue": sprintf("aws_kms_key[%s].enable_key_rotation is set to true", [name]),
 		"keyActualValue": sprintf("aws_kms_key[%s].enable_key_rotation is set to false", [name]),
+		"remediation": {"before": "false", "after": "true"},
 	}
 }
 
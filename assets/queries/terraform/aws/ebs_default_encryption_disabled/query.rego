@@ -10,5 +10,6 @@ CxPolicy[result] {
 		"issueType": "IncorrectValue",
 		"keyExpectedValue": "'aws_ebs_encryption_by_default.encrypted' is true",
 		"keyActualValue": "'aws_ebs_encryption_by_default.encrypted' is false",
+		"remediation": {"before": "false", "after": "true"},
 	}
 }
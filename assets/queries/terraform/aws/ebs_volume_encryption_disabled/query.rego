@@ -27,5 +27,6 @@ CxPolicy[result] {
 		"issueType": "IncorrectValue",
 		"keyExpectedValue": "One of 'aws_ebs_volume.encrypted' is 'true'",
 		"keyActualValue": "One of 'aws_ebs_volume.encrypted' is 'false'",
+		"remediation": {"before": "false", "after": "true"},
 	}
 }
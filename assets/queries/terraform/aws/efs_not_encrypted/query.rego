@@ -12,6 +12,7 @@ CxPolicy[result] {
 		"issueType": "IncorrectValue",
 		"keyExpectedValue": sprintf("aws_efs_file_system[%s].encrypted' is true", [name]),
 		"keyActualValue": sprintf("aws_efs_file_system[%s].encrypted' is false", [name]),
+		"remediation": {"before": "false", "after": "true"},
 	}
 }
 
@@ -12,6 +12,7 @@ CxPolicy[result] {
 		"issueType": "IncorrectValue",
 		"keyExpectedValue": "'acl' is equal 'private'",
 		"keyActualValue": sprintf("'acl' is equal '%s'", [resource.acl]),
+		"remediation": {"before": sprintf("\"%s\"", [resource.acl]), "after": "\"private\""},
 		"searchLine": common_lib.build_search_line(["resource", "aws_s3_bucket", name, "acl"], []),
 	}
 }
@@ -28,6 +29,7 @@ CxPolicy[result] {
 		"issueType": "IncorrectValue",
 		"keyExpectedValue": "'acl' is equal 'private'",
 		"keyActualValue": sprintf("'acl' is equal '%s'", [module[keyToCheck]]),
+		"remediation": {"before": sprintf("\"%s\"", [module[keyToCheck]]), "after": "\"private\""},
 		"searchLine": common_lib.build_search_line(["module", name, "acl"], []),
 	}
 }
@@ -2,33 +2,58 @@ package flags
 
 // Flags constants for scan
 const (
-	BomFlag                = "bom"
-	CloudProviderFlag      = "cloud-provider"
-	ConfigFlag             = "config"
-	DisableCISDescFlag     = "disable-cis-descriptions"
-	DisableFullDescFlag    = "disable-full-descriptions"
-	ExcludeCategoriesFlag  = "exclude-categories"
-	ExcludePathsFlag       = "exclude-paths"
-	ExcludeQueriesFlag     = "exclude-queries"
-	ExcludeResultsFlag     = "exclude-results"
-	ExcludeSeveritiesFlag  = "exclude-severities"
-	IncludeQueriesFlag     = "include-queries"
-	InputDataFlag          = "input-data"
-	FailOnFlag             = "fail-on"
-	IgnoreOnExitFlag       = "ignore-on-exit"
-	MinimalUIFlag          = "minimal-ui"
-	NoProgressFlag         = "no-progress"
-	OutputNameFlag         = "output-name"
-	OutputPathFlag         = "output-path"
-	PathFlag               = "path"
-	PayloadPathFlag        = "payload-path"
-	PreviewLinesFlag       = "preview-lines"
-	QueriesPath            = "queries-path"
-	LibrariesPath          = "libraries-path"
-	ReportFormatsFlag      = "report-formats"
-	TypeFlag               = "type"
-	QueryExecTimeoutFlag   = "timeout"
-	LineInfoPayloadFlag    = "payload-lines"
-	DisableSecretsFlag     = "disable-secrets"
-	SecretsRegexesPathFlag = "secrets-regexes-path" //nolint:gosec
+	AnsibleVaultPasswordFileFlag = "ansible-vault-password-file" //nolint:gosec
+	BomFlag                      = "bom"
+	CloudProviderFlag            = "cloud-provider"
+	ConfigFlag                   = "config"
+	CRDPathFlag                  = "crd-path"
+	DisableCISDescFlag           = "disable-cis-descriptions"
+	DisableFullDescFlag          = "disable-full-descriptions"
+	DockerBuildArgFlag           = "docker-build-arg"
+	ExcludeCategoriesFlag        = "exclude-categories"
+	ExcludePathsFlag             = "exclude-paths"
+	ExcludeQueriesFlag           = "exclude-queries"
+	ExcludeResultsFlag           = "exclude-results"
+	ExcludeSeveritiesFlag        = "exclude-severities"
+	IncludeQueriesFlag           = "include-queries"
+	InputDataFlag                = "input-data"
+	FailOnFlag                   = "fail-on"
+	IgnoreOnExitFlag             = "ignore-on-exit"
+	MarkdownLinkTemplateFlag     = "markdown-link-template"
+	MarkdownMaxSizeFlag          = "markdown-max-size"
+	MinimalUIFlag                = "minimal-ui"
+	NoProgressFlag               = "no-progress"
+	OutputNameFlag               = "output-name"
+	OutputPathFlag               = "output-path"
+	PathFlag                     = "path"
+	PayloadPathFlag              = "payload-path"
+	PreviewLinesFlag             = "preview-lines"
+	QueriesPath                  = "queries-path"
+	LibrariesPath                = "libraries-path"
+	ReportFormatsFlag            = "report-formats"
+	ReportTemplateFlag           = "report-template"
+	TypeFlag                     = "type"
+	QueryExecTimeoutFlag         = "timeout"
+	LineInfoPayloadFlag          = "payload-lines"
+	DisableSecretsFlag           = "disable-secrets"
+	SecretsRegexesPathFlag       = "secrets-regexes-path" //nolint:gosec
+	ShowSecretsFlag              = "show-secrets"
+	SecretsAllFilesFlag          = "secrets-all-files"      //nolint:gosec
+	SecretsDecodingDepthFlag     = "secrets-decoding-depth" //nolint:gosec
+	SecretsIncludePathsFlag      = "secrets-include-paths"  //nolint:gosec
+	SecretsExcludePathsFlag      = "secrets-exclude-paths"  //nolint:gosec
+	SecretsHistoryFlag           = "secrets-history"        //nolint:gosec
+	SecretsHistoryRangeFlag      = "secrets-history-range"  //nolint:gosec
+	SecretsHistorySinceFlag      = "secrets-history-since"  //nolint:gosec
+	StdinFilenameFlag            = "stdin-filename"
+	FetchCachePathFlag           = "fetch-cache-path"
+	FetchMirrorFlag              = "fetch-mirror"
+	FetchRequireChecksumFlag     = "fetch-require-checksum"
+	InsecureFetchFlag            = "insecure-fetch"
+	OfflineFlag                  = "offline"
+	ArchiveDepthFlag             = "archive-depth"
+	ArchiveMaxFilesFlag          = "archive-max-files"
+	ArchiveMaxSizeFlag           = "archive-max-size"
+	StdinTypeFlag                = "stdin-type"
+	StdinMaxSizeFlag             = "stdin-max-size"
 )
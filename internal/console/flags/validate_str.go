@@ -9,7 +9,8 @@ import (
 )
 
 var validStrEnums = map[string]map[string]string{
-	LogLevelFlag: convertSliceToDummyMap(constants.AvailableLogLevels),
+	LogLevelFlag:  convertSliceToDummyMap(constants.AvailableLogLevels),
+	StdinTypeFlag: constants.AvailablePlatforms,
 }
 
 func validateStrEnum(flagName string) error {
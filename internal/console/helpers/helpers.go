@@ -26,12 +26,33 @@ const (
 	wordWrapCount = 5
 )
 
-var reportGenerators = map[string]func(path, filename string, body interface{}) error{
-	"json":   report.PrintJSONReport,
-	"sarif":  report.PrintSarifReport,
-	"html":   report.PrintHTMLReport,
-	"glsast": report.PrintGitlabSASTReport,
-	"pdf":    report.PrintPdfReport,
+// ReportOptions are the options of the report formats that can be configured
+type ReportOptions struct {
+	Markdown report.MarkdownOptions
+}
+
+// reportGenerator prints the body on the report format, with its options
+type reportGenerator func(path, filename string, body interface{}, opts *ReportOptions) error
+
+var reportGenerators = map[string]reportGenerator{
+	"json":          withoutOptions(report.PrintJSONReport),
+	"sarif":         withoutOptions(report.PrintSarifReport),
+	"html":          withoutOptions(report.PrintHTMLReport),
+	"glsast":        withoutOptions(report.PrintGitlabSASTReport),
+	"glcodequality": withoutOptions(report.PrintGitlabCodeQualityReport),
+	"sonarqube":     withoutOptions(report.PrintSonarQubeReport),
+	"checkstyle":    withoutOptions(report.PrintCheckstyleReport),
+	"pdf":           withoutOptions(report.PrintPdfReport),
+	"markdown": func(path, filename string, body interface{}, opts *ReportOptions) error {
+		return report.PrintMarkdownReport(path, filename, body, opts.Markdown)
+	},
+}
+
+// withoutOptions returns the generator of a report format that has no options
+func withoutOptions(printReport func(path, filename string, body interface{}) error) reportGenerator {
+	return func(path, filename string, body interface{}, opts *ReportOptions) error {
+		return printReport(path, filename, body)
+	}
 }
 
 // Printer wil print console output with colors
@@ -134,6 +155,9 @@ func printFiles(query *model.QueryResult, printer *Printer) {
 	for fileIdx := range query.Files {
 		fmt.Printf("\t%s %s:%s\n", printer.PrintBySev(fmt.Sprintf("[%d]:", fileIdx+1), string(query.Severity)),
 			query.Files[fileIdx].FileName, printer.Success.Sprint(query.Files[fileIdx].Line))
+		if query.Files[fileIdx].ConstructPath != "" {
+			fmt.Printf("\t     CDK construct: %s\n", query.Files[fileIdx].ConstructPath)
+		}
 		if !printer.minimal {
 			fmt.Println()
 			for _, line := range query.Files[fileIdx].VulnLines {
@@ -207,7 +231,8 @@ func FileAnalyzer(path string) (string, error) {
 }
 
 // GenerateReport execute each report function to generate report
-func GenerateReport(path, filename string, body interface{}, formats []string, proBarBuilder progress.PbBuilder) error {
+func GenerateReport(path, filename string, body interface{}, formats []string, opts *ReportOptions,
+	proBarBuilder progress.PbBuilder) error {
 	log.Debug().Msgf("helpers.GenerateReport()")
 	metrics.Metric.Start("generate_report")
 
@@ -219,7 +244,7 @@ func GenerateReport(path, filename string, body interface{}, formats []string, p
 
 	for _, format := range formats {
 		format = strings.ToLower(format)
-		if err = reportGenerators[format](path, filename, body); err != nil {
+		if err = reportGenerators[format](path, filename, body, opts); err != nil {
 			log.Error().Msgf("Failed to generate %s report", format)
 			break
 		}
@@ -303,7 +303,7 @@ func TestHelpers_GenerateReport(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := GenerateReport(tt.args.path, tt.args.filename, tt.args.body, tt.args.formats, progress.PbBuilder{})
+			err := GenerateReport(tt.args.path, tt.args.filename, tt.args.body, tt.args.formats, &ReportOptions{}, progress.PbBuilder{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GenerateReport() = %v, wantErr = %v", err, tt.wantErr)
 			}
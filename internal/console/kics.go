@@ -46,6 +46,7 @@ func initialize(rootCmd *cobra.Command) error {
 	rootCmd.AddCommand(NewGenerateIDCmd())
 	rootCmd.AddCommand(scanCmd)
 	rootCmd.AddCommand(NewListPlatformsCmd())
+	rootCmd.AddCommand(NewReportCmd())
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
 
 	if err := flags.InitJSONFlags(
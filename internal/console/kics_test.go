@@ -147,6 +147,40 @@ func TestConsole_Execute(t *testing.T) { //nolint
 			wantErr: false,
 			remove:  "",
 		},
+		{
+			name: "test_kics_report_convert_cmd",
+			args: []string{"kics", "report", "convert",
+				filepath.FromSlash("../../e2e/fixtures/E2E_CLI_032_RESULT.json"),
+				"--report-formats", "sarif,markdown", "-o", "report-convert"},
+			wantErr: false,
+			remove:  "report-convert",
+		},
+		{
+			name: "test_kics_report_convert_unknown_format",
+			args: []string{"kics", "report", "convert",
+				filepath.FromSlash("../../e2e/fixtures/E2E_CLI_032_RESULT.json"),
+				"--report-formats", "unknown", "-o", "report-convert"},
+			wantErr: true,
+			remove:  "",
+		},
+		{
+			name: "test_kics_report_merge_cmd",
+			args: []string{"kics", "report", "merge",
+				filepath.FromSlash("../../e2e/fixtures/E2E_CLI_032_RESULT.json"),
+				filepath.FromSlash("../../e2e/fixtures/E2E_CLI_036_RESULT.json"),
+				"-o", filepath.FromSlash("report-merge/merged.json")},
+			wantErr: false,
+			remove:  "report-merge",
+		},
+		{
+			name: "test_kics_report_merge_not_kics_report",
+			args: []string{"kics", "report", "merge",
+				filepath.FromSlash("../../e2e/fixtures/E2E_CLI_032_RESULT.json"),
+				filepath.FromSlash("../../e2e/fixtures/schemas/result-sarif.json"),
+				"-o", filepath.FromSlash("report-merge/merged.json")},
+			wantErr: true,
+			remove:  "",
+		},
 		{
 			name: "test_kics_fail_without_scan",
 			args: []string{"kics", "--path", filepath.FromSlash("../../test/fixtures/tc-sim01/positive1.tf"),
@@ -0,0 +1,153 @@
+package console
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Checkmarx/kics/internal/console/flags"
+	consoleHelpers "github.com/Checkmarx/kics/internal/console/helpers"
+	internalPrinter "github.com/Checkmarx/kics/internal/console/printer"
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/Checkmarx/kics/pkg/progress"
+	"github.com/Checkmarx/kics/pkg/report"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultMergeOutput = "merged.json"
+	minMergeReports    = 2
+)
+
+// reportOptions are the flags of the report subcommands, they are not registered in the flags of the scan
+// since both commands share the names of the flags
+type reportOptions struct {
+	formats    []string
+	outputPath string
+	outputName string
+}
+
+// NewReportCmd creates a new instance of the report Command, which converts and merges the JSON reports of scans
+func NewReportCmd() *cobra.Command {
+	reportCmd := &cobra.Command{
+		Use:   "report",
+		Short: "Converts and merges the JSON results of scans",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := internalPrinter.SetupPrinter(cmd.InheritedFlags()); err != nil {
+				return errors.New(initError + err.Error())
+			}
+			return nil
+		},
+	}
+	reportCmd.AddCommand(newReportConvertCmd())
+	reportCmd.AddCommand(newReportMergeCmd())
+	return reportCmd
+}
+
+func newReportConvertCmd() *cobra.Command {
+	options := reportOptions{}
+	convertCmd := &cobra.Command{
+		Use:   "convert <results.json>",
+		Short: "Converts the JSON results of a scan to other report formats",
+		Example: "  kics report convert results.json --report-formats sarif,html\n" +
+			"  kics report convert results.json --report-formats all -o reports --output-name scan",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			summary, err := report.ReadJSONReport(args[0])
+			if err != nil {
+				return err
+			}
+			if options.outputPath == "" {
+				options.outputPath = filepath.Dir(args[0])
+			}
+			if options.outputName == "" {
+				options.outputName = strings.TrimSuffix(filepath.Base(args[0]), filepath.Ext(args[0]))
+			}
+			return writeReports(&summary, &options)
+		},
+	}
+	convertCmd.Flags().StringSliceVar(&options.formats, flags.ReportFormatsFlag, []string{"json"},
+		fmt.Sprintf("formats in which the results will be exported (%s)",
+			strings.Join(append([]string{"all"}, consoleHelpers.ListReportFormats()...), ", ")))
+	convertCmd.Flags().StringVarP(&options.outputPath, flags.OutputPathFlag, "o", "",
+		"directory path to store the reports (default: the directory of the results)")
+	convertCmd.Flags().StringVar(&options.outputName, flags.OutputNameFlag, "",
+		"name used on the reports (default: the name of the results without extension)")
+	return convertCmd
+}
+
+func newReportMergeCmd() *cobra.Command {
+	options := reportOptions{}
+	var output string
+	mergeCmd := &cobra.Command{
+		Use:   "merge <results.json>...",
+		Short: "Merges the JSON results of several scans, results found by more than one scan are kept once",
+		Example: "  kics report merge a.json b.json -o merged.json\n" +
+			"  kics report merge shards/*.json -o reports/results.json --report-formats json,sarif",
+		Args: cobra.MinimumNArgs(minMergeReports),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			summaries := make([]model.Summary, 0, len(args))
+			for _, path := range args {
+				summary, err := report.ReadJSONReport(path)
+				if err != nil {
+					return err
+				}
+				summaries = append(summaries, summary)
+			}
+			merged := model.MergeSummaries(summaries)
+
+			options.outputPath = filepath.Dir(output)
+			options.outputName = strings.TrimSuffix(filepath.Base(output), filepath.Ext(output))
+			return writeReports(&merged, &options)
+		},
+	}
+	mergeCmd.Flags().StringVarP(&output, "output", "o", defaultMergeOutput,
+		"file path of the merged results, the other report formats are stored next to it with the same name")
+	mergeCmd.Flags().StringSliceVar(&options.formats, flags.ReportFormatsFlag, []string{"json"},
+		fmt.Sprintf("formats in which the merged results will be exported (%s)",
+			strings.Join(append([]string{"all"}, consoleHelpers.ListReportFormats()...), ", ")))
+	return mergeCmd
+}
+
+// writeReports writes the summary with the report formats of the options
+func writeReports(summary *model.Summary, options *reportOptions) error {
+	formats, err := getReportFormats(options.formats)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(options.outputPath, os.ModePerm); err != nil {
+		return err
+	}
+	proBarBuilder := progress.InitializePbBuilder(true, flags.GetBoolFlag(flags.CIFlag), flags.GetBoolFlag(flags.SilentFlag))
+	return consoleHelpers.GenerateReport(options.outputPath, options.outputName, summary, formats,
+		&consoleHelpers.ReportOptions{}, *proBarBuilder)
+}
+
+// getReportFormats validates the report formats, 'all' is replaced by all the report formats
+func getReportFormats(formats []string) ([]string, error) {
+	supported := consoleHelpers.ListReportFormats()
+	validated := make([]string, 0, len(formats))
+	for _, format := range formats {
+		format = strings.ToLower(strings.TrimSpace(format))
+		if format == "all" {
+			return supported, nil
+		}
+		if !isSupportedReportFormat(format, supported) {
+			return nil, fmt.Errorf("unknown report format %s, supported formats are: %s",
+				format, strings.Join(append([]string{"all"}, supported...), ", "))
+		}
+		validated = append(validated, format)
+	}
+	return validated, nil
+}
+
+func isSupportedReportFormat(format string, supported []string) bool {
+	for _, value := range supported {
+		if value == format {
+			return true
+		}
+	}
+	return false
+}
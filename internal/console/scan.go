@@ -119,6 +119,8 @@ func getScanParameters(changedDefaultQueryPath, changedDefaultLibrariesPath bool
 		ExcludeSeverities:           flags.GetMultiStrFlag(flags.ExcludeSeveritiesFlag),
 		IncludeQueries:              flags.GetMultiStrFlag(flags.IncludeQueriesFlag),
 		InputData:                   flags.GetStrFlag(flags.InputDataFlag),
+		MarkdownLinkTemplate:        flags.GetStrFlag(flags.MarkdownLinkTemplateFlag),
+		MarkdownMaxSize:             flags.GetIntFlag(flags.MarkdownMaxSizeFlag),
 		OutputName:                  flags.GetStrFlag(flags.OutputNameFlag),
 		OutputPath:                  flags.GetStrFlag(flags.OutputPathFlag),
 		Path:                        flags.GetMultiStrFlag(flags.PathFlag),
@@ -127,11 +129,34 @@ func getScanParameters(changedDefaultQueryPath, changedDefaultLibrariesPath bool
 		QueriesPath:                 flags.GetStrFlag(flags.QueriesPath),
 		LibrariesPath:               flags.GetStrFlag(flags.LibrariesPath),
 		ReportFormats:               flags.GetMultiStrFlag(flags.ReportFormatsFlag),
+		ReportTemplates:             flags.GetMultiStrFlag(flags.ReportTemplateFlag),
 		Platform:                    flags.GetMultiStrFlag(flags.TypeFlag),
 		QueryExecTimeout:            flags.GetIntFlag(flags.QueryExecTimeoutFlag),
 		LineInfoPayload:             flags.GetBoolFlag(flags.LineInfoPayloadFlag),
 		DisableSecrets:              flags.GetBoolFlag(flags.DisableSecretsFlag),
 		SecretsRegexesPath:          flags.GetStrFlag(flags.SecretsRegexesPathFlag),
+		ShowSecrets:                 flags.GetBoolFlag(flags.ShowSecretsFlag),
+		SecretsAllFiles:             flags.GetBoolFlag(flags.SecretsAllFilesFlag),
+		SecretsDecodingDepth:        flags.GetIntFlag(flags.SecretsDecodingDepthFlag),
+		SecretsIncludePaths:         flags.GetMultiStrFlag(flags.SecretsIncludePathsFlag),
+		SecretsExcludePaths:         flags.GetMultiStrFlag(flags.SecretsExcludePathsFlag),
+		SecretsHistory:              flags.GetBoolFlag(flags.SecretsHistoryFlag),
+		SecretsHistoryRange:         flags.GetStrFlag(flags.SecretsHistoryRangeFlag),
+		SecretsHistorySince:         flags.GetStrFlag(flags.SecretsHistorySinceFlag),
+		AnsibleVaultPasswordFiles:   flags.GetMultiStrFlag(flags.AnsibleVaultPasswordFileFlag),
+		CRDPaths:                    flags.GetMultiStrFlag(flags.CRDPathFlag),
+		DockerBuildArgs:             flags.GetMultiStrFlag(flags.DockerBuildArgFlag),
+		StdinType:                   flags.GetStrFlag(flags.StdinTypeFlag),
+		StdinFilename:               flags.GetStrFlag(flags.StdinFilenameFlag),
+		StdinMaxSize:                flags.GetIntFlag(flags.StdinMaxSizeFlag),
+		InsecureFetch:               flags.GetBoolFlag(flags.InsecureFetchFlag),
+		Offline:                     flags.GetBoolFlag(flags.OfflineFlag),
+		FetchCachePath:              flags.GetStrFlag(flags.FetchCachePathFlag),
+		FetchMirrors:                flags.GetMultiStrFlag(flags.FetchMirrorFlag),
+		FetchRequireChecksum:        flags.GetBoolFlag(flags.FetchRequireChecksumFlag),
+		ArchiveDepth:                flags.GetIntFlag(flags.ArchiveDepthFlag),
+		ArchiveMaxFiles:             flags.GetIntFlag(flags.ArchiveMaxFilesFlag),
+		ArchiveMaxSize:              flags.GetIntFlag(flags.ArchiveMaxSizeFlag),
 		ScanID:                      scanID,
 		ChangedDefaultLibrariesPath: changedDefaultLibrariesPath,
 		ChangedDefaultQueryPath:     changedDefaultQueryPath,
@@ -170,9 +195,8 @@ func executeScan(scanParams *scan.Parameters) error {
 
 // gracefulShutdown catches signal interrupt and returns the appropriate exit code
 func gracefulShutdown() {
-	c := make(chan os.Signal)
-	// This line should not be lint, since golangci-lint has an issue about it (https://github.com/golang/go/issues/45043)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM) // nolint
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 	showErrors := consoleHelpers.ShowError("errors")
 	interruptCode := constants.SignalInterruptCode
 	go func(showErrors bool, interruptCode int) {
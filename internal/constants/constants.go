@@ -47,6 +47,12 @@ var (
 		"Terraform":            "terraform",
 		"OpenAPI":              "openapi",
 		"AzureResourceManager": "azureresourcemanager",
+		"Bicep":                "bicep",
+		"AsyncAPI":             "asyncapi",
+		"GraphQL":              "graphql",
+		"DockerCompose":        "dockercompose",
+		"CICD":                 "cicd",
+		"Pulumi":               "pulumi",
 	}
 
 	// AvailableSeverities - All severities available
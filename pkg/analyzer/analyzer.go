@@ -8,6 +8,7 @@ import (
 	"sync"
 
 	"github.com/Checkmarx/kics/internal/metrics"
+	"github.com/Checkmarx/kics/pkg/model"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 )
@@ -20,10 +21,40 @@ import (
 // k8sRegexKind - Regex that finds Kubernetes defining property "kind"
 // k8sRegexMetadata - Regex that finds Kubernetes defining property "metadata"
 // k8sRegexSpec - Regex that finds Kubernetes defining property "spec"
+// asyncAPIRegex - Regex that finds AsyncAPI defining property "asyncapi"
+// asyncAPIRegexChannels - Regex that finds AsyncAPI defining property "channels"
+// graphQLRouterRegex - Regex that finds GraphQL router (gateway) configuration defining property "supergraph"
+// dockerComposeRegexServices - Regex that finds Docker Compose defining top level property "services"
+// dockerComposeRegexServiceKeys - Regex that finds the properties of Docker Compose services
+// dockerComposeFileNameRegex - Regex that finds the default names of Docker Compose files (including override files)
+// githubWorkflowRegexOn - Regex that finds GitHub Actions workflow defining top level property "on"
+// githubWorkflowRegexJobs - Regex that finds GitHub Actions workflow defining top level property "jobs"
+// githubWorkflowRegexJobKeys - Regex that finds the properties of GitHub Actions jobs and steps
+// gitlabCIRegexStages - Regex that finds GitLab CI defining top level property "stages"
+// gitlabCIRegexScript - Regex that finds the scripts of GitLab CI jobs
+// githubWorkflowPathRegex - Regex that finds the paths of GitHub Actions workflows
+// gitlabCIFileNameRegex - Regex that finds the default name of GitLab CI pipelines
+// pulumiRegexResources - Regex that finds Pulumi YAML defining top level property "resources"
+// pulumiRegexResourceType - Regex that finds the type tokens of Pulumi resources (e.g. "aws:s3:Bucket")
 var (
 	openAPIRegex                      = regexp.MustCompile("(\\s*\"openapi\":)|(\\s*openapi:)|(\\s*\"swagger\":)|(\\s*swagger:)")
 	openAPIRegexInfo                  = regexp.MustCompile("(\\s*\"info\":)|(\\s*info:)")
 	openAPIRegexPath                  = regexp.MustCompile("(\\s*\"paths\":)|(\\s*paths:)")
+	asyncAPIRegex                     = regexp.MustCompile("(\\s*\"asyncapi\":)|(\\s*asyncapi:)")
+	asyncAPIRegexChannels             = regexp.MustCompile("(\\s*\"channels\":)|(\\s*channels:)")
+	graphQLRouterRegex                = regexp.MustCompile("(\\s*\"supergraph\":)|(\\s*supergraph:)")
+	dockerComposeRegexServices        = regexp.MustCompile(`(?m)^services\s*:`)
+	dockerComposeRegexServiceKeys     = regexp.MustCompile(`(?m)^\s+(image|build|ports|volumes|environment|network_mode|depends_on)\s*:`)
+	dockerComposeFileNameRegex        = regexp.MustCompile(`^(docker-)?compose(\.[\w-]+)*\.ya?ml$`)
+	githubWorkflowRegexOn             = regexp.MustCompile(`(?m)^["']?on["']?\s*:`)
+	githubWorkflowRegexJobs           = regexp.MustCompile(`(?m)^jobs\s*:`)
+	githubWorkflowRegexJobKeys        = regexp.MustCompile(`(?m)^\s+(-\s+)?(runs-on|uses|steps)\s*:`)
+	gitlabCIRegexStages               = regexp.MustCompile(`(?m)^stages\s*:`)
+	gitlabCIRegexScript               = regexp.MustCompile(`(?m)^\s+script\s*:`)
+	githubWorkflowPathRegex           = regexp.MustCompile(`(^|/)\.github/workflows/[^/]+\.ya?ml$`)
+	gitlabCIFileNameRegex             = regexp.MustCompile(`^\.gitlab-ci\.ya?ml$`)
+	pulumiRegexResources              = regexp.MustCompile(`(?m)^resources\s*:`)
+	pulumiRegexResourceType           = regexp.MustCompile(`(?m)^\s+type\s*:\s*["']?[\w-]+:[\w/.-]*:\w+["']?\s*$`)
 	armRegexContentVersion            = regexp.MustCompile("\\s*\"contentVersion\":")
 	armRegexResources                 = regexp.MustCompile("\\s*\"resources\":")
 	cloudRegex                        = regexp.MustCompile("(\\s*\"Resources\":)|(\\s*Resources:)")
@@ -42,15 +73,26 @@ var (
 )
 
 const (
-	yml  = ".yml"
-	yaml = ".yaml"
-	json = ".json"
-	arm  = "azureresourcemanager"
+	yml           = ".yml"
+	yaml          = ".yaml"
+	json          = ".json"
+	tfJSON        = ".tf.json"
+	tfvarsJSON    = ".tfvars.json"
+	arm           = "azureresourcemanager"
+	asyncAPI      = "asyncapi"
+	dockerCompose = "dockercompose"
+	cicd          = "cicd"
+	gitlabCI      = "gitlabci"
+	bicep         = ".bicep"
+	graphQL       = ".graphql"
+	graphQLS      = ".graphqls"
+	gql           = ".gql"
 )
 
 // Analyze will go through the slice paths given and determine what type of queries should be loaded
 // should be loaded based on the extension of the file and the content
-func Analyze(paths []string) (typesRes, excludeRes []string, errRes error) {
+// files encrypted with Ansible Vault are only analyzed when ansibleVault is set, since they can be decrypted
+func Analyze(paths []string, ansibleVault bool) (typesRes, excludeRes []string, errRes error) {
 	// start metrics for file analyzer
 	metrics.Metric.Start("file_type_analyzer")
 
@@ -80,7 +122,7 @@ func Analyze(paths []string) (typesRes, excludeRes []string, errRes error) {
 	for _, file := range files {
 		wg.Add(1)
 		// analyze the files concurrently
-		go worker(file, results, unwanted, &wg)
+		go worker(file, results, unwanted, ansibleVault, &wg)
 	}
 
 	go func() {
@@ -103,9 +145,9 @@ func Analyze(paths []string) (typesRes, excludeRes []string, errRes error) {
 // worker determines the type of the file by ext (dockerfile and terraform)/content and
 // writes the answer to the results channel
 // if no types were found, the worker will write the path of the file in the unwanted channel
-func worker(path string, results, unwanted chan<- string, wg *sync.WaitGroup) {
+func worker(path string, results, unwanted chan<- string, ansibleVault bool, wg *sync.WaitGroup) {
 	defer wg.Done()
-	ext := filepath.Ext(path)
+	ext := model.FileExtension(path)
 	if ext == "" {
 		ext = filepath.Base(path)
 	}
@@ -114,14 +156,35 @@ func worker(path string, results, unwanted chan<- string, wg *sync.WaitGroup) {
 	case ".dockerfile", "Dockerfile":
 		results <- "dockerfile"
 	// Terraform
-	case ".tf", "tfvars":
+	case ".tf", "tfvars", tfJSON, tfvarsJSON:
 		results <- "terraform"
-	// Cloud Formation, Ansible, OpenAPI
+	// Bicep
+	case bicep:
+		results <- "bicep"
+	// GraphQL
+	case graphQL, graphQLS, gql:
+		results <- "graphql"
+	// Cloud Formation, Ansible, OpenAPI, AsyncAPI, GraphQL router configuration, Docker Compose, CI pipelines, Pulumi
 	case yaml, yml, json:
-		checkContent(path, results, unwanted, ext)
+		// Docker Compose override files may not have any property that identifies them
+		if dockerComposeFileNameRegex.MatchString(filepath.Base(path)) {
+			results <- dockerCompose
+			return
+		}
+		if isCIPipeline(path) {
+			results <- cicd
+			return
+		}
+		checkContent(path, results, unwanted, ext, ansibleVault)
 	}
 }
 
+// isCIPipeline verifies if the path is a GitHub Actions workflow or a GitLab CI pipeline by its location and name
+func isCIPipeline(path string) bool {
+	return githubWorkflowPathRegex.MatchString(filepath.ToSlash(path)) ||
+		gitlabCIFileNameRegex.MatchString(filepath.Base(path))
+}
+
 // regexSlice is a struct to contain a slice of regex
 type regexSlice struct {
 	regex []*regexp.Regexp
@@ -136,6 +199,42 @@ var types = map[string]regexSlice{
 			openAPIRegexPath,
 		},
 	},
+	"asyncapi": {
+		regex: []*regexp.Regexp{
+			asyncAPIRegex,
+			asyncAPIRegexChannels,
+		},
+	},
+	"graphql": {
+		regex: []*regexp.Regexp{
+			graphQLRouterRegex,
+		},
+	},
+	"cicd": {
+		regex: []*regexp.Regexp{
+			githubWorkflowRegexOn,
+			githubWorkflowRegexJobs,
+			githubWorkflowRegexJobKeys,
+		},
+	},
+	"gitlabci": {
+		regex: []*regexp.Regexp{
+			gitlabCIRegexStages,
+			gitlabCIRegexScript,
+		},
+	},
+	"pulumi": {
+		regex: []*regexp.Regexp{
+			pulumiRegexResources,
+			pulumiRegexResourceType,
+		},
+	},
+	"dockercompose": {
+		regex: []*regexp.Regexp{
+			dockerComposeRegexServices,
+			dockerComposeRegexServiceKeys,
+		},
+	},
 	"kubernetes": {
 		regex: []*regexp.Regexp{
 			k8sRegex,
@@ -177,16 +276,20 @@ var types = map[string]regexSlice{
 }
 
 // overrides k8s match when all regexs passes for azureresourcemanager key and extension is set to json
+// overrides any match when all regexs passes for asyncapi key, since "asyncapi" is only defined by AsyncAPI documents
 func needsOverride(check bool, returnType, key, ext string) bool {
 	if check && returnType == "kubernetes" && key == "azureresourcemanager" && ext == json {
 		return true
 	}
+	if check && key == asyncAPI {
+		return true
+	}
 	return false
 }
 
 // checkContent will determine the file type by content when worker was unable to
 // determine by ext, if no type was determined checkContent adds it to unwanted channel
-func checkContent(path string, results, unwanted chan<- string, ext string) {
+func checkContent(path string, results, unwanted chan<- string, ext string, ansibleVault bool) {
 	// get file content
 	content, err := os.ReadFile(path)
 	if err != nil {
@@ -224,11 +327,14 @@ func checkContent(path string, results, unwanted chan<- string, ext string) {
 		if returnType == "blueprint" || returnType == "blueprintsartifacts" {
 			returnType = arm
 		}
+		if returnType == gitlabCI {
+			returnType = cicd
+		}
 		// write to channel type of file
 		results <- returnType
 	} else if ext == yaml || ext == yml {
-		// check if it is an ansible vault
-		if res := ansibleVaultRegex.Match(content); res {
+		// check if it is an ansible vault that can not be decrypted
+		if res := ansibleVaultRegex.Match(content); res && !ansibleVault {
 			unwanted <- path
 		} else {
 			// Since Ansible has no defining property
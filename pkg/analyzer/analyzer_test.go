@@ -10,16 +10,17 @@ import (
 
 func TestAnalyzer_Analyze(t *testing.T) {
 	tests := []struct {
-		name        string
-		paths       []string
-		wantTypes   []string
-		wantExclude []string
-		wantErr     bool
+		name         string
+		paths        []string
+		wantTypes    []string
+		wantExclude  []string
+		wantErr      bool
+		ansibleVault bool
 	}{
 		{
 			name:        "analyze_test_dir_single_path",
 			paths:       []string{filepath.FromSlash("../../test/fixtures/analyzer_test")},
-			wantTypes:   []string{"dockerfile", "cloudformation", "kubernetes", "openapi", "terraform", "ansible", "azureresourcemanager"},
+			wantTypes:   []string{"dockerfile", "cloudformation", "kubernetes", "openapi", "terraform", "ansible", "azureresourcemanager", "bicep", "asyncapi", "graphql", "dockercompose", "cicd", "pulumi"},
 			wantExclude: []string{},
 			wantErr:     false,
 		},
@@ -47,6 +48,38 @@ func TestAnalyzer_Analyze(t *testing.T) {
 			wantExclude: []string{},             // ansible is added because of unknown type in values.yaml
 			wantErr:     false,
 		},
+		{
+			name: "analyze_test_graphql_router_path",
+			paths: []string{
+				filepath.FromSlash("../../test/fixtures/analyzer_test/graphql_router")},
+			wantTypes:   []string{"graphql"},
+			wantExclude: []string{},
+			wantErr:     false,
+		},
+		{
+			name: "analyze_test_docker_compose_path",
+			paths: []string{
+				filepath.FromSlash("../../test/fixtures/analyzer_test/docker_compose")},
+			wantTypes:   []string{"dockercompose"},
+			wantExclude: []string{},
+			wantErr:     false,
+		},
+		{
+			name: "analyze_test_cicd_path",
+			paths: []string{
+				filepath.FromSlash("../../test/fixtures/analyzer_test/cicd")},
+			wantTypes:   []string{"cicd"},
+			wantExclude: []string{},
+			wantErr:     false,
+		},
+		{
+			name: "analyze_test_pulumi_path",
+			paths: []string{
+				filepath.FromSlash("../../test/fixtures/analyzer_test/pulumi")},
+			wantTypes:   []string{"pulumi"},
+			wantExclude: []string{},
+			wantErr:     false,
+		},
 		{
 			name: "analyze_test_error_path",
 			paths: []string{
@@ -74,11 +107,39 @@ func TestAnalyzer_Analyze(t *testing.T) {
 			wantExclude: []string{},
 			wantErr:     false,
 		},
+		{
+			name: "analyze_test_terraform_json",
+			paths: []string{
+				filepath.FromSlash("../../test/fixtures/test_terraform_json_variables"),
+			},
+			wantTypes:   []string{"terraform"},
+			wantExclude: []string{},
+			wantErr:     false,
+		},
+		{
+			name: "analyze_test_ansible_vault",
+			paths: []string{
+				filepath.FromSlash("../../test/fixtures/ansible_vault/secrets.yml"),
+			},
+			wantTypes:   []string{},
+			wantExclude: []string{filepath.FromSlash("../../test/fixtures/ansible_vault/secrets.yml")},
+			wantErr:     false,
+		},
+		{
+			name: "analyze_test_ansible_vault_with_password",
+			paths: []string{
+				filepath.FromSlash("../../test/fixtures/ansible_vault/secrets.yml"),
+			},
+			wantTypes:    []string{"ansible"},
+			wantExclude:  []string{},
+			wantErr:      false,
+			ansibleVault: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, exc, err := Analyze(tt.paths)
+			got, exc, err := Analyze(tt.paths, tt.ansibleVault)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Analyze = %v, wantErr = %v", err, tt.wantErr)
 			}
@@ -0,0 +1,183 @@
+package detector
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Checkmarx/kics/pkg/model"
+)
+
+const (
+	refOriginKey = "_kics_ref"
+	refKey       = "$ref"
+	// notFoundLine is used by findByKeyValue when no object matches, so the search can go on
+	notFoundLine = -2
+)
+
+// RefOrigin is the file and line where a resolved reference was defined
+type RefOrigin struct {
+	FilePath string
+	Line     int
+}
+
+// GetRefOrigin follows the path components in the payload with lines information and, when the path goes
+// inside the content of a resolved reference ('$ref'), returns the file and line where that content was defined
+func GetRefOrigin(pathComponents []string, file *model.FileMetadata) (RefOrigin, bool) {
+	origin := findRefOrigin(map[string]interface{}(file.LineInfoDocument), pathComponents,
+		RefOrigin{Line: undetectedVulnerabilityLine})
+	return origin, isValidOrigin(origin)
+}
+
+func findRefOrigin(current interface{}, pathComponents []string, origin RefOrigin) RefOrigin {
+	if len(pathComponents) == 0 {
+		return origin
+	}
+	component := pathComponents[0]
+
+	switch node := current.(type) {
+	case model.Document:
+		return findRefOrigin(map[string]interface{}(node), pathComponents, origin)
+	case map[string]interface{}:
+		if filePath, ok := node[refOriginKey].(string); ok && component != refKey {
+			origin = RefOrigin{FilePath: filePath, Line: undetectedVulnerabilityLine}
+		}
+		if _, ok := node[component]; !ok {
+			keyValue := strings.SplitN(component, "=", 2) //nolint:gomnd
+			// search keys that start with an object selected by one of its values (e.g. Ansible tasks 'name=...')
+			// are relative, that object may be anywhere in the document
+			if _, hasKey := node[keyValue[0]]; !hasKey && len(keyValue) == 2 { //nolint:gomnd
+				return findByKeyValue(node, keyValue[0], keyValue[1], pathComponents[1:], origin)
+			}
+			// search keys may end with the value of the key (key=value)
+			component = keyValue[0]
+		}
+		next, ok := node[component]
+		if !ok {
+			return origin
+		}
+		origin = setOriginLine(origin, node, component)
+		return findRefOrigin(next, pathComponents[1:], origin)
+	case []interface{}:
+		if idx, err := strconv.Atoi(component); err == nil {
+			if idx < 0 || idx >= len(node) {
+				return origin
+			}
+			return findRefOrigin(node[idx], pathComponents[1:], origin)
+		}
+		// elements selected by one of its values (key=value) are located by that key
+		if keyValue := strings.SplitN(component, "=", 2); len(keyValue) == 2 { //nolint:gomnd
+			for _, element := range node {
+				if obj, ok := element.(map[string]interface{}); ok {
+					if value, ok := obj[keyValue[0]].(string); ok && value == keyValue[1] {
+						return findRefOrigin(obj, append([]string{keyValue[0]}, pathComponents[1:]...), origin)
+					}
+				}
+			}
+			return origin
+		}
+		// array indexes are not always part of the search key, so each element is tried
+		for _, element := range node {
+			if elementOrigin := findRefOrigin(element, pathComponents, origin); isValidOrigin(elementOrigin) {
+				return elementOrigin
+			}
+		}
+	}
+
+	return origin
+}
+
+// findByKeyValue searches the document for the first object where key has the given value and follows
+// the remaining path components from that object
+func findByKeyValue(current interface{}, key, value string, pathComponents []string, origin RefOrigin) RefOrigin {
+	switch node := current.(type) {
+	case map[string]interface{}:
+		if filePath, ok := node[refOriginKey].(string); ok {
+			origin = RefOrigin{FilePath: filePath, Line: undetectedVulnerabilityLine}
+		}
+		if nodeValue, ok := node[key].(string); ok && nodeValue == value {
+			// the remaining components are properties of the selected object
+			origin = setOriginLine(origin, node, key)
+			if len(pathComponents) == 0 {
+				return origin
+			}
+			return findRefOrigin(node, pathComponents, origin)
+		}
+		keys := make([]string, 0, len(node))
+		for k := range node {
+			if k != "_kics_lines" {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if found := findByKeyValue(node[k], key, value, pathComponents, origin); found.Line != notFoundLine {
+				return found
+			}
+		}
+	case []interface{}:
+		for _, element := range node {
+			if found := findByKeyValue(element, key, value, pathComponents, origin); found.Line != notFoundLine {
+				return found
+			}
+		}
+	}
+	return RefOrigin{Line: notFoundLine}
+}
+
+func setOriginLine(origin RefOrigin, node map[string]interface{}, key string) RefOrigin {
+	if origin.FilePath == "" {
+		return origin
+	}
+	if line := getKeyLine(node["_kics_lines"], "_kics_"+key); line > 0 {
+		origin.Line = line
+	}
+	return origin
+}
+
+// SplitSearchKey splits the search key into path components, removing the brackets used to wrap values
+func SplitSearchKey(searchKey string) []string {
+	var extractedString [][]string
+	extractedString = GetBracketValues(searchKey, extractedString, "")
+	sanitizedSubstring := searchKey
+	for idx, str := range extractedString {
+		sanitizedSubstring = strings.Replace(sanitizedSubstring, str[0], `{{`+strconv.Itoa(idx)+`}}`, -1)
+	}
+
+	components := strings.Split(sanitizedSubstring, ".")
+	for i := range components {
+		for idx, str := range extractedString {
+			components[i] = strings.Replace(components[i], `{{`+strconv.Itoa(idx)+`}}`, str[1], -1)
+		}
+	}
+	return components
+}
+
+func isValidOrigin(origin RefOrigin) bool {
+	return origin.FilePath != "" && origin.Line > 0
+}
+
+// getKeyLine returns the line of the key from the line information map, whatever the parser that created it
+func getKeyLine(lines interface{}, key string) int {
+	switch v := lines.(type) {
+	case map[string]model.LineObject:
+		return v[key].Line
+	case map[string]interface{}:
+		switch line := v[key].(type) {
+		case model.LineObject:
+			return line.Line
+		case map[string]interface{}:
+			switch nr := line["_kics_line"].(type) {
+			case float64:
+				return int(nr)
+			case json.Number:
+				n, _ := nr.Int64()
+				return int(n)
+			case int:
+				return nr
+			}
+		}
+	}
+	return undetectedVulnerabilityLine
+}
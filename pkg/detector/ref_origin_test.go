@@ -0,0 +1,152 @@
+package detector
+
+import (
+	"testing"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+var refOriginDocument = map[string]interface{}{
+	"_kics_lines": map[string]model.LineObject{
+		"_kics__default": {Line: 0},
+		"_kics_paths":    {Line: 5},
+	},
+	"paths": map[string]interface{}{
+		"_kics_lines": map[string]model.LineObject{
+			"_kics__default": {Line: 5},
+			"_kics_/pets":    {Line: 6},
+		},
+		"/pets": map[string]interface{}{
+			"_kics_lines": map[string]model.LineObject{
+				"_kics__default":   {Line: 6},
+				"_kics_parameters": {Line: 7},
+			},
+			"parameters": []interface{}{
+				map[string]interface{}{
+					"_kics_lines": map[string]interface{}{
+						"_kics__default": map[string]interface{}{"_kics_line": 8.0},
+						"_kics_$ref":     map[string]interface{}{"_kics_line": 8.0},
+						"_kics_name":     map[string]interface{}{"_kics_line": 3.0},
+						"_kics_in":       map[string]interface{}{"_kics_line": 4.0},
+					},
+					"_kics_ref": "parameters.yaml",
+					"$ref":      "parameters.yaml#/limit",
+					"name":      "limit",
+					"in":        "query",
+				},
+			},
+		},
+	},
+}
+
+// TestGetRefOrigin tests the functions [GetRefOrigin()] and all the methods called by them
+func TestGetRefOrigin(t *testing.T) {
+	file := &model.FileMetadata{
+		FilePath:         "openapi.yaml",
+		LineInfoDocument: refOriginDocument,
+	}
+
+	tests := []struct {
+		name           string
+		pathComponents []string
+		want           RefOrigin
+		wantOk         bool
+	}{
+		{
+			name:           "key inside resolved reference",
+			pathComponents: []string{"paths", "/pets", "parameters", "0", "in"},
+			want:           RefOrigin{FilePath: "parameters.yaml", Line: 4},
+			wantOk:         true,
+		},
+		{
+			name:           "array element selected by key value",
+			pathComponents: []string{"paths", "/pets", "parameters", "name=limit", "name"},
+			want:           RefOrigin{FilePath: "parameters.yaml", Line: 3},
+			wantOk:         true,
+		},
+		{
+			name:           "key with value",
+			pathComponents: []string{"paths", "/pets", "parameters", "0", "in=query"},
+			want:           RefOrigin{FilePath: "parameters.yaml", Line: 4},
+			wantOk:         true,
+		},
+		{
+			name:           "reference pointer",
+			pathComponents: []string{"paths", "/pets", "parameters", "0", "$ref"},
+			want:           RefOrigin{Line: -1},
+			wantOk:         false,
+		},
+		{
+			name:           "outside resolved reference",
+			pathComponents: []string{"paths", "/pets"},
+			want:           RefOrigin{Line: -1},
+			wantOk:         false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := GetRefOrigin(tt.pathComponents, file)
+			require.Equal(t, tt.wantOk, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// TestGetRefOrigin_RelativeSearchKey tests that search keys starting with an object selected by its value
+// (e.g. Ansible tasks) are found anywhere in the document
+func TestGetRefOrigin_RelativeSearchKey(t *testing.T) {
+	file := &model.FileMetadata{
+		FilePath: "playbook.yml",
+		LineInfoDocument: map[string]interface{}{
+			"playbooks": []interface{}{
+				map[string]interface{}{
+					"name": "play",
+					"tasks": []interface{}{
+						map[string]interface{}{
+							"name": "local task",
+							"file": map[string]interface{}{"path": "/tmp"},
+						},
+						map[string]interface{}{
+							"_kics_lines": map[string]interface{}{
+								"_kics__default":  map[string]interface{}{"_kics_line": 2.0},
+								"_kics_name":      map[string]interface{}{"_kics_line": 2.0},
+								"_kics_s3_bucket": map[string]interface{}{"_kics_line": 3.0},
+							},
+							"_kics_ref": "roles/s3/tasks/main.yml",
+							"name":      "create bucket",
+							"s3_bucket": map[string]interface{}{
+								"_kics_lines": map[string]interface{}{
+									"_kics__default":   map[string]interface{}{"_kics_line": 3.0},
+									"_kics_encryption": map[string]interface{}{"_kics_line": 5.0},
+								},
+								"encryption": "none",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got, ok := GetRefOrigin(SplitSearchKey("name={{create bucket}}.{{s3_bucket}}.encryption"), file)
+	require.True(t, ok)
+	require.Equal(t, RefOrigin{FilePath: "roles/s3/tasks/main.yml", Line: 5}, got)
+
+	got, ok = GetRefOrigin(SplitSearchKey("name={{create bucket}}"), file)
+	require.True(t, ok)
+	require.Equal(t, RefOrigin{FilePath: "roles/s3/tasks/main.yml", Line: 2}, got)
+
+	_, ok = GetRefOrigin(SplitSearchKey("name={{local task}}.{{file}}.path"), file)
+	require.False(t, ok)
+
+	_, ok = GetRefOrigin(SplitSearchKey("name={{missing}}.{{file}}"), file)
+	require.False(t, ok)
+}
+
+// TestSplitSearchKey tests the functions [SplitSearchKey()] and all the methods called by them
+func TestSplitSearchKey(t *testing.T) {
+	got := SplitSearchKey("paths.{{/pets}}.get.responses.{{200}}.content.{{application/json}}.schema.type")
+	require.Equal(t, []string{"paths", "/pets", "get", "responses", "200", "content", "application/json", "schema", "type"}, got)
+}
@@ -0,0 +1,98 @@
+package engine
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	cdkPathMetadata      = "aws:cdk:path"
+	cdkLogicalIDMetadata = "aws:cdk:logicalId"
+	cdkManifestFileName  = "manifest.json"
+	cdkStackArtifactType = "aws:cloudformation:stack"
+)
+
+// cdkManifest is the cloud assembly manifest (cdk.out/manifest.json) synthesized by AWS CDK
+type cdkManifest struct {
+	Artifacts map[string]struct {
+		Type       string `json:"type"`
+		Properties struct {
+			TemplateFile string `json:"templateFile"`
+		} `json:"properties"`
+		Metadata map[string][]struct {
+			Type string      `json:"type"`
+			Data interface{} `json:"data"`
+		} `json:"metadata"`
+	} `json:"artifacts"`
+}
+
+var (
+	cdkManifestsMu sync.Mutex
+	// cdkManifests caches the construct paths of the logical ids by template file, read from the manifests
+	cdkManifests = make(map[string]map[string]string)
+)
+
+// constructPath returns the CDK construct path of the CloudFormation resource of the search key, the
+// 'aws:cdk:path' metadata of the resource is used, otherwise the manifest of the cloud assembly of the template
+func constructPath(file *model.FileMetadata, searchKey string) string {
+	if !strings.HasPrefix(searchKey, "Resources.") {
+		return ""
+	}
+	logicalID := strings.SplitN(strings.TrimPrefix(searchKey, "Resources."), ".", 2)[0]
+
+	if resources, ok := file.Document["Resources"].(map[string]interface{}); ok {
+		if resource, ok := resources[logicalID].(map[string]interface{}); ok {
+			if metadata, ok := resource["Metadata"].(map[string]interface{}); ok {
+				if path, ok := metadata[cdkPathMetadata].(string); ok {
+					return path
+				}
+			}
+		}
+	}
+
+	return manifestConstructPaths(file.FilePath)[logicalID]
+}
+
+// manifestConstructPaths returns the construct paths of the logical ids of the template, read from the manifest
+// of the cloud assembly in the directory of the template
+func manifestConstructPaths(templatePath string) map[string]string {
+	cdkManifestsMu.Lock()
+	defer cdkManifestsMu.Unlock()
+
+	if paths, ok := cdkManifests[templatePath]; ok {
+		return paths
+	}
+
+	paths := make(map[string]string)
+	cdkManifests[templatePath] = paths
+
+	content, err := os.ReadFile(filepath.Join(filepath.Dir(templatePath), cdkManifestFileName))
+	if err != nil {
+		return paths
+	}
+	var manifest cdkManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		log.Debug().Msgf("Failed to parse CDK manifest of %s: %s", templatePath, err)
+		return paths
+	}
+
+	for _, artifact := range manifest.Artifacts {
+		if artifact.Type != cdkStackArtifactType || artifact.Properties.TemplateFile != filepath.Base(templatePath) {
+			continue
+		}
+		for path, entries := range artifact.Metadata {
+			for _, entry := range entries {
+				if logicalID, ok := entry.Data.(string); ok && entry.Type == cdkLogicalIDMetadata {
+					paths[logicalID] = strings.TrimPrefix(path, "/")
+				}
+			}
+		}
+	}
+	return paths
+}
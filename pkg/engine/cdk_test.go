@@ -0,0 +1,64 @@
+package engine
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConstructPath tests the functions [constructPath()] and all the methods called by them
+func TestConstructPath(t *testing.T) {
+	templatePath := filepath.FromSlash("../../test/fixtures/cdk_out/StorageStack.template.json")
+	content, err := os.ReadFile(templatePath)
+	require.NoError(t, err)
+	file := model.FileMetadata{FilePath: templatePath}
+	require.NoError(t, json.Unmarshal(content, &file.Document))
+
+	tests := []struct {
+		name      string
+		file      model.FileMetadata
+		searchKey string
+		want      string
+	}{
+		{
+			name:      "resource_metadata",
+			file:      file,
+			searchKey: "Resources.AssetsBucket5CB76180.Properties.AccessControl",
+			want:      "StorageStack/AssetsBucket/Resource",
+		},
+		{
+			name:      "manifest_metadata",
+			file:      file,
+			searchKey: "Resources.LogsBucket9C4D8843.Properties",
+			want:      "StorageStack/LogsBucket/Resource",
+		},
+		{
+			name:      "unknown_resource",
+			file:      file,
+			searchKey: "Resources.Unknown",
+			want:      "",
+		},
+		{
+			name:      "not_a_resource",
+			file:      file,
+			searchKey: "Parameters.BootstrapVersion",
+			want:      "",
+		},
+		{
+			name:      "without_manifest",
+			file:      model.FileMetadata{FilePath: filepath.FromSlash("../../test/fixtures/tc-sim01/positive1.tf")},
+			searchKey: "Resources.LogsBucket9C4D8843",
+			want:      "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, constructPath(&tt.file, tt.searchKey))
+		})
+	}
+}
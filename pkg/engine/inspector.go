@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Checkmarx/kics/internal/metrics"
@@ -61,6 +62,11 @@ type Inspector struct {
 	failedQueries  map[string]error
 	excludeResults map[string]bool
 	detector       *detector.DetectLine
+	// suppressed are the results ignored by comments or excluded by their similarity ID
+	suppressed      []model.Vulnerability
+	suppressedMutex sync.Mutex
+	// refFiles are the files of the resolved references, read once for all the queries
+	refFiles *refFiles
 
 	enableCoverageReport bool
 	coverageReport       cover.Report
@@ -76,6 +82,7 @@ type QueryContext struct {
 	query         *preparedQuery
 	payload       model.Documents
 	baseScanPaths []string
+	refFiles      *refFiles
 }
 
 var (
@@ -139,6 +146,7 @@ func NewInspector(
 		excludeResults:   excludeResults,
 		detector:         lineDetector,
 		queryExecTimeout: queryExecTimeout,
+		refFiles:         newRefFiles(),
 	}, nil
 }
 
@@ -200,6 +208,7 @@ func (c *Inspector) Inspect(
 			query:         query,
 			payload:       combinedFiles,
 			baseScanPaths: baseScanPaths,
+			refFiles:      c.refFiles,
 		})
 		if err != nil {
 			sentryReport.ReportSentry(&sentryReport.Report{
@@ -316,6 +325,7 @@ func (c *Inspector) decodeQueryResults(ctx *QueryContext, results rego.ResultSet
 	}
 
 	vulnerabilities := make([]model.Vulnerability, 0, len(queryResultItems))
+	refVulnerabilities := make([]model.Vulnerability, 0)
 	failedDetectLine := false
 	for _, queryResultItem := range queryResultItems {
 		vulnerability, err := c.vb(ctx, c.tracker, queryResultItem, c.detector)
@@ -338,6 +348,7 @@ func (c *Inspector) decodeQueryResults(ctx *QueryContext, results rego.ResultSet
 		file := ctx.files[vulnerability.FileID]
 		if shouldSkipFile(file.Commands, vulnerability.QueryID) {
 			log.Debug().Msgf("Skipping file %s for query %s", file.FilePath, ctx.query.metadata.Query)
+			c.suppress(&vulnerability, model.SuppressionInSource, "disabled by a 'kics-scan enable' or 'disable' comment of the file")
 			continue
 		}
 
@@ -348,16 +359,25 @@ func (c *Inspector) decodeQueryResults(ctx *QueryContext, results rego.ResultSet
 		if _, ok := c.excludeResults[vulnerability.SimilarityID]; ok {
 			log.Debug().
 				Msgf("Excluding result SimilarityID: %s", vulnerability.SimilarityID)
+			c.suppress(&vulnerability, model.SuppressionExternal, "excluded by its similarity ID")
 			continue
 		} else if checkComment(vulnerability.Line, file.LinesIgnore) {
 			log.Debug().
 				Msgf("Excluding result Comment: %s", vulnerability.SimilarityID)
+			c.suppress(&vulnerability, model.SuppressionInSource, "ignored by a 'kics-scan ignore-line' or 'ignore-block' comment")
+			continue
+		}
+
+		if _, ok := detector.GetRefOrigin(detector.SplitSearchKey(vulnerability.SearchKey), &file); ok {
+			refVulnerabilities = append(refVulnerabilities, vulnerability)
 			continue
 		}
 
 		vulnerabilities = append(vulnerabilities, vulnerability)
 	}
 
+	vulnerabilities = appendRefVulnerabilities(vulnerabilities, refVulnerabilities)
+
 	if failedDetectLine {
 		c.tracker.FailedDetectLine()
 	}
@@ -365,6 +385,58 @@ func (c *Inspector) decodeQueryResults(ctx *QueryContext, results rego.ResultSet
 	return vulnerabilities, nil
 }
 
+// suppress keeps the result that is not reported, with the reason of its suppression
+func (c *Inspector) suppress(vulnerability *model.Vulnerability, kind, justification string) {
+	vulnerability.Suppression = &model.Suppression{Kind: kind, Justification: justification}
+	c.suppressedMutex.Lock()
+	defer c.suppressedMutex.Unlock()
+	c.suppressed = append(c.suppressed, *vulnerability)
+}
+
+// GetSuppressed returns the results ignored by comments or excluded by their similarity ID
+func (c *Inspector) GetSuppressed() []model.Vulnerability {
+	c.suppressedMutex.Lock()
+	defer c.suppressedMutex.Unlock()
+	return append([]model.Vulnerability{}, c.suppressed...)
+}
+
+// vulnerabilityKey identifies results of a query that point to the same place of a file
+type vulnerabilityKey struct {
+	queryID   string
+	fileName  string
+	line      int
+	issueType model.IssueType
+}
+
+func newVulnerabilityKey(vulnerability *model.Vulnerability) vulnerabilityKey {
+	return vulnerabilityKey{
+		queryID:   vulnerability.QueryID,
+		fileName:  vulnerability.FileName,
+		line:      vulnerability.Line,
+		issueType: vulnerability.IssueType,
+	}
+}
+
+// appendRefVulnerabilities appends the results found inside resolved references ('$ref')
+// the content of a reference is scanned where it is defined and in every place it is used,
+// since all those results point to the definition, each one is reported only once
+func appendRefVulnerabilities(vulnerabilities, refVulnerabilities []model.Vulnerability) []model.Vulnerability {
+	found := make(map[vulnerabilityKey]struct{}, len(vulnerabilities))
+	for i := range vulnerabilities {
+		found[newVulnerabilityKey(&vulnerabilities[i])] = struct{}{}
+	}
+
+	for i := range refVulnerabilities {
+		key := newVulnerabilityKey(&refVulnerabilities[i])
+		if _, ok := found[key]; ok {
+			continue
+		}
+		found[key] = struct{}{}
+		vulnerabilities = append(vulnerabilities, refVulnerabilities[i])
+	}
+	return vulnerabilities
+}
+
 // checkComment checks if the vulnerability should be skipped from comment
 func checkComment(line int, ignoreLines []int) bool {
 	for _, ignoreLine := range ignoreLines {
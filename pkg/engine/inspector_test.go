@@ -215,11 +215,12 @@ func TestInspect(t *testing.T) { //nolint
 		files  model.FileMetadatas
 	}
 	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    []model.Vulnerability
-		wantErr bool
+		name           string
+		fields         fields
+		args           args
+		want           []model.Vulnerability
+		wantSuppressed []string
+		wantErr        bool
 	}{
 		{
 			name: "TestInspect",
@@ -295,8 +296,9 @@ func TestInspect(t *testing.T) { //nolint
 					},
 				},
 			},
-			want:    []model.Vulnerability{},
-			wantErr: false,
+			want:           []model.Vulnerability{},
+			wantSuppressed: []string{"fec62a97d569662093dbb9739360942fc2a0c47bedec0bfcae05dc9d899d3ebe"},
+			wantErr:        false,
 		},
 	}
 
@@ -333,6 +335,12 @@ func TestInspect(t *testing.T) { //nolint
 				require.Nil(t, err)
 				t.Errorf("Inspector.Inspect() got %v,\nwant %v", gotStrVulnerabilities, wantStrVulnerabilities)
 			}
+			suppressed := c.GetSuppressed()
+			require.Len(t, suppressed, len(tt.wantSuppressed))
+			for i := range suppressed {
+				require.Equal(t, tt.wantSuppressed[i], suppressed[i].SimilarityID)
+				require.Equal(t, model.SuppressionExternal, suppressed[i].Suppression.Kind)
+			}
 		})
 
 		defer func() {
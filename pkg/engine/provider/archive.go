@@ -0,0 +1,245 @@
+package provider
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"compress/gzip"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const archiveDirPrefix = "kics-archive-"
+
+// errArchiveLimit is returned when the extraction of an archive exceeds the limits
+var errArchiveLimit = errors.New("extraction limit exceeded")
+
+// archiveExtensions are the extensions of the archives extracted from the scanned paths
+var archiveExtensions = []string{".zip", ".tar", ".tgz", ".tar.gz"}
+
+// ArchiveLimits are the limits of the extraction of the archives found in the scanned paths
+// Depth is the number of levels of nested archives extracted, no archive is extracted when it is zero
+// MaxSize is the maximum number of bytes extracted from all the archives
+// MaxFiles is the maximum number of files extracted from all the archives
+type ArchiveLimits struct {
+	Depth    int
+	MaxSize  int64
+	MaxFiles int
+}
+
+// archiveBudget keeps the number of bytes and files extracted from the archives
+type archiveBudget struct {
+	limits ArchiveLimits
+	size   int64
+	files  int
+}
+
+// ExtractArchives extracts the archives found in the paths of the provider, and the archives found in their files
+// up to the depth of the limits, the directories of the extracted archives are added to the paths of the provider
+// and returned with the path of their archive. Archives exceeding the limits are not extracted.
+func (s *FileSystemSourceProvider) ExtractArchives(limits ArchiveLimits) map[string]model.ExtractedPathObject {
+	extracted := make(map[string]model.ExtractedPathObject)
+	budget := &archiveBudget{limits: limits}
+	paths := s.paths
+	for depth := 0; depth < limits.Depth && len(paths) > 0; depth++ {
+		nested := make([]string, 0)
+		for _, archive := range s.findArchives(paths) {
+			destination := filepath.Join(os.TempDir(), archiveDirPrefix+nextRandom())
+			if err := budget.extract(archive, destination); err != nil {
+				log.Warn().Msgf("Archive %s not scanned: %s", archive, err)
+				if err := os.RemoveAll(destination); err != nil {
+					log.Err(err).Msgf("Failed to remove %s", destination)
+				}
+				continue
+			}
+			log.Debug().Msgf("Archive %s extracted to %s", archive, destination)
+			extracted[destination] = model.ExtractedPathObject{
+				Path:      archive,
+				LocalPath: true,
+				Archive:   true,
+				Nested:    true,
+			}
+			nested = append(nested, destination)
+		}
+		s.paths = append(s.paths, nested...)
+		paths = nested
+	}
+	return extracted
+}
+
+// MarkArchives marks the local archives of the scanned paths as archives, so the paths of their files are shown as
+// '<archive>!/<file>' like the files of the nested archives, when the nested archives are extracted
+func (e *ExtractedPath) MarkArchives() {
+	for dir, extracted := range e.ExtractionMap {
+		if extracted.LocalPath && !extracted.Nested && isArchive(extracted.Path) && isFile(extracted.Path) {
+			extracted.Archive = true
+			e.ExtractionMap[dir] = extracted
+		}
+	}
+}
+
+// findArchives returns the archives in the paths that are not excluded
+func (s *FileSystemSourceProvider) findArchives(paths []string) []string {
+	archives := make([]string, 0)
+	for _, scanPath := range paths {
+		err := filepath.WalkDir(scanPath, func(filePath string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			if f, ok := s.excludes[info.Name()]; ok && containsFile(f, info) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() && d.Name() == gitDir {
+				return filepath.SkipDir
+			}
+			if d.Type().IsRegular() && isArchive(filePath) {
+				archives = append(archives, filePath)
+			}
+			return nil
+		})
+		if err != nil {
+			log.Err(err).Msgf("Failed to find archives in %s", scanPath)
+		}
+	}
+	return archives
+}
+
+func isArchive(filePath string) bool {
+	name := strings.ToLower(filePath)
+	for _, ext := range archiveExtensions {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// extract extracts the regular files of the archive to the destination, the bytes and files of an archive
+// that can not be extracted are not counted
+func (b *archiveBudget) extract(archive, destination string) error {
+	size, files := b.size, b.files
+	var err error
+	if strings.HasSuffix(strings.ToLower(archive), ".zip") {
+		err = b.extractZip(archive, destination)
+	} else {
+		err = b.extractTar(archive, destination)
+	}
+	if err != nil {
+		b.size, b.files = size, files
+	}
+	return err
+}
+
+func (b *archiveBudget) extractZip(archive, destination string) error {
+	reader, err := zip.OpenReader(archive)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	for _, file := range reader.File {
+		name := cleanEntryName(file.Name)
+		if name == "" || !file.Mode().IsRegular() {
+			continue
+		}
+		content, err := file.Open()
+		if err != nil {
+			return err
+		}
+		err = b.writeFile(filepath.Join(destination, filepath.FromSlash(name)), content)
+		content.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *archiveBudget) extractTar(archive, destination string) error {
+	file, err := os.Open(filepath.Clean(archive))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	var stream io.Reader = reader
+	if magic, err := reader.Peek(len(gzipMagic)); err == nil && string(magic) == gzipMagic {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		stream = gz
+	}
+
+	tr := tar.NewReader(stream)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := cleanEntryName(header.Name)
+		if name == "" || header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := b.writeFile(filepath.Join(destination, filepath.FromSlash(name)), tr); err != nil {
+			return err
+		}
+	}
+}
+
+// writeFile writes the content to the file, counting the bytes actually written so the size declared
+// by the archive entries can not be used to exceed the limits
+func (b *archiveBudget) writeFile(filePath string, content io.Reader) error {
+	if b.files >= b.limits.MaxFiles {
+		return errors.Wrapf(errArchiveLimit, "more than %d files", b.limits.MaxFiles)
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+		return err
+	}
+	file, err := os.Create(filepath.Clean(filePath))
+	if err != nil {
+		return err
+	}
+	written, err := io.Copy(file, io.LimitReader(content, b.limits.MaxSize-b.size+1))
+	b.size += written
+	b.files++
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if b.size > b.limits.MaxSize {
+		return errors.Wrapf(errArchiveLimit, "more than %d bytes", b.limits.MaxSize)
+	}
+	return nil
+}
+
+// cleanEntryName returns the slash separated path of an archive entry relative to the root of the archive,
+// so entries can not escape the root, it returns an empty string for the root
+func cleanEntryName(name string) string {
+	name = path.Clean(strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(name, "\\", "/")), "/"))
+	if name == "." {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+func buildZip(t *testing.T, entries []layerEntry) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, entry := range entries {
+		w, err := zw.Create(entry.name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(entry.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+// writeBundle writes a directory with a tarball containing a chart package and a zip
+func writeBundle(t *testing.T) string {
+	dir := t.TempDir()
+	chart := buildLayer(t, []layerEntry{
+		{name: "app/Chart.yaml", content: "name: app"},
+		{name: "app/templates/deploy.yaml", content: "kind: Deployment"},
+	}, true)
+	lambda := buildZip(t, []layerEntry{
+		{name: "template.yaml", content: "Resources: {}"},
+		{name: "../../escape.yaml", content: "key: escape"},
+	})
+	bundle := buildLayer(t, []layerEntry{
+		{name: "charts/app-1.2.tgz", content: string(chart)},
+		{name: "lambda.zip", content: string(lambda)},
+	}, false)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bundle.tar"), bundle, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(fetchContent), os.ModePerm))
+	return dir
+}
+
+// TestFileSystemSourceProvider_ExtractArchives tests the functions [ExtractArchives()] and all the methods called by them
+func TestFileSystemSourceProvider_ExtractArchives(t *testing.T) {
+	unlimited := ArchiveLimits{MaxSize: 1 << 20, MaxFiles: 100}
+	tests := []struct {
+		name     string
+		depth    int
+		excludes func(dir string) []string
+		limits   ArchiveLimits
+		want     []string
+	}{
+		{
+			name:   "disabled",
+			depth:  0,
+			limits: unlimited,
+			want:   []string{},
+		},
+		{
+			name:   "first_level",
+			depth:  1,
+			limits: unlimited,
+			want:   []string{"bundle.tar!/charts/app-1.2.tgz", "bundle.tar!/lambda.zip"},
+		},
+		{
+			name:   "nested",
+			depth:  3,
+			limits: unlimited,
+			want: []string{
+				"bundle.tar!/charts/app-1.2.tgz",
+				"bundle.tar!/charts/app-1.2.tgz!/app/Chart.yaml",
+				"bundle.tar!/charts/app-1.2.tgz!/app/templates/deploy.yaml",
+				"bundle.tar!/lambda.zip",
+				"bundle.tar!/lambda.zip!/escape.yaml",
+				"bundle.tar!/lambda.zip!/template.yaml",
+			},
+		},
+		{
+			name:  "excluded",
+			depth: 2,
+			excludes: func(dir string) []string {
+				return []string{filepath.Join(dir, "bundle.tar")}
+			},
+			limits: unlimited,
+			want:   []string{},
+		},
+		{
+			name:   "max_files",
+			depth:  2,
+			limits: ArchiveLimits{MaxSize: 1 << 20, MaxFiles: 4},
+			want: []string{
+				"bundle.tar!/charts/app-1.2.tgz",
+				"bundle.tar!/charts/app-1.2.tgz!/app/Chart.yaml",
+				"bundle.tar!/charts/app-1.2.tgz!/app/templates/deploy.yaml",
+				"bundle.tar!/lambda.zip",
+			},
+		},
+		{
+			name:   "max_size",
+			depth:  2,
+			limits: ArchiveLimits{MaxSize: 10, MaxFiles: 100},
+			want:   []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeBundle(t)
+			excludes := []string{}
+			if tt.excludes != nil {
+				excludes = tt.excludes(dir)
+			}
+			s, err := NewFileSystemSourceProvider([]string{dir}, excludes)
+			require.NoError(t, err)
+
+			tt.limits.Depth = tt.depth
+			archives := s.ExtractArchives(tt.limits)
+			t.Cleanup(func() {
+				for extracted := range archives {
+					os.RemoveAll(extracted)
+				}
+			})
+			require.Len(t, s.GetBasePaths(), len(archives)+1)
+
+			got := []string{}
+			for _, basePath := range s.GetBasePaths()[1:] {
+				archive, ok := archives[basePath]
+				require.True(t, ok)
+				require.True(t, archive.Archive && archive.Nested)
+				require.NoError(t, walkTree(basePath, func(path, rel string) error {
+					got = append(got, displayPath(archives, path, dir))
+					return nil
+				}))
+			}
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+// displayPath returns the path of an extracted file as '<archive>!/<file>', relative to the directory
+func displayPath(archives map[string]model.ExtractedPathObject, path, dir string) string {
+	for extracted, archive := range archives {
+		if strings.HasPrefix(path, extracted+string(filepath.Separator)) {
+			rel := filepath.ToSlash(strings.TrimPrefix(path, extracted))
+			return displayPath(archives, archive.Path, dir) + "!" + rel
+		}
+	}
+	rel, _ := filepath.Rel(dir, path)
+	return filepath.ToSlash(rel)
+}
+
+// TestExtractedPath_MarkArchives tests that the scanned archives are only marked as archives by [MarkArchives()]
+func TestExtractedPath_MarkArchives(t *testing.T) {
+	bundle := filepath.Join(writeBundle(t), "bundle.tar")
+	extracted, err := GetSources([]string{bundle}, FetchOptions{})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		for dir := range extracted.ExtractionMap {
+			os.RemoveAll(dir)
+		}
+	})
+	require.Len(t, extracted.ExtractionMap, 1)
+	for _, archive := range extracted.ExtractionMap {
+		require.False(t, archive.Archive, "the paths of the archives keep their format without nested extraction")
+	}
+
+	extracted.MarkArchives()
+	for _, archive := range extracted.ExtractionMap {
+		require.True(t, archive.Archive)
+		require.Equal(t, bundle, archive.Path)
+	}
+}
@@ -29,6 +29,7 @@ const (
 type ExtractedPath struct {
 	Path          []string
 	ExtractionMap map[string]model.ExtractedPathObject
+	RemoveTmp     []string
 }
 
 type getterStruct struct {
@@ -37,18 +38,22 @@ type getterStruct struct {
 	mode        getter.ClientMode
 	pwd         string
 	opts        []getter.ClientOption
+	getters     map[string]getter.Getter
 	destination string
 	source      string
 }
 
 // GetSources goes through the source slice, and determines the of source type (ex: zip, git, local).
 // It than extracts the files to be scanned. If the source given is not local, a temp dir
-// will be created where the files will be stored.
-func GetSources(source []string) (ExtractedPath, error) {
+// will be created where the files will be stored, unless the download is cached.
+// The files of the container images are extracted up to the image limits of the fetch options.
+func GetSources(source []string, fetchOpts FetchOptions) (ExtractedPath, error) {
 	extrStruct := ExtractedPath{
 		Path:          []string{},
 		ExtractionMap: make(map[string]model.ExtractedPathObject),
+		RemoveTmp:     []string{},
 	}
+	budget := &archiveBudget{limits: fetchOpts.ImageLimits}
 	for _, path := range source {
 		destination := filepath.Join(os.TempDir(), "kics-extract-"+nextRandom())
 
@@ -61,7 +66,9 @@ func GetSources(source []string) (ExtractedPath, error) {
 
 		opts := []getter.ClientOption{}
 
-		opts = append(opts, getter.WithInsecure())
+		if fetchOpts.Insecure {
+			opts = append(opts, getter.WithInsecure())
+		}
 
 		ctx, cancel := context.WithCancel(context.Background())
 
@@ -71,17 +78,33 @@ func GetSources(source []string) (ExtractedPath, error) {
 			mode:        mode,
 			pwd:         pwd,
 			opts:        opts,
+			getters:     getters(fetchOpts.Insecure),
 			destination: destination,
 			source:      path,
 		}
 
-		getterDst, err := getPaths(&goGetter)
+		getterDst, err := fetch(&goGetter, fetchOpts)
 		if err != nil {
 			log.Error().Msgf("failed to find path %s: %s", path, err)
+			extrStruct.RemoveTemporaryPaths()
 			return ExtractedPath{}, err
 		}
+		// the destination is only a link to the local sources, or is not used by the cached downloads
+		extrStruct.RemoveTmp = append(extrStruct.RemoveTmp, destination)
 		tempDst, local := checkSymLink(getterDst, path)
 
+		// container image tarballs are scanned through their config and their flattened layer filesystem
+		if isImageLayout(tempDst) {
+			imageDst := filepath.Join(os.TempDir(), "kics-image-"+nextRandom())
+			extrStruct.RemoveTmp = append(extrStruct.RemoveTmp, imageDst)
+			if err := extractImages(tempDst, imageDst, budget); err != nil {
+				log.Error().Msgf("failed to extract image %s: %s", path, err)
+				extrStruct.RemoveTemporaryPaths()
+				return ExtractedPath{}, err
+			}
+			getterDst, tempDst = imageDst, imageDst
+		}
+
 		extrStruct.ExtractionMap[getterDst] = model.ExtractedPathObject{
 			Path:      path,
 			LocalPath: local,
@@ -93,6 +116,17 @@ func GetSources(source []string) (ExtractedPath, error) {
 	return extrStruct, nil
 }
 
+// RemoveTemporaryPaths removes the temporary directories of the sources, the links to local sources are removed
+// without their targets
+func (e *ExtractedPath) RemoveTemporaryPaths() {
+	for _, tmp := range e.RemoveTmp {
+		if err := os.RemoveAll(tmp); err != nil {
+			log.Err(err).Msgf("Failed to remove %s", tmp)
+		}
+	}
+	e.RemoveTmp = nil
+}
+
 func getPaths(g *getterStruct) (string, error) {
 	// Build the client
 	client := &getter.Client{
@@ -102,6 +136,7 @@ func getPaths(g *getterStruct) (string, error) {
 		Pwd:     g.pwd,
 		Mode:    g.mode,
 		Options: g.opts,
+		Getters: g.getters,
 	}
 
 	wg := sync.WaitGroup{}
@@ -162,6 +197,11 @@ func checkSymLink(getterDst, pathFile string) (string, bool) {
 	return getterDst, local
 }
 
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
 func getFileInfo(info fs.FileInfo, dst, pathFile string) fs.FileInfo {
 	var extension = filepath.Ext(pathFile)
 	var path string
@@ -14,6 +14,7 @@ func TestProvider_GetSources(t *testing.T) {
 
 	type args struct {
 		source []string
+		opts   FetchOptions
 	}
 
 	tests := []struct {
@@ -58,15 +59,38 @@ func TestProvider_GetSources(t *testing.T) {
 				source: []string{
 					"test/fixtures/all_auth_users_get_read_access",
 				},
+				opts: FetchOptions{Insecure: true},
 			},
 			wantErr: false,
 			want:    "test/fixtures/all_auth_users_get_read_access",
 		},
+		{
+			name: "test_get_offline_local_sources",
+			args: args{
+				source: []string{
+					"test/fixtures/test_zip.zip",
+				},
+				opts: FetchOptions{Offline: true},
+			},
+			wantErr: false,
+			want:    "test/fixtures/test_zip.zip",
+		},
+		{
+			name: "test_get_offline_remote_error",
+			args: args{
+				source: []string{
+					"git::https://github.com/Checkmarx/kics.git?ref=master",
+				},
+				opts: FetchOptions{Offline: true, CachePath: t.TempDir()},
+			},
+			wantErr: true,
+			want:    "",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := GetSources(tt.args.source)
+			got, err := GetSources(tt.args.source, tt.args.opts)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetSources() = %v, wantErr = %v", err, tt.wantErr)
 			}
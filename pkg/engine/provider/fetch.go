@@ -0,0 +1,350 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-getter"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	cacheRefsDir    = "refs"
+	cacheObjectsDir = "objects"
+	digestAlgorithm = "sha256"
+	gitDir          = ".git"
+)
+
+var (
+	forcedGetterRegex = regexp.MustCompile(`^[A-Za-z0-9]+::`)
+	commitRefRegex    = regexp.MustCompile(`^[0-9a-fA-F]{40}$`)
+	digestRegex       = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+	// credentialParams are the query parameters of the sources that carry credentials
+	credentialParams = []string{"aws_access_key_id", "aws_access_key_secret", "aws_access_token", "sshkey"}
+)
+
+// FetchOptions are the options of the download of the remote sources
+// Insecure disables the TLS verification of the downloads
+// Offline resolves the remote sources only from the download cache and the mirrors
+// RequireChecksum rejects the remote sources that are not pinned by a checksum (or a git commit)
+// CachePath is the directory of the content-addressed download cache, the cache is disabled when empty
+// Mirrors maps the prefixes of the sources to the local directories mirroring them
+// ImageLimits are the limits of the files extracted from the layers of the container images, its depth is unused
+type FetchOptions struct {
+	Insecure        bool
+	Offline         bool
+	RequireChecksum bool
+	CachePath       string
+	Mirrors         map[string]string
+	ImageLimits     ArchiveLimits
+}
+
+// DefaultFetchCachePath returns the directory of the download cache in the user cache directory
+func DefaultFetchCachePath() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		log.Debug().Msgf("Download cache disabled, failed to find the user cache directory: %s", err)
+		return ""
+	}
+	return filepath.Join(cacheDir, "kics", "sources")
+}
+
+// fetch downloads the source to the destination and returns the path of the downloaded files,
+// remote sources are resolved from their mirror or from the download cache when possible
+// Every download is cached, but only the pinned sources are scanned from the cache when online, the content
+// of the others can change between downloads; offline, any cached download is used
+func fetch(g *getterStruct, opts FetchOptions) (string, error) {
+	g.source = mirrorSource(g.source, g.pwd, opts.Mirrors)
+
+	detected, err := getter.Detect(g.source, g.pwd, getter.Detectors)
+	if err != nil || strings.HasPrefix(detected, "file://") {
+		return getPaths(g)
+	}
+
+	warnSourceCredentials(detected)
+	pinned := isPinnedSource(detected)
+	if opts.RequireChecksum && !pinned {
+		return "", fmt.Errorf("%s is not pinned by a checksum or a git commit, required by --fetch-require-checksum",
+			sanitizeSource(detected))
+	}
+	cache := sourceCache(opts.CachePath)
+	if dir, ok := cache.get(detected); ok && (pinned || opts.Offline) {
+		log.Info().Msgf("Using the cached download of %s", sanitizeSource(detected))
+		return dir, nil
+	}
+	if opts.Offline {
+		return "", fmt.Errorf("%s is not in the download cache and can not be fetched in offline mode", sanitizeSource(detected))
+	}
+
+	dst, err := getPaths(g)
+	if err != nil {
+		return "", err
+	}
+	if err := cache.put(detected, dst); err != nil {
+		log.Warn().Msgf("Failed to cache the download of %s: %s", sanitizeSource(detected), err)
+	}
+	return dst, nil
+}
+
+// getters returns the getters of a download, the http getters of go-getter are shared by the downloads and keep
+// the client of the first one, so each download has its own http client to verify (or not) the TLS certificates
+// The git sources are cloned by gitGetter, which does not prompt for credentials
+func getters(insecure bool) map[string]getter.Getter {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if insecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec
+	}
+	httpGetter := &getter.HttpGetter{
+		Netrc:  true,
+		Client: &http.Client{Transport: transport},
+	}
+
+	downloadGetters := make(map[string]getter.Getter, len(getter.Getters))
+	for name, g := range getter.Getters {
+		downloadGetters[name] = g
+	}
+	downloadGetters["http"] = httpGetter
+	downloadGetters["https"] = httpGetter
+	downloadGetters["git"] = &gitGetter{}
+	return downloadGetters
+}
+
+// mirrorSource replaces the prefix of the source by the directory of its mirror, the query of the source
+// (e.g. '?ref=v1.0') is not part of the path of the mirror
+func mirrorSource(source, pwd string, mirrors map[string]string) string {
+	detected, err := getter.Detect(source, pwd, getter.Detectors)
+	if err != nil {
+		detected = source
+	}
+	prefixes := make([]string, 0, len(mirrors))
+	for prefix := range mirrors {
+		prefixes = append(prefixes, prefix)
+	}
+	// the longest prefix is the most specific mirror
+	sort.Slice(prefixes, func(i, j int) bool {
+		return len(prefixes[i]) > len(prefixes[j])
+	})
+	for _, prefix := range prefixes {
+		for _, src := range []string{source, detected} {
+			if strings.HasPrefix(src, prefix) {
+				rel := strings.TrimPrefix(src, prefix)
+				if idx := strings.Index(rel, "?"); idx >= 0 {
+					rel = rel[:idx]
+				}
+				mirrored := filepath.Join(mirrors[prefix], filepath.FromSlash(rel))
+				log.Info().Msgf("Using the mirror %s of %s", mirrored, sanitizeSource(source))
+				return mirrored
+			}
+		}
+	}
+	return source
+}
+
+// isPinnedSource checks if the content of the source can not change, since it is verified by a checksum
+// or it is a git commit, so the cached download can be used
+func isPinnedSource(source string) bool {
+	u, err := url.Parse(forcedGetterRegex.ReplaceAllString(source, ""))
+	if err != nil {
+		return false
+	}
+	query := u.Query()
+	return query.Get("checksum") != "" || commitRefRegex.MatchString(query.Get("ref"))
+}
+
+// warnSourceCredentials warns about the credentials given in the source, which should come from
+// the local config files (git credential helpers, AWS shared credentials, ...)
+func warnSourceCredentials(source string) {
+	u, err := url.Parse(forcedGetterRegex.ReplaceAllString(source, ""))
+	if err != nil {
+		return
+	}
+	_, hasPassword := u.User.Password()
+	for _, param := range credentialParams {
+		hasPassword = hasPassword || u.Query().Get(param) != ""
+	}
+	if hasPassword {
+		log.Warn().Msgf("Credentials of %s should be given by git credential helpers or the cloud provider config files, not in the path",
+			sanitizeSource(source))
+	}
+}
+
+// sanitizeSource removes the credentials of the source
+func sanitizeSource(source string) string {
+	forced := forcedGetterRegex.FindString(source)
+	u, err := url.Parse(strings.TrimPrefix(source, forced))
+	if err != nil {
+		return source
+	}
+	if u.User != nil {
+		u.User = url.User(u.User.Username())
+	}
+	query := u.Query()
+	for _, param := range credentialParams {
+		if query.Get(param) != "" {
+			query.Set(param, "redacted")
+		}
+	}
+	u.RawQuery = query.Encode()
+	return forced + u.String()
+}
+
+// downloadCache is a content-addressed cache of downloads, the files of a download are stored by the digest
+// of their content in 'objects' and the digest of the download of a source is referenced by 'refs'
+type downloadCache struct {
+	path string
+}
+
+func sourceCache(path string) *downloadCache {
+	if path == "" {
+		return nil
+	}
+	return &downloadCache{path: path}
+}
+
+func (c *downloadCache) refPath(source string) string {
+	sum := sha256.Sum256([]byte(source))
+	return filepath.Join(c.path, cacheRefsDir, hex.EncodeToString(sum[:]))
+}
+
+func (c *downloadCache) objectPath(digest string) string {
+	return filepath.Join(c.path, cacheObjectsDir, filepath.FromSlash(strings.Replace(digest, ":", "/", 1)))
+}
+
+// get returns the directory of the cached download of the source, when its content matches its digest
+func (c *downloadCache) get(source string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	ref, err := os.ReadFile(c.refPath(source))
+	if err != nil {
+		return "", false
+	}
+	digest := strings.TrimSpace(string(ref))
+	if !digestRegex.MatchString(digest) {
+		return "", false
+	}
+	dir := c.objectPath(digest)
+	got, err := treeDigest(dir)
+	if err != nil || got != digest {
+		log.Warn().Msgf("Ignoring the cached download of %s, its content does not match the digest %s", sanitizeSource(source), digest)
+		return "", false
+	}
+	return dir, true
+}
+
+// put stores the files of the download of the source in the cache
+func (c *downloadCache) put(source, dir string) error {
+	if c == nil {
+		return nil
+	}
+	tmp := filepath.Join(c.path, "tmp-"+nextRandom())
+	defer os.RemoveAll(tmp)
+	if err := copyTree(dir, tmp); err != nil {
+		return err
+	}
+	digest, err := treeDigest(tmp)
+	if err != nil {
+		return err
+	}
+	object := c.objectPath(digest)
+	if _, err := os.Stat(object); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(object), os.ModePerm); err != nil {
+			return err
+		}
+		if err := os.Rename(tmp, object); err != nil {
+			return err
+		}
+	}
+
+	ref := c.refPath(source)
+	if err := os.MkdirAll(filepath.Dir(ref), os.ModePerm); err != nil {
+		return err
+	}
+	tmpRef := ref + ".tmp-" + nextRandom()
+	if err := os.WriteFile(tmpRef, []byte(digest), os.ModePerm); err != nil {
+		return err
+	}
+	return os.Rename(tmpRef, ref)
+}
+
+// walkTree calls fn with the regular files of the directory, in lexical order,
+// the git metadata and the symbolic links are not part of the tree
+func walkTree(dir string, fn func(path, rel string) error) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == gitDir {
+			return filepath.SkipDir
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		return fn(path, filepath.ToSlash(rel))
+	})
+}
+
+// treeDigest returns the digest of the paths and the content of the files of the directory
+func treeDigest(dir string) (string, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return "", err
+	}
+	tree := sha256.New()
+	err := walkTree(dir, func(path, rel string) error {
+		file, err := os.Open(filepath.Clean(path))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		content := sha256.New()
+		if _, err := io.Copy(content, file); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(tree, "%s\x00%x\n", rel, content.Sum(nil))
+		return err
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to digest %s", dir)
+	}
+	return digestAlgorithm + ":" + hex.EncodeToString(tree.Sum(nil)), nil
+}
+
+// copyTree copies the files of the tree of the directory to the destination
+func copyTree(dir, destination string) error {
+	return walkTree(dir, func(path, rel string) error {
+		target := filepath.Join(destination, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+			return err
+		}
+		src, err := os.Open(filepath.Clean(path))
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		dst, err := os.Create(filepath.Clean(target))
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(dst, src); err != nil {
+			dst.Close()
+			return err
+		}
+		return dst.Close()
+	})
+}
@@ -166,7 +166,7 @@ func (s *FileSystemSourceProvider) walkDir(ctx context.Context, scanPath string,
 }
 
 func openScanFile(scanPath string, extensions model.Extensions) (*os.File, error) {
-	if !extensions.Include(filepath.Ext(scanPath)) && !extensions.Include(filepath.Base(scanPath)) {
+	if !extensions.Include(model.FileExtension(scanPath)) && !extensions.Include(filepath.Base(scanPath)) {
 		return nil, ErrNotSupportedFile
 	}
 
@@ -206,7 +206,7 @@ func (s *FileSystemSourceProvider) checkConditions(info os.FileInfo, extensions
 		log.Trace().Msgf("File ignored: %s", path)
 		return true, nil
 	}
-	if !extensions.Include(filepath.Ext(path)) && !extensions.Include(filepath.Base(path)) {
+	if !extensions.Include(model.FileExtension(path)) && !extensions.Include(filepath.Base(path)) {
 		return true, nil
 	}
 	return false, nil
@@ -0,0 +1,163 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-getter"
+	"github.com/pkg/errors"
+)
+
+const (
+	gitTerminalPrompt = "GIT_TERMINAL_PROMPT"
+	gitSSHCommand     = "GIT_SSH_COMMAND"
+)
+
+// gitGetter clones the git sources like the git getter of go-getter, with the environment of the git commands
+// set by gitEnv instead of the environment of the process
+type gitGetter struct {
+	getter.GitGetter
+}
+
+// Get clones the repository of the URL to the destination, on the branch, tag or commit of its 'ref' parameter
+func (g *gitGetter) Get(dst string, u *url.URL) error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return fmt.Errorf("git must be available and on the PATH")
+	}
+
+	query := u.Query()
+	ref, sshKey := query.Get("ref"), query.Get("sshkey")
+	depth, _ := strconv.Atoi(query.Get("depth"))
+	for _, param := range []string{"ref", "sshkey", "depth"} {
+		query.Del(param)
+	}
+	repo := *u
+	repo.RawQuery = query.Encode()
+
+	sshKeyFile, err := writeSSHKey(sshKey)
+	if err != nil {
+		return err
+	}
+	if sshKeyFile != "" {
+		defer os.Remove(sshKeyFile)
+	}
+	env := gitEnv(sshKeyFile)
+
+	args := []string{"clone"}
+	// commits can not be cloned as a branch, they are checked out after the clone of the whole history
+	isCommit := commitRefRegex.MatchString(ref)
+	if depth > 0 && !isCommit {
+		args = append(args, "--depth", strconv.Itoa(depth))
+	}
+	if ref != "" && !isCommit {
+		args = append(args, "--branch", ref)
+	}
+	if err := runGit(env, "", append(args, "--", repo.String(), dst)...); err != nil {
+		return err
+	}
+	if isCommit {
+		if err := runGit(env, dst, "checkout", "--detach", ref); err != nil {
+			return err
+		}
+	}
+
+	args = []string{"submodule", "update", "--init", "--recursive"}
+	if depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(depth))
+	}
+	return runGit(env, dst, args...)
+}
+
+// GetFile clones the repository of the directory of the URL and copies the file
+func (g *gitGetter) GetFile(dst string, u *url.URL) error {
+	tmp, err := os.MkdirTemp("", "kics-git-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	repo := *u
+	filename := filepath.Base(repo.Path)
+	repo.Path = filepath.ToSlash(filepath.Dir(repo.Path))
+	if err := g.Get(filepath.Join(tmp, "repo"), &repo); err != nil {
+		return err
+	}
+
+	fileURL, err := url.Parse("file://" + filepath.ToSlash(filepath.Join(tmp, "repo", filename)))
+	if err != nil {
+		return err
+	}
+	return (&getter.FileGetter{Copy: true}).GetFile(dst, fileURL)
+}
+
+// gitEnv returns the environment of the git commands, the credentials come from the git credential helpers,
+// so git must not wait for a prompt, and the ssh key of the source is given to ssh
+func gitEnv(sshKeyFile string) []string {
+	env := os.Environ()
+	if _, ok := os.LookupEnv(gitTerminalPrompt); !ok {
+		env = append(env, gitTerminalPrompt+"=0")
+	}
+	if sshKeyFile == "" {
+		return env
+	}
+
+	if runtime.GOOS == "windows" {
+		sshKeyFile = strings.ReplaceAll(sshKeyFile, `\`, `/`)
+	}
+	sshCommand := "ssh"
+	for i := range env {
+		if value := strings.TrimPrefix(env[i], gitSSHCommand+"="); value != env[i] && value != "" {
+			sshCommand = value
+			env = append(env[:i], env[i+1:]...)
+			break
+		}
+	}
+	return append(env, fmt.Sprintf("%s=%s -i %s", gitSSHCommand, sshCommand, sshKeyFile))
+}
+
+// writeSSHKey writes the base64 encoded ssh key of the source to a temporary file, readable only by the user
+func writeSSHKey(sshKey string) (string, error) {
+	if sshKey == "" {
+		return "", nil
+	}
+	raw, err := base64.StdEncoding.DecodeString(sshKey)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid sshkey")
+	}
+	file, err := os.CreateTemp("", "kics-sshkey-")
+	if err != nil {
+		return "", err
+	}
+	if err := file.Chmod(0600); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return "", err
+	}
+	if _, err := file.Write(raw); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), file.Close()
+}
+
+func runGit(env []string, dir string, args ...string) error {
+	cmd := exec.Command("git", args...) //nolint:gosec
+	cmd.Dir = dir
+	cmd.Env = env
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Run(); err != nil {
+		return errors.Wrapf(err, "git %s failed: %s", args[0], strings.TrimSpace(output.String()))
+	}
+	return nil
+}
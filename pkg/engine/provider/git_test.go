@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test_gitGetter tests the functions [Get()] of the git getter, on a branch and on a commit, without changing the
+// environment of the process
+func Test_gitGetter(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo := t.TempDir()
+	git := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=Dev", "-c", "user.email=dev@example.com"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+		return strings.TrimSpace(string(out))
+	}
+	git("init", "-q", "-b", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "main.tf"), []byte(fetchContent), os.ModePerm))
+	git("add", ".")
+	git("commit", "-q", "-m", "first")
+	first := git("rev-parse", "HEAD")
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "main.tf"), []byte("# changed\n"), os.ModePerm))
+	git("commit", "-q", "-am", "second")
+
+	t.Setenv(gitTerminalPrompt, "")
+	os.Unsetenv(gitTerminalPrompt)
+
+	for ref, want := range map[string]string{"main": "# changed\n", first: fetchContent} {
+		got, err := GetSources([]string{"git::file://" + filepath.ToSlash(repo) + "?ref=" + ref}, FetchOptions{})
+		require.NoError(t, err, ref)
+		content, err := os.ReadFile(filepath.Join(got.Path[0], "main.tf"))
+		require.NoError(t, err)
+		require.Equal(t, want, string(content), ref)
+	}
+	_, ok := os.LookupEnv(gitTerminalPrompt)
+	require.False(t, ok, "the environment of the process must not change")
+}
+
+// Test_gitEnv tests the functions [gitEnv()]
+func Test_gitEnv(t *testing.T) {
+	t.Setenv(gitSSHCommand, "ssh -o StrictHostKeyChecking=yes")
+	env := gitEnv("/tmp/key")
+	require.Contains(t, env, gitTerminalPrompt+"=0")
+	require.Contains(t, env, gitSSHCommand+"=ssh -o StrictHostKeyChecking=yes -i /tmp/key")
+	require.NotContains(t, env, gitSSHCommand+"=ssh -o StrictHostKeyChecking=yes")
+
+	t.Setenv(gitTerminalPrompt, "1")
+	require.NotContains(t, gitEnv(""), gitTerminalPrompt+"=0")
+}
@@ -0,0 +1,431 @@
+package provider
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	dockerManifestFile  = "manifest.json"
+	ociLayoutFile       = "oci-layout"
+	ociIndexFile        = "index.json"
+	ociIndexMediaType   = "application/vnd.oci.image.index.v1+json"
+	dockerListMediaType = "application/vnd.docker.distribution.manifest.list.v2+json"
+	imageDockerfile     = "image-config.dockerfile"
+	whiteoutPrefix      = ".wh."
+	whiteoutOpaque      = ".wh..wh..opq"
+	gzipMagic           = "\x1f\x8b"
+)
+
+var imageNameRegex = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// dockerManifest is an image of a 'docker save' tarball, described in its manifest.json
+type dockerManifest struct {
+	Config   string
+	RepoTags []string
+	Layers   []string
+}
+
+// ociDescriptor references a blob of an OCI image layout
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+type ociIndex struct {
+	Manifests []ociDescriptor `json:"manifests"`
+}
+
+type ociManifest struct {
+	Config ociDescriptor   `json:"config"`
+	Layers []ociDescriptor `json:"layers"`
+}
+
+// imageConfig is the configuration of a container image, the properties of the image a Dockerfile would set
+type imageConfig struct {
+	Config struct {
+		User         string              `json:"User"`
+		ExposedPorts map[string]struct{} `json:"ExposedPorts"`
+		Env          []string            `json:"Env"`
+		Entrypoint   []string            `json:"Entrypoint"`
+		Cmd          []string            `json:"Cmd"`
+		WorkingDir   string              `json:"WorkingDir"`
+		Labels       map[string]string   `json:"Labels"`
+		Healthcheck  *struct {
+			Test        []string      `json:"Test"`
+			Interval    time.Duration `json:"Interval"`
+			Timeout     time.Duration `json:"Timeout"`
+			StartPeriod time.Duration `json:"StartPeriod"`
+			Retries     int           `json:"Retries"`
+		} `json:"Healthcheck"`
+	} `json:"config"`
+	RootFS struct {
+		DiffIDs []string `json:"diff_ids"`
+	} `json:"rootfs"`
+}
+
+// imageLayer is a layer of an image, the path of its blob in the layout and its digest
+type imageLayer struct {
+	path   string
+	digest string
+}
+
+// containerImage is an image of an image tarball
+type containerImage struct {
+	name   string
+	config imageConfig
+	layers []imageLayer
+}
+
+// isImageLayout checks if the directory is an image layout extracted from a 'docker save' or an OCI tarball
+func isImageLayout(dir string) bool {
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return false
+	}
+	if manifests, err := readDockerManifests(dir); err == nil && len(manifests) > 0 {
+		return true
+	}
+	_, errLayout := os.Stat(filepath.Join(dir, ociLayoutFile))
+	_, errIndex := os.Stat(filepath.Join(dir, ociIndexFile))
+	return errLayout == nil && errIndex == nil
+}
+
+// extractImages writes, for each image of the layout, a Dockerfile equivalent to the image config and the
+// files of the flattened layer filesystem, under a directory named by the digest of the layer they come from.
+// The files of the layers are counted by the budget, like the files of the archives
+func extractImages(layoutDir, destination string, budget *archiveBudget) error {
+	images, err := readImages(layoutDir)
+	if err != nil {
+		return err
+	}
+	for _, img := range images {
+		imageDst := destination
+		if len(images) > 1 {
+			imageDst = filepath.Join(destination, imageNameRegex.ReplaceAllString(img.name, "_"))
+		}
+		if err := os.MkdirAll(imageDst, os.ModePerm); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(imageDst, imageDockerfile), []byte(img.dockerfile()), os.ModePerm); err != nil {
+			return err
+		}
+		if err := flattenLayers(layoutDir, img.layers, imageDst, budget); err != nil {
+			return errors.Wrapf(err, "failed to extract layers of image %s", img.name)
+		}
+	}
+	return nil
+}
+
+func readDockerManifests(dir string) ([]dockerManifest, error) {
+	content, err := os.ReadFile(filepath.Join(dir, dockerManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	var manifests []dockerManifest
+	if err := json.Unmarshal(content, &manifests); err != nil {
+		return nil, err
+	}
+	for _, manifest := range manifests {
+		if manifest.Config == "" || len(manifest.Layers) == 0 {
+			return nil, errors.New("not a docker image manifest")
+		}
+	}
+	return manifests, nil
+}
+
+// readImages reads the images of the layout, the manifest.json of 'docker save' is preferred to the OCI index
+func readImages(dir string) ([]containerImage, error) {
+	if manifests, err := readDockerManifests(dir); err == nil {
+		images := make([]containerImage, 0, len(manifests))
+		for _, manifest := range manifests {
+			img := containerImage{name: strings.TrimSuffix(path.Base(manifest.Config), ".json")}
+			if len(manifest.RepoTags) > 0 {
+				img.name = manifest.RepoTags[0]
+			}
+			if err := readJSON(filepath.Join(dir, filepath.FromSlash(manifest.Config)), &img.config); err != nil {
+				return nil, err
+			}
+			for idx, layer := range manifest.Layers {
+				digest := blobDigest(layer)
+				if digest == "" && idx < len(img.config.RootFS.DiffIDs) {
+					digest = img.config.RootFS.DiffIDs[idx]
+				}
+				img.layers = append(img.layers, imageLayer{path: layer, digest: digest})
+			}
+			images = append(images, img)
+		}
+		return images, nil
+	}
+
+	var index ociIndex
+	if err := readJSON(filepath.Join(dir, ociIndexFile), &index); err != nil {
+		return nil, err
+	}
+	return readOCIManifests(dir, index.Manifests, "")
+}
+
+// readOCIManifests reads the images of the manifests, nested indexes (multi-platform images) are followed
+func readOCIManifests(dir string, descriptors []ociDescriptor, name string) ([]containerImage, error) {
+	images := make([]containerImage, 0, len(descriptors))
+	for _, descriptor := range descriptors {
+		imageName := descriptor.Annotations["org.opencontainers.image.ref.name"]
+		if imageName == "" {
+			imageName = name
+		}
+		if descriptor.MediaType == ociIndexMediaType || descriptor.MediaType == dockerListMediaType {
+			var index ociIndex
+			if err := readJSON(blobPath(dir, descriptor.Digest), &index); err != nil {
+				return nil, err
+			}
+			nested, err := readOCIManifests(dir, index.Manifests, imageName)
+			if err != nil {
+				return nil, err
+			}
+			images = append(images, nested...)
+			continue
+		}
+
+		var manifest ociManifest
+		if err := readJSON(blobPath(dir, descriptor.Digest), &manifest); err != nil {
+			return nil, err
+		}
+		img := containerImage{name: descriptor.Digest}
+		if imageName != "" && len(descriptors) == 1 {
+			img.name = imageName
+		}
+		if err := readJSON(blobPath(dir, manifest.Config.Digest), &img.config); err != nil {
+			return nil, err
+		}
+		for _, layer := range manifest.Layers {
+			img.layers = append(img.layers, imageLayer{path: blobRelativePath(layer.Digest), digest: layer.Digest})
+		}
+		images = append(images, img)
+	}
+	return images, nil
+}
+
+func readJSON(filePath string, v interface{}) error {
+	content, err := os.ReadFile(filepath.Clean(filePath))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, v)
+}
+
+func blobRelativePath(digest string) string {
+	return path.Join("blobs", strings.Replace(digest, ":", "/", 1))
+}
+
+func blobPath(dir, digest string) string {
+	return filepath.Join(dir, filepath.FromSlash(blobRelativePath(digest)))
+}
+
+// blobDigest returns the digest of a layer stored as a blob ('blobs/sha256/<hex>'), empty otherwise
+func blobDigest(layerPath string) string {
+	parts := strings.Split(layerPath, "/")
+	if len(parts) == 3 && parts[0] == "blobs" {
+		return parts[1] + ":" + parts[2]
+	}
+	return ""
+}
+
+// layerDir is the directory of the files of the layer, the digest with no ':' so it is a valid path on every OS
+func layerDir(layer imageLayer) string {
+	if layer.digest == "" {
+		return imageNameRegex.ReplaceAllString(layer.path, "_")
+	}
+	return strings.Replace(layer.digest, ":", "-", 1)
+}
+
+// flattenLayers extracts the files of the image filesystem, the files removed or replaced by upper layers
+// (including the whiteouts) are not extracted
+func flattenLayers(layoutDir string, layers []imageLayer, destination string, budget *archiveBudget) error {
+	owners := make(map[string]int)
+	for idx, layer := range layers {
+		err := walkLayer(layoutDir, layer, func(name string, header *tar.Header, _ io.Reader) error {
+			base, dir := path.Base(name), path.Dir(name)
+			switch {
+			case base == whiteoutOpaque:
+				removeOwners(owners, dir+"/", idx)
+			case strings.HasPrefix(base, whiteoutPrefix):
+				target := path.Join(dir, strings.TrimPrefix(base, whiteoutPrefix))
+				delete(owners, target)
+				removeOwners(owners, target+"/", idx)
+			case header.Typeflag == tar.TypeReg:
+				owners[name] = idx
+			case header.Typeflag != tar.TypeDir:
+				delete(owners, name)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for idx, layer := range layers {
+		err := walkLayer(layoutDir, layer, func(name string, header *tar.Header, content io.Reader) error {
+			if owner, ok := owners[name]; !ok || owner != idx || header.Typeflag != tar.TypeReg {
+				return nil
+			}
+			return budget.writeFile(filepath.Join(destination, layerDir(layer), filepath.FromSlash(name)), content)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeOwners removes the files of the directory (prefix) added by lower layers
+func removeOwners(owners map[string]int, prefix string, layer int) {
+	for name, owner := range owners {
+		if owner < layer && strings.HasPrefix(name, prefix) {
+			delete(owners, name)
+		}
+	}
+}
+
+// walkLayer calls fn for each entry of the layer tarball (uncompressed or gzip), entries escaping the root are ignored
+func walkLayer(layoutDir string, layer imageLayer, fn func(name string, header *tar.Header, content io.Reader) error) error {
+	file, err := os.Open(filepath.Join(layoutDir, filepath.FromSlash(layer.path)))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil {
+			log.Err(closeErr).Msgf("Failed to close layer %s", layer.path)
+		}
+	}()
+
+	reader := bufio.NewReader(file)
+	var stream io.Reader = reader
+	if magic, err := reader.Peek(len(gzipMagic)); err == nil && string(magic) == gzipMagic {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		stream = gz
+	}
+
+	tr := tar.NewReader(stream)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to read layer %s", layer.path)
+		}
+		name := cleanEntryName(header.Name)
+		if name == "" {
+			continue
+		}
+		if err := fn(name, header, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// dockerfile returns a Dockerfile equivalent to the image config, so the Dockerfile queries check the image
+func (img *containerImage) dockerfile() string {
+	config := img.config.Config
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# configuration of the image %s\n", img.name)
+	fmt.Fprintf(&sb, "FROM %s\n", img.name)
+	if config.User != "" {
+		fmt.Fprintf(&sb, "USER %s\n", config.User)
+	}
+	if config.WorkingDir != "" {
+		fmt.Fprintf(&sb, "WORKDIR %s\n", config.WorkingDir)
+	}
+	for _, env := range config.Env {
+		parts := strings.SplitN(env, "=", 2)
+		if len(parts) == 2 {
+			fmt.Fprintf(&sb, "ENV %s=%q\n", parts[0], parts[1])
+		}
+	}
+	ports := make([]string, 0, len(config.ExposedPorts))
+	for port := range config.ExposedPorts {
+		// tcp is the default protocol of the 'EXPOSE' instruction
+		ports = append(ports, strings.TrimSuffix(port, "/tcp"))
+	}
+	sort.Strings(ports)
+	for _, port := range ports {
+		fmt.Fprintf(&sb, "EXPOSE %s\n", port)
+	}
+	labels := make([]string, 0, len(config.Labels))
+	for label := range config.Labels {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	for _, label := range labels {
+		fmt.Fprintf(&sb, "LABEL %q=%q\n", label, config.Labels[label])
+	}
+	if healthcheck := config.Healthcheck; healthcheck != nil && len(healthcheck.Test) > 0 {
+		sb.WriteString("HEALTHCHECK")
+		if healthcheck.Test[0] == "NONE" {
+			sb.WriteString(" NONE\n")
+		} else {
+			flags := []struct {
+				name  string
+				value time.Duration
+			}{
+				{name: "interval", value: healthcheck.Interval},
+				{name: "timeout", value: healthcheck.Timeout},
+				{name: "start-period", value: healthcheck.StartPeriod},
+			}
+			for _, flag := range flags {
+				if flag.value > 0 {
+					fmt.Fprintf(&sb, " --%s=%s", flag.name, flag.value)
+				}
+			}
+			if healthcheck.Retries > 0 {
+				fmt.Fprintf(&sb, " --retries=%d", healthcheck.Retries)
+			}
+			fmt.Fprintf(&sb, " CMD %s\n", healthcheckCommand(healthcheck.Test))
+		}
+	}
+	if len(config.Entrypoint) > 0 {
+		fmt.Fprintf(&sb, "ENTRYPOINT %s\n", jsonArray(config.Entrypoint))
+	}
+	if len(config.Cmd) > 0 {
+		fmt.Fprintf(&sb, "CMD %s\n", jsonArray(config.Cmd))
+	}
+	return sb.String()
+}
+
+// healthcheckCommand returns the command of the healthcheck test, in shell form for 'CMD-SHELL' tests
+func healthcheckCommand(test []string) string {
+	if test[0] == "CMD-SHELL" && len(test) > 1 {
+		return strings.Join(test[1:], " ")
+	}
+	if test[0] == "CMD" {
+		return jsonArray(test[1:])
+	}
+	return jsonArray(test)
+}
+
+func jsonArray(values []string) string {
+	content, err := json.Marshal(values)
+	if err != nil {
+		return "[]"
+	}
+	return string(content)
+}
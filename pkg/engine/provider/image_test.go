@@ -0,0 +1,180 @@
+package provider
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Checkmarx/kics/test"
+	"github.com/stretchr/testify/require"
+)
+
+// TestProvider_GetSourcesImage tests the functions [GetSources()] with a 'docker save' tarball
+func TestProvider_GetSourcesImage(t *testing.T) {
+	if err := test.ChangeCurrentDir("kics"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := GetSources([]string{filepath.FromSlash("test/fixtures/test_image/image.tar")},
+		FetchOptions{ImageLimits: ArchiveLimits{MaxSize: 1 << 20, MaxFiles: 10}})
+	require.NoError(t, err)
+	require.Len(t, got.Path, 1)
+	imageDir := got.Path[0]
+	t.Cleanup(got.RemoveTemporaryPaths)
+	require.True(t, strings.HasPrefix(filepath.Base(imageDir), "kics-image-"))
+	require.Equal(t, filepath.FromSlash("test/fixtures/test_image/image.tar"), got.ExtractionMap[imageDir].Path)
+
+	dockerfile, err := os.ReadFile(filepath.Join(imageDir, imageDockerfile))
+	require.NoError(t, err)
+	require.Contains(t, string(dockerfile), "FROM app:1.0\n")
+	require.Contains(t, string(dockerfile), "EXPOSE 22\n")
+	require.NotContains(t, string(dockerfile), "USER")
+
+	lowerLayer := "sha256-820b0fcb5813c6975e6330caa30887193f1c3e754631cf1a187bf3d43470d841"
+	upperLayer := "sha256-b60e441b0139c19f78bf1ed6daef8b2218638f8aac842b66f5e37b16f561423e"
+	require.FileExists(t, filepath.Join(imageDir, lowerLayer, "etc", "app", "pod.yaml"))
+	require.FileExists(t, filepath.Join(imageDir, upperLayer, "app", "main.tf"))
+	// removed by a whiteout of the upper layer
+	require.NoFileExists(t, filepath.Join(imageDir, lowerLayer, "app", "old.tf"))
+
+	// the extract directory of the tarball is removed with the directory of the image
+	removed := got.RemoveTmp
+	require.Len(t, removed, 2)
+	require.Contains(t, removed, imageDir)
+	got.RemoveTemporaryPaths()
+	for _, tmp := range removed {
+		require.NoDirExists(t, tmp)
+	}
+
+	_, err = GetSources([]string{filepath.FromSlash("test/fixtures/test_image/image.tar")},
+		FetchOptions{ImageLimits: ArchiveLimits{MaxSize: 1 << 20, MaxFiles: 1}})
+	require.ErrorIs(t, err, errArchiveLimit)
+}
+
+type layerEntry struct {
+	name    string
+	content string
+}
+
+func buildLayer(t *testing.T, entries []layerEntry, compress bool) []byte {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, entry := range entries {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     entry.name,
+			Mode:     0o644,
+			Size:     int64(len(entry.content)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tw.Write([]byte(entry.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	if !compress {
+		return buf.Bytes()
+	}
+	var gz bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	_, err := gw.Write(buf.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+	return gz.Bytes()
+}
+
+func writeBlob(t *testing.T, dir string, content []byte) string {
+	sum := sha256.Sum256(content)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "blobs", "sha256"), os.ModePerm))
+	require.NoError(t, os.WriteFile(blobPath(dir, digest), content, os.ModePerm))
+	return digest
+}
+
+func writeJSONBlob(t *testing.T, dir string, v interface{}) string {
+	content, err := json.Marshal(v)
+	require.NoError(t, err)
+	return writeBlob(t, dir, content)
+}
+
+// Test_extractImagesOCI tests the functions [extractImages()] with an OCI layout of a multi-platform image
+func Test_extractImagesOCI(t *testing.T) {
+	layoutDir := t.TempDir()
+	destination := t.TempDir()
+
+	lower := writeBlob(t, layoutDir, buildLayer(t, []layerEntry{
+		{name: "config/app.yaml", content: "key: lower"},
+		{name: "config/other.yaml", content: "key: other"},
+		{name: "/../../escape.yaml", content: "key: escape"},
+	}, true))
+	upper := writeBlob(t, layoutDir, buildLayer(t, []layerEntry{
+		{name: "config/.wh..wh..opq"},
+		{name: "config/app.yaml", content: "key: upper"},
+	}, false))
+
+	config := map[string]interface{}{
+		"config": map[string]interface{}{
+			"User": "1000",
+			"Healthcheck": map[string]interface{}{
+				"Test":     []string{"CMD-SHELL", "curl -f http://localhost/"},
+				"Interval": 30 * time.Second,
+				"Retries":  3,
+			},
+		},
+	}
+	manifests := make([]ociDescriptor, 0, 2)
+	for range []string{"amd64", "arm64"} {
+		manifests = append(manifests, ociDescriptor{
+			MediaType: "application/vnd.oci.image.manifest.v1+json",
+			Digest: writeJSONBlob(t, layoutDir, ociManifest{
+				Config: ociDescriptor{Digest: writeJSONBlob(t, layoutDir, config)},
+				Layers: []ociDescriptor{{Digest: lower}, {Digest: upper}},
+			}),
+		})
+		config["architecture"] = "arm64"
+	}
+	nested := writeJSONBlob(t, layoutDir, ociIndex{Manifests: manifests})
+	index := ociIndex{Manifests: []ociDescriptor{{
+		MediaType:   ociIndexMediaType,
+		Digest:      nested,
+		Annotations: map[string]string{"org.opencontainers.image.ref.name": "app:1.0"},
+	}}}
+	content, err := json.Marshal(index)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(layoutDir, ociIndexFile), content, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(layoutDir, ociLayoutFile), []byte(`{"imageLayoutVersion":"1.0.0"}`), os.ModePerm))
+
+	require.True(t, isImageLayout(layoutDir))
+	require.NoError(t, extractImages(layoutDir, destination, &archiveBudget{limits: ArchiveLimits{MaxSize: 1 << 20, MaxFiles: 10}}))
+
+	for _, manifest := range manifests {
+		imageDir := filepath.Join(destination, imageNameRegex.ReplaceAllString(manifest.Digest, "_"))
+		dockerfile, err := os.ReadFile(filepath.Join(imageDir, imageDockerfile))
+		require.NoError(t, err)
+		require.Contains(t, string(dockerfile), "USER 1000\n")
+		require.Contains(t, string(dockerfile), "HEALTHCHECK --interval=30s --retries=3 CMD curl -f http://localhost/\n")
+
+		upperContent, err := os.ReadFile(filepath.Join(imageDir, strings.Replace(upper, ":", "-", 1), "config", "app.yaml"))
+		require.NoError(t, err)
+		require.Equal(t, "key: upper", string(upperContent))
+		require.NoDirExists(t, filepath.Join(imageDir, strings.Replace(lower, ":", "-", 1), "config"))
+		require.FileExists(t, filepath.Join(imageDir, strings.Replace(lower, ":", "-", 1), "escape.yaml"))
+	}
+	require.NoFileExists(t, filepath.Join(filepath.Dir(destination), "escape.yaml"))
+}
+
+// Test_isImageLayout tests the functions [isImageLayout()] with directories that are not image layouts
+func Test_isImageLayout(t *testing.T) {
+	dir := t.TempDir()
+	require.False(t, isImageLayout(dir))
+	require.False(t, isImageLayout(filepath.Join(dir, "not_found")))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, dockerManifestFile), []byte(`{"name": "package"}`), os.ModePerm))
+	require.False(t, isImageLayout(dir))
+}
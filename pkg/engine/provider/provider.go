@@ -20,3 +20,11 @@ type SourceProvider interface {
 	GetBasePaths() []string
 	GetSources(ctx context.Context, extensions model.Extensions, sink Sink, resolverSink ResolverSink) error
 }
+
+// TextSourceProvider is the interface of the source providers that can provide every text file of their paths,
+// regardless of the extensions supported by the parsers
+// GetTextSources receives context, a filter of the files and a sink function to save sources
+type TextSourceProvider interface {
+	SourceProvider
+	GetTextSources(ctx context.Context, filter TextFilter, sink Sink) error
+}
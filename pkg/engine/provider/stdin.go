@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"path/filepath"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/pkg/errors"
+)
+
+// StdinPath is the path given to scan the content of the standard input
+const StdinPath = "-"
+
+// StdinSourceProvider provides the content of the standard input as a file with a virtual filename,
+// the filename selects the parser of the content and is the file referenced by the results
+type StdinSourceProvider struct {
+	filename string
+	content  []byte
+}
+
+// IsStdin checks if the paths to scan are the standard input
+func IsStdin(paths []string) bool {
+	for _, path := range paths {
+		if path == StdinPath {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadStdin reads the content of the reader up to maxSize bytes, the content is read once since every parser
+// of the scan gets the same content
+func ReadStdin(reader io.Reader, maxSize int64) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(reader, maxSize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read stdin")
+	}
+	if int64(len(content)) > maxSize {
+		return nil, errors.Errorf("stdin has more than %d bytes", maxSize)
+	}
+	return content, nil
+}
+
+// NewStdinSourceProvider returns the provider of the content read from stdin, with its virtual filename
+func NewStdinSourceProvider(content []byte, filename string) *StdinSourceProvider {
+	return &StdinSourceProvider{
+		filename: filename,
+		content:  content,
+	}
+}
+
+// GetBasePaths returns the virtual filename of the content
+func (s *StdinSourceProvider) GetBasePaths() []string {
+	return []string{s.filename}
+}
+
+// GetSources executes the sink function with the content when the virtual filename is supported by the extensions,
+// the YAML content is split in its documents, each one sunk as soon as it is read
+func (s *StdinSourceProvider) GetSources(ctx context.Context,
+	extensions model.Extensions, sink Sink, resolverSink ResolverSink) error {
+	if !extensions.Include(model.FileExtension(s.filename)) && !extensions.Include(filepath.Base(s.filename)) {
+		return nil
+	}
+	if ext := model.FileExtension(s.filename); ext != ".yaml" && ext != ".yml" {
+		return sink(ctx, s.filename, io.NopCloser(bytes.NewReader(s.content)))
+	}
+	return s.sinkDocuments(ctx, sink)
+}
+
+// sinkDocuments executes the sink function with each YAML document of the content, the document is preceded by
+// empty lines so the lines of its results are the lines of stdin
+func (s *StdinSourceProvider) sinkDocuments(ctx context.Context, sink Sink) error {
+	reader := bufio.NewReader(bytes.NewReader(s.content))
+	document := &bytes.Buffer{}
+	start, lines := 0, 0
+	flush := func() error {
+		if len(bytes.TrimSpace(document.Bytes())) == 0 {
+			return nil
+		}
+		content := append(bytes.Repeat([]byte("\n"), start), document.Bytes()...)
+		return sink(ctx, s.filename, io.NopCloser(bytes.NewReader(content)))
+	}
+	for {
+		line, err := reader.ReadBytes('\n')
+		if isDocumentSeparator(line) {
+			if sinkErr := flush(); sinkErr != nil {
+				return sinkErr
+			}
+			document.Reset()
+			start = lines
+		}
+		document.Write(line)
+		lines++
+		if err == io.EOF {
+			return flush()
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to read stdin")
+		}
+	}
+}
+
+// isDocumentSeparator checks if the line is the directives end marker ('---') starting a YAML document
+func isDocumentSeparator(line []byte) bool {
+	line = bytes.TrimRight(line, " \t\r\n")
+	return bytes.Equal(line, []byte("---")) || bytes.HasPrefix(line, []byte("--- "))
+}
@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStdinSourceProvider_GetSources tests the functions [GetSources()] of the stdin provider
+func TestStdinSourceProvider_GetSources(t *testing.T) {
+	content := "apiVersion: v1\nkind: Pod\n---\napiVersion: v1\nkind: Service\n"
+	tests := []struct {
+		name       string
+		filename   string
+		extensions model.Extensions
+		want       []string
+		contents   []string
+	}{
+		{
+			name:       "supported_extension",
+			filename:   "rendered.yaml",
+			extensions: model.Extensions{".yaml": struct{}{}, ".yml": struct{}{}},
+			want:       []string{"rendered.yaml", "rendered.yaml"},
+			contents:   []string{"apiVersion: v1\nkind: Pod\n", "\n\n---\napiVersion: v1\nkind: Service\n"},
+		},
+		{
+			name:       "supported_filename",
+			filename:   "build/Dockerfile",
+			extensions: model.Extensions{"Dockerfile": struct{}{}, ".dockerfile": struct{}{}},
+			want:       []string{"build/Dockerfile"},
+			contents:   []string{content},
+		},
+		{
+			name:       "unsupported_extension",
+			filename:   "main.tf",
+			extensions: model.Extensions{".yaml": struct{}{}},
+			want:       []string{},
+			contents:   []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewStdinSourceProvider([]byte(content), tt.filename)
+			require.Equal(t, []string{tt.filename}, s.GetBasePaths())
+
+			got, contents := []string{}, []string{}
+			err := s.GetSources(context.Background(), tt.extensions,
+				func(ctx context.Context, filename string, rc io.ReadCloser) error {
+					b, err := io.ReadAll(rc)
+					require.NoError(t, err)
+					got = append(got, filename)
+					contents = append(contents, string(b))
+					return nil
+				}, func(ctx context.Context, filename string) ([]string, error) {
+					return nil, nil
+				})
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+			require.Equal(t, tt.contents, contents)
+		})
+	}
+}
+
+// TestReadStdin tests the functions [ReadStdin()]
+func TestReadStdin(t *testing.T) {
+	content, err := ReadStdin(strings.NewReader("FROM alpine\n"), 12)
+	require.NoError(t, err)
+	require.Equal(t, "FROM alpine\n", string(content))
+
+	_, err = ReadStdin(strings.NewReader("FROM alpine\n"), 11)
+	require.EqualError(t, err, "stdin has more than 11 bytes")
+}
+
+// TestIsStdin tests the functions [IsStdin()]
+func TestIsStdin(t *testing.T) {
+	require.True(t, IsStdin([]string{StdinPath}))
+	require.False(t, IsStdin([]string{"assets/", "./-"}))
+}
@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// binaryPeekSize is the number of bytes read from the start of a file to detect a binary file
+const binaryPeekSize = 8000
+
+// TextFilter selects the text files provided by GetTextSources, with glob patterns matched against the name of the
+// files and the trailing parts of their path relative to the scanned path (e.g. 'config/*.env' matches 'app/config/.env')
+// Include selects the files matching any of its patterns, every file is included when empty
+// Exclude skips the files matching any of its patterns
+type TextFilter struct {
+	Include []string
+	Exclude []string
+}
+
+// NewTextFilter creates a TextFilter, checking the syntax of its patterns
+func NewTextFilter(include, exclude []string) (TextFilter, error) {
+	for _, pattern := range append(append([]string{}, include...), exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return TextFilter{}, errors.Wrapf(err, "invalid pattern %q", pattern)
+		}
+	}
+	return TextFilter{Include: include, Exclude: exclude}, nil
+}
+
+// Match checks if the file, given by its slash separated path relative to the scanned path, is selected by the filter
+func (f TextFilter) Match(rel string) bool {
+	if len(f.Include) > 0 && !MatchPatterns(f.Include, rel) {
+		return false
+	}
+	return !MatchPatterns(f.Exclude, rel)
+}
+
+// MatchPatterns checks if any of the glob patterns matches the name of the file or the trailing parts of its
+// slash separated path
+func MatchPatterns(patterns []string, rel string) bool {
+	parts := strings.Split(rel, "/")
+	for _, pattern := range patterns {
+		for i := range parts {
+			if matched, _ := path.Match(pattern, strings.Join(parts[i:], "/")); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetTextSources executes the sink function on the text files of the paths selected by the filter, regardless of
+// their extension, the binary files, the excluded paths and the git metadata are skipped
+func (s *FileSystemSourceProvider) GetTextSources(ctx context.Context, filter TextFilter, sink Sink) error {
+	for _, scanPath := range s.paths {
+		err := filepath.WalkDir(scanPath, func(filePath string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			if f, ok := s.excludes[info.Name()]; ok && containsFile(f, info) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() && d.Name() == gitDir {
+				return filepath.SkipDir
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			rel, err := filepath.Rel(scanPath, filePath)
+			if err != nil || rel == "." {
+				rel = d.Name()
+			}
+			if !filter.Match(filepath.ToSlash(rel)) {
+				return nil
+			}
+			return textSink(ctx, filePath, sink)
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to walk directory")
+		}
+	}
+	return nil
+}
+
+// textSink executes the sink function on the file, unless it is a binary file (its first bytes contain a NUL byte)
+func textSink(ctx context.Context, filePath string, sink Sink) error {
+	file, err := os.Open(filepath.Clean(filePath))
+	if err != nil {
+		return errors.Wrap(err, "failed to open file")
+	}
+	defer file.Close()
+
+	reader := bufio.NewReaderSize(file, binaryPeekSize)
+	start, err := reader.Peek(binaryPeekSize)
+	if err != nil && err != io.EOF {
+		return errors.Wrap(err, "failed to read file")
+	}
+	if bytes.IndexByte(start, 0) >= 0 {
+		log.Trace().Msgf("Binary file ignored: %s", filePath)
+		return nil
+	}
+
+	content := struct {
+		io.Reader
+		io.Closer
+	}{reader, file}
+	if err := sink(ctx, strings.ReplaceAll(filePath, "\\", "/"), content); err != nil {
+		log.Warn().Msgf("Text files provider couldn't read file %s: %s", filePath, err)
+	}
+	return nil
+}
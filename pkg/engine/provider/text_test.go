@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeTextFiles writes a directory with text files, a binary file and git metadata
+func writeTextFiles(t *testing.T) string {
+	dir := t.TempDir()
+	files := map[string]string{
+		".env":                          "DB_PASSWORD=secret\n",
+		"config/application.properties": "spring.datasource.password=secret\n",
+		"deploy.sh":                     "#!/bin/sh\n",
+		"vendor/lib/settings.ini":       "[db]\n",
+		"blob.bin":                      "ab\x00cd",
+		".git/config":                   "[core]\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		require.NoError(t, os.WriteFile(path, []byte(content), os.ModePerm))
+	}
+	return dir
+}
+
+// TestFileSystemSourceProvider_GetTextSources tests the functions [GetTextSources()] and all the methods called by them
+func TestFileSystemSourceProvider_GetTextSources(t *testing.T) {
+	tests := []struct {
+		name     string
+		include  []string
+		exclude  []string
+		excludes func(dir string) []string
+		want     []string
+	}{
+		{
+			name: "all_text_files",
+			want: []string{".env", "config/application.properties", "deploy.sh", "vendor/lib/settings.ini"},
+		},
+		{
+			name:    "include",
+			include: []string{"*.env", "config/*.properties"},
+			want:    []string{".env", "config/application.properties"},
+		},
+		{
+			name:    "exclude",
+			exclude: []string{"vendor/*/*", "*.sh"},
+			want:    []string{".env", "config/application.properties"},
+		},
+		{
+			name: "excluded_paths",
+			excludes: func(dir string) []string {
+				return []string{filepath.Join(dir, "vendor")}
+			},
+			want: []string{".env", "config/application.properties", "deploy.sh"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeTextFiles(t)
+			excludes := []string{}
+			if tt.excludes != nil {
+				excludes = tt.excludes(dir)
+			}
+			s, err := NewFileSystemSourceProvider([]string{dir}, excludes)
+			require.NoError(t, err)
+			filter, err := NewTextFilter(tt.include, tt.exclude)
+			require.NoError(t, err)
+
+			got := []string{}
+			err = s.GetTextSources(context.Background(), filter, func(ctx context.Context, filename string, rc io.ReadCloser) error {
+				content, err := io.ReadAll(rc)
+				require.NoError(t, err)
+				require.NotContains(t, string(content), "\x00")
+				got = append(got, strings.TrimPrefix(filename, filepath.ToSlash(dir)+"/"))
+				return nil
+			})
+			require.NoError(t, err)
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+// TestNewTextFilter tests the functions [NewTextFilter()] and [Match()]
+func TestNewTextFilter(t *testing.T) {
+	_, err := NewTextFilter([]string{"[a-"}, nil)
+	require.Error(t, err)
+
+	filter, err := NewTextFilter(nil, []string{"*_test.go", "testdata/*"})
+	require.NoError(t, err)
+	require.True(t, filter.Match("pkg/main.go"))
+	require.False(t, filter.Match("pkg/main_test.go"))
+	require.False(t, filter.Match("pkg/testdata/secrets.env"))
+}
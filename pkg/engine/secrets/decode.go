@@ -0,0 +1,204 @@
+package secrets
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Checkmarx/kics/pkg/detector"
+	"github.com/Checkmarx/kics/pkg/model"
+)
+
+const (
+	// defaultDecodingDepth is the number of nested encodings decoded by default
+	defaultDecodingDepth = 3
+	// maxDecodedValues is the maximum number of values decoded from a line
+	maxDecodedValues = 32
+	// maxDecodedSize is the maximum size of a value decompressed from gzip
+	maxDecodedSize = 1 << 20
+)
+
+var (
+	// the encoded values are at least 16 characters long, shorter values are too common to be decoded
+	base64Regex     = regexp.MustCompile(`[A-Za-z0-9+/_-]{16,}={0,2}`)
+	hexRegex        = regexp.MustCompile(`^(?:[0-9a-fA-F]{2}){8,}$`)
+	urlEncodedRegex = regexp.MustCompile(`[^\s'"]*%[0-9A-Fa-f]{2}[^\s'"]*`)
+	gzipMagic       = []byte{0x1f, 0x8b}
+	base64Encodings = []*base64.Encoding{
+		base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding,
+	}
+)
+
+// decodedValue is a value decoded from an encoded value of a line
+// encoded is the encoded value of the line
+// text is the text where the secrets are searched: the text with the encoded value replaced by its decoded value,
+// or the decoded value when it has several lines (e.g. a script or a private key)
+// chain is the list of the encodings decoded, the outermost first
+type decodedValue struct {
+	encoded string
+	text    string
+	chain   []string
+}
+
+// fileContent is the content of a file checked by the rules, its lines and the values decoded from them are
+// computed once, for all the rules
+type fileContent struct {
+	metadata *model.FileMetadata
+	lines    []string
+	decoded  [][]decodedValue
+}
+
+// newFileContent returns the content of the file, or the content decrypted from its vaulted source when vaulted is set
+func newFileContent(file *model.FileMetadata, vaulted *model.VaultedData) *fileContent {
+	if vaulted == nil {
+		return &fileContent{metadata: file}
+	}
+	decrypted := *file
+	decrypted.OriginalData = vaulted.Content
+	return &fileContent{metadata: &decrypted}
+}
+
+// splitLines returns the lines of the content, split the first time
+func (f *fileContent) splitLines(lineDetector *detector.DetectLine) []string {
+	if f.lines == nil {
+		f.lines = lineDetector.SplitLines(f.metadata)
+	}
+	return f.lines
+}
+
+// decodedLine returns the values decoded from the line, all the lines are decoded the first time
+func (f *fileContent) decodedLine(lineNumber, depth int) []decodedValue {
+	if f.decoded == nil {
+		f.decoded = make([][]decodedValue, len(f.lines))
+		for i, line := range f.lines {
+			if values := decodeLine(line, depth); len(values) > 0 {
+				f.decoded[i] = values
+			}
+		}
+	}
+	return f.decoded[lineNumber]
+}
+
+// decodeLine returns the values decoded from the base64, hex, URL-encoded and gzip+base64 values of the line,
+// the decoded values are decoded again up to the depth
+func decodeLine(line string, depth int) []decodedValue {
+	values := make([]decodedValue, 0)
+	var decode func(text, encoded string, chain []string)
+	decode = func(text, encoded string, chain []string) {
+		for _, token := range encodedTokens(text) {
+			decoded, encodings, ok := decodeToken(token)
+			if !ok || len(chain)+len(encodings) > depth || len(values) >= maxDecodedValues {
+				continue
+			}
+			value := decodedValue{
+				encoded: encoded,
+				text:    decoded,
+				chain:   append(append([]string{}, chain...), encodings...),
+			}
+			if value.encoded == "" {
+				value.encoded = token
+			}
+			if !strings.Contains(decoded, "\n") {
+				value.text = strings.Replace(text, token, decoded, 1)
+			}
+			values = append(values, value)
+			decode(value.text, value.encoded, value.chain)
+		}
+	}
+	decode(line, "", []string{})
+	return values
+}
+
+// encodedTokens returns the values of the text that may be encoded
+func encodedTokens(text string) []string {
+	tokens := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, token := range append(urlEncodedRegex.FindAllString(text, -1), base64Regex.FindAllString(text, -1)...) {
+		if !seen[token] {
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// decodeToken decodes the token to a text, returning the encodings decoded
+func decodeToken(token string) (decoded string, encodings []string, ok bool) {
+	if strings.Contains(token, "%") {
+		unescaped, err := url.QueryUnescape(token)
+		if err != nil || unescaped == token || !isText([]byte(unescaped)) {
+			return "", nil, false
+		}
+		return unescaped, []string{"url"}, true
+	}
+	if hexRegex.MatchString(token) {
+		if content, err := hex.DecodeString(token); err == nil {
+			if text, encodings, ok := decodeContent(content); ok {
+				return text, append([]string{"hex"}, encodings...), true
+			}
+		}
+	}
+	for _, encoding := range base64Encodings {
+		if content, err := encoding.DecodeString(token); err == nil {
+			if text, encodings, ok := decodeContent(content); ok {
+				return text, append([]string{"base64"}, encodings...), true
+			}
+			return "", nil, false
+		}
+	}
+	return "", nil, false
+}
+
+// decodeContent returns the text of the decoded content, decompressing it when it is compressed with gzip
+func decodeContent(content []byte) (text string, encodings []string, ok bool) {
+	encodings = []string{}
+	if bytes.HasPrefix(content, gzipMagic) {
+		reader, err := gzip.NewReader(bytes.NewReader(content))
+		if err != nil {
+			return "", nil, false
+		}
+		defer reader.Close()
+		content, err = io.ReadAll(io.LimitReader(reader, maxDecodedSize))
+		if err != nil {
+			return "", nil, false
+		}
+		encodings = append(encodings, "gzip")
+	}
+	if !isText(content) {
+		return "", nil, false
+	}
+	return string(content), encodings, true
+}
+
+// isText checks if the content is printable text
+func isText(content []byte) bool {
+	if len(content) == 0 || !utf8.Valid(content) {
+		return false
+	}
+	for _, r := range string(content) {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// lines returns the lines of the text checked by the line rules
+func (d *decodedValue) lines() []string {
+	return strings.Split(strings.ReplaceAll(d.text, "\r", ""), "\n")
+}
+
+// note returns the decoding chain reported with the secrets found in the decoded value
+func (d *decodedValue) note() string {
+	if d == nil {
+		return ""
+	}
+	return " (decoded from " + strings.Join(d.chain, " > ") + ")"
+}
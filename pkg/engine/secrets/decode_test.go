@@ -0,0 +1,159 @@
+package secrets
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/Checkmarx/kics/assets"
+	"github.com/Checkmarx/kics/internal/tracker"
+	"github.com/Checkmarx/kics/pkg/detector"
+	"github.com/Checkmarx/kics/pkg/engine/source"
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+func gzipBase64(t *testing.T, content string) string {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+// Test_decodeLine tests the function [decodeLine()]
+func Test_decodeLine(t *testing.T) {
+	password := base64.StdEncoding.EncodeToString([]byte("password=Mustbe8characters"))
+	nested := base64.StdEncoding.EncodeToString([]byte(hex.EncodeToString([]byte("password=Mustbe8characters"))))
+	script := gzipBase64(t, "#!/bin/sh\nexport PASSWORD=Mustbe8characters\n")
+	tests := []struct {
+		name  string
+		line  string
+		depth int
+		want  []decodedValue
+	}{
+		{
+			name:  "base64",
+			line:  "  db.conf: " + password,
+			depth: 3,
+			want: []decodedValue{
+				{encoded: password, text: "  db.conf: password=Mustbe8characters", chain: []string{"base64"}},
+			},
+		},
+		{
+			name:  "gzip_base64",
+			line:  "UserData: " + script,
+			depth: 3,
+			want: []decodedValue{
+				{encoded: script, text: "#!/bin/sh\nexport PASSWORD=Mustbe8characters\n", chain: []string{"base64", "gzip"}},
+			},
+		},
+		{
+			name:  "nested",
+			line:  "token = \"" + nested + "\"",
+			depth: 3,
+			want: []decodedValue{
+				{encoded: nested, text: "token = \"" + hex.EncodeToString([]byte("password=Mustbe8characters")) + "\"", chain: []string{"base64"}},
+				{encoded: nested, text: "token = \"password=Mustbe8characters\"", chain: []string{"base64", "hex"}},
+			},
+		},
+		{
+			name:  "depth_limit",
+			line:  "token = \"" + nested + "\"",
+			depth: 1,
+			want: []decodedValue{
+				{encoded: nested, text: "token = \"" + hex.EncodeToString([]byte("password=Mustbe8characters")) + "\"", chain: []string{"base64"}},
+			},
+		},
+		{
+			name:  "url",
+			line:  "url: postgres://admin:p%40ssword%21@db:5432",
+			depth: 3,
+			want: []decodedValue{
+				{encoded: "postgres://admin:p%40ssword%21@db:5432", text: "url: postgres://admin:p@ssword!@db:5432", chain: []string{"url"}},
+			},
+		},
+		{
+			name:  "not_encoded",
+			line:  "resource \"aws_s3_bucket_public_access_block\" \"b\" { key = \"" + base64.StdEncoding.EncodeToString([]byte{0, 1, 2, 250, 251, 252, 3, 4, 5, 6, 7, 8}) + "\" }",
+			depth: 3,
+			want:  []decodedValue{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, decodeLine(tt.line, tt.depth))
+		})
+	}
+}
+
+// Test_fileContent tests that the lines of a file are split and decoded once, for all the rules
+func Test_fileContent(t *testing.T) {
+	lineDetector := detector.NewDetectLine(3)
+	password := base64.StdEncoding.EncodeToString([]byte("password=Mustbe8characters"))
+	content := newFileContent(&model.FileMetadata{Kind: model.KindCOMMON, OriginalData: "plain\nkey: " + password}, nil)
+
+	lines := content.splitLines(lineDetector)
+	require.Equal(t, []string{"plain", "key: " + password}, lines)
+	require.Empty(t, content.decodedLine(0, defaultDecodingDepth))
+	decoded := content.decodedLine(1, defaultDecodingDepth)
+	require.Len(t, decoded, 1)
+
+	content.lines[1] = "changed"
+	require.Equal(t, decoded, content.decodedLine(1, defaultDecodingDepth), "the lines are decoded once")
+
+	vaulted := newFileContent(&model.FileMetadata{OriginalData: "vaulted"}, &model.VaultedData{Content: "decrypted"})
+	require.Equal(t, "decrypted", vaulted.metadata.OriginalData)
+}
+
+// TestInspect_Decoded tests that the secrets of encoded values are reported on the encoded line with the decoding chain
+func TestInspect_Decoded(t *testing.T) {
+	ctx := context.Background()
+	secretsInspector, err := NewInspector(
+		ctx,
+		map[string]bool{},
+		&tracker.CITracker{},
+		&source.QueryInspectorParameters{
+			IncludeQueries: source.IncludeQueries{ByIDs: []string{}},
+			ExcludeQueries: source.ExcludeQueries{ByIDs: []string{}},
+		},
+		false,
+		60,
+		assets.SecretsQueryRegexRulesJSON,
+		false,
+	)
+	require.NoError(t, err)
+
+	currentQuery := make(chan int64)
+	go func() {
+		for range currentQuery {
+		}
+	}()
+	defer close(currentQuery)
+
+	userData := gzipBase64(t, "#!/bin/bash\nexport AWS_SECRET_ACCESS_KEY=\"wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY\"\n")
+	files := model.FileMetadatas{
+		{
+			ID:           "cloudformation",
+			FilePath:     "template.yaml",
+			Kind:         model.KindYAML,
+			OriginalData: "Resources:\n  Instance:\n    Type: AWS::EC2::Instance\n    Properties:\n      UserData: " + userData + "\n",
+		},
+	}
+
+	vulns, err := secretsInspector.Inspect(ctx, []string{"."}, files, currentQuery)
+	require.NoError(t, err)
+	require.Len(t, vulns, 1)
+	require.Equal(t, 5, vulns[0].Line)
+	require.Equal(t, "'      UserData: "+userData+"' contains a secret (decoded from base64 > gzip)", vulns[0].KeyActualValue)
+	require.Contains(t, secretsInspector.GetSecrets(), userData, "the encoded value must be redacted")
+
+	secretsInspector.SetDecodingDepth(1)
+	vulns, err = secretsInspector.Inspect(ctx, []string{"."}, files, currentQuery)
+	require.NoError(t, err)
+	require.Len(t, vulns, 1, "gzip+base64 is not decoded with a depth of 1, no other result is added")
+}
@@ -0,0 +1,289 @@
+package secrets
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Checkmarx/kics/pkg/engine"
+	"github.com/Checkmarx/kics/pkg/engine/similarity"
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// historyCommitMarker starts the lines of the git log with the metadata of a commit, it can not start a diff line
+	historyCommitMarker = "\x00"
+	// historyCommitFormat is the format of the metadata of the commits: SHA, author date and author
+	historyCommitFormat = "%x00%H%x00%aI%x00%an <%ae>"
+	historyCommitFields = 3
+)
+
+var hunkHeaderRegex = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// HistoryOptions limits the commits checked by InspectHistory
+// Range is a git revision range (e.g. 'v1.0..HEAD'), all the commits of HEAD are checked when empty
+// Since is the date of the oldest commit checked (e.g. '2021-01-01' or '6 months ago')
+type HistoryOptions struct {
+	Range string
+	Since string
+}
+
+// historyFile is the content added to a file by a commit, with the line numbers of the added lines
+type historyFile struct {
+	commit      model.Commit
+	lines       []string
+	lineNumbers []int
+}
+
+// historyFinding is a secret found in the history, with the commits that added it
+type historyFinding struct {
+	query       *RegexQuery
+	fingerprint string
+	secrets     []string
+	line        string
+	note        string
+	commits     []model.Commit
+}
+
+// IsGitRepository checks if the path is a directory of the work tree of a git repository
+func IsGitRepository(path string) bool {
+	out, err := exec.Command("git", "-C", path, "rev-parse", "--is-inside-work-tree").Output() //nolint:gosec
+	return err == nil && strings.TrimSpace(string(out)) == "true"
+}
+
+// InspectHistory checks the secrets of the lines added by the commits of the git repository of the path, limited to
+// the files of the path. A secret is identified by its fingerprint (the hash of the rule and the secret) and reported
+// once, on the first commit that added it, with all the commits that added it
+// The secrets still found in the scanned files are not reported, the scan of the files already reports them, so
+// the history must be inspected after the files
+func (c *Inspector) InspectHistory(ctx context.Context, basePaths []string, repoPath string,
+	opts HistoryOptions) ([]model.Vulnerability, error) {
+	if len(c.regexQueries) == 0 {
+		return []model.Vulnerability{}, nil
+	}
+	args, err := historyArgs(repoPath, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := make(map[string]*historyFinding)
+	order := make([]string, 0)
+	var current *historyFile
+	matcher := &Inspector{
+		ctx:           ctx,
+		detector:      c.detector,
+		regexQueries:  c.regexQueries,
+		allowRules:    c.allowRules,
+		secrets:       make(map[string]struct{}),
+		decodingDepth: c.decodingDepth,
+		onSecret: func(query *RegexQuery, lineNumber int, issueLine, note string, secrets []string) {
+			if lineNumber < 0 || lineNumber >= len(current.lineNumbers) {
+				lineNumber = 0
+			}
+			commit := current.commit
+			commit.Line = current.lineNumbers[lineNumber]
+			fingerprint := secretFingerprint(query, secrets, issueLine)
+			finding, ok := findings[fingerprint]
+			if !ok {
+				finding = &historyFinding{query: query, fingerprint: fingerprint, secrets: secrets, line: issueLine, note: note}
+				findings[fingerprint] = finding
+				order = append(order, fingerprint)
+			}
+			if last := len(finding.commits) - 1; last < 0 || finding.commits[last].SHA != commit.SHA {
+				finding.commits = append(finding.commits, commit)
+			}
+		},
+	}
+
+	err = readHistory(ctx, args, func(file *historyFile) {
+		current = file
+		content := &model.FileMetadata{
+			FilePath:     file.commit.File,
+			OriginalData: strings.Join(file.lines, "\n"),
+			Kind:         model.KindCOMMON,
+		}
+		fileContent := newFileContent(content, nil)
+		for i := range matcher.regexQueries {
+			matcher.checkFile(&matcher.regexQueries[i], basePaths, content, nil, fileContent)
+		}
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the git history of %s", repoPath)
+	}
+
+	vulnerabilities := make([]model.Vulnerability, 0, len(order))
+	for _, fingerprint := range order {
+		if c.hasFingerprint(fingerprint) {
+			continue
+		}
+		if vuln, ok := c.historyVulnerability(basePaths, repoPath, findings[fingerprint]); ok {
+			vulnerabilities = append(vulnerabilities, vuln)
+		}
+	}
+	log.Info().Msgf("Found %d secrets in the git history of %s", len(vulnerabilities), repoPath)
+	return vulnerabilities, nil
+}
+
+// historyArgs returns the arguments of the git log of the lines added to the files of the path, oldest commit first
+func historyArgs(repoPath string, opts HistoryOptions) ([]string, error) {
+	for _, value := range []string{opts.Range, opts.Since} {
+		if strings.HasPrefix(value, "-") {
+			return nil, fmt.Errorf("invalid git history option '%s'", value)
+		}
+	}
+	args := []string{
+		"-C", repoPath, "-c", "core.quotePath=false", "log", "--reverse", "--patch", "--unified=0", "--relative",
+		"--no-color", "--no-ext-diff", "--format=" + historyCommitFormat,
+	}
+	if opts.Since != "" {
+		args = append(args, "--since="+opts.Since)
+	}
+	if opts.Range != "" {
+		args = append(args, opts.Range)
+	}
+	return append(args, "--", "."), nil
+}
+
+// readHistory runs the git log and calls fn with the lines added to each file by each commit
+func readHistory(ctx context.Context, args []string, fn func(file *historyFile)) error {
+	cmd := exec.CommandContext(ctx, "git", args...) //nolint:gosec
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	parseErr := parseHistory(stdout, fn)
+	if err := cmd.Wait(); err != nil {
+		return errors.Wrap(err, strings.TrimSpace(stderr.String()))
+	}
+	return parseErr
+}
+
+// parseHistory parses the patches of the git log, calling fn with the lines added to each file by each commit
+func parseHistory(r io.Reader, fn func(file *historyFile)) error {
+	reader := bufio.NewReader(r)
+	var commit model.Commit
+	var file *historyFile
+	lineNumber := 0
+	inHunk := false
+	flush := func() {
+		if file != nil && len(file.lines) > 0 {
+			fn(file)
+		}
+		file = nil
+	}
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+
+		switch {
+		case strings.HasPrefix(line, historyCommitMarker):
+			flush()
+			fields := strings.SplitN(strings.TrimPrefix(line, historyCommitMarker), historyCommitMarker, historyCommitFields)
+			if len(fields) == historyCommitFields {
+				commit = model.Commit{SHA: fields[0], Date: fields[1], Author: fields[2]}
+			}
+			inHunk = false
+		case strings.HasPrefix(line, "diff --git "):
+			flush()
+			inHunk = false
+		case !inHunk && strings.HasPrefix(line, "+++ "):
+			if path := diffPath(strings.TrimPrefix(line, "+++ ")); path != "" {
+				fileCommit := commit
+				fileCommit.File = path
+				file = &historyFile{commit: fileCommit}
+			}
+		case strings.HasPrefix(line, "@@ "):
+			if match := hunkHeaderRegex.FindStringSubmatch(line); match != nil {
+				lineNumber, _ = strconv.Atoi(match[1])
+				inHunk = true
+			}
+		case inHunk && strings.HasPrefix(line, "+") && file != nil:
+			file.lines = append(file.lines, strings.TrimPrefix(line, "+"))
+			file.lineNumbers = append(file.lineNumbers, lineNumber)
+			lineNumber++
+		}
+
+		if err == io.EOF {
+			flush()
+			return nil
+		}
+	}
+}
+
+// diffPath returns the path of the new file of the '+++' line of a diff, or an empty string for a deleted file
+func diffPath(path string) string {
+	path = strings.TrimSuffix(path, "\t")
+	if strings.HasPrefix(path, "\"") {
+		unquoted, err := strconv.Unquote(path)
+		if err != nil {
+			return ""
+		}
+		path = unquoted
+	}
+	if !strings.HasPrefix(path, "b/") {
+		return ""
+	}
+	return strings.TrimPrefix(path, "b/")
+}
+
+// secretFingerprint returns the fingerprint of the secrets found by the query
+func secretFingerprint(query *RegexQuery, secrets []string, issueLine string) string {
+	value := strings.Join(secrets, "\n")
+	if value == "" {
+		value = strings.TrimSpace(issueLine)
+	}
+	sum := sha256.Sum256([]byte(query.ID + "\x00" + value))
+	return hex.EncodeToString(sum[:])
+}
+
+// historyVulnerability creates the vulnerability of the finding, on the file and the line of the first commit
+// that added the secret, it is false when the result is excluded, then it is kept as suppressed
+func (c *Inspector) historyVulnerability(basePaths []string, repoPath string,
+	finding *historyFinding) (model.Vulnerability, bool) {
+	first := finding.commits[0]
+	filePath := filepath.Join(repoPath, filepath.FromSlash(first.File))
+	simID, err := similarity.ComputeSimilarityID(basePaths, filePath, finding.query.ID, finding.fingerprint, "")
+	if err != nil {
+		log.Error().Msg("unable to compute similarity ID")
+	}
+	vuln := newVulnerability(finding.query, engine.PtrStringToString(simID), model.VulnerabilityLines{
+		Line:      first.Line,
+		VulnLines: []model.CodeLine{{Position: first.Line, Line: finding.line}},
+	})
+	vuln.FileName = filePath
+	vuln.KeyActualValue = fmt.Sprintf("'%s' contains a secret%s added in commit %s", finding.line, finding.note, first.SHA)
+	switch others := len(finding.commits) - 1; {
+	case others == 1:
+		vuln.KeyActualValue += " and 1 other commit"
+	case others > 1:
+		vuln.KeyActualValue += fmt.Sprintf(" and %d other commits", others)
+	}
+	vuln.Commits = finding.commits
+	c.addSecrets(finding.secrets)
+	if _, ok := c.excludeResults[vuln.SimilarityID]; ok {
+		c.suppress(&vuln)
+		return model.Vulnerability{}, false
+	}
+	return vuln, true
+}
@@ -0,0 +1,195 @@
+package secrets
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Checkmarx/kics/assets"
+	"github.com/Checkmarx/kics/internal/tracker"
+	"github.com/Checkmarx/kics/pkg/engine/source"
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+const historyLog = "\x00aaa\x002021-06-01T10:00:00+00:00\x00Dev One <dev@example.com>\n" +
+	"\n" +
+	"diff --git a/main.tf b/main.tf\n" +
+	"new file mode 100644\n" +
+	"--- /dev/null\n" +
+	"+++ b/main.tf\n" +
+	"@@ -0,0 +1,3 @@\n" +
+	"+resource \"aws_db_instance\" \"default\" {\n" +
+	"+  password = \"Mustbe8characters\"\n" +
+	"+}\n" +
+	"\x00bbb\x002021-06-02T10:00:00+00:00\x00Dev Two <two@example.com>\n" +
+	"\n" +
+	"diff --git a/main.tf b/main.tf\n" +
+	"--- a/main.tf\n" +
+	"+++ b/main.tf\n" +
+	"@@ -2 +2 @@\n" +
+	"-  password = \"Mustbe8characters\"\n" +
+	"+  password = var.password\n" +
+	"diff --git a/old.sh b/old.sh\n" +
+	"deleted file mode 100644\n" +
+	"--- a/old.sh\n" +
+	"+++ /dev/null\n" +
+	"@@ -1 +0,0 @@\n" +
+	"-echo\n" +
+	"diff --git \"a/sp ace.sh\" \"b/sp ace.sh\"\n" +
+	"--- \"a/sp ace.sh\"\n" +
+	"+++ \"b/sp ace.sh\"\n" +
+	"@@ -4,0 +5,2 @@\n" +
+	"++++ not a header\n" +
+	"+echo"
+
+// Test_parseHistory tests the function [parseHistory()]
+func Test_parseHistory(t *testing.T) {
+	got := []historyFile{}
+	require.NoError(t, parseHistory(strings.NewReader(historyLog), func(file *historyFile) {
+		got = append(got, *file)
+	}))
+	require.Len(t, got, 3)
+
+	require.Equal(t, "aaa", got[0].commit.SHA)
+	require.Equal(t, "Dev One <dev@example.com>", got[0].commit.Author)
+	require.Equal(t, "2021-06-01T10:00:00+00:00", got[0].commit.Date)
+	require.Equal(t, "main.tf", got[0].commit.File)
+	require.Equal(t, []int{1, 2, 3}, got[0].lineNumbers)
+
+	require.Equal(t, "bbb", got[1].commit.SHA)
+	require.Equal(t, []string{"  password = var.password"}, got[1].lines)
+	require.Equal(t, []int{2}, got[1].lineNumbers)
+
+	require.Equal(t, "sp ace.sh", got[2].commit.File)
+	require.Equal(t, []string{"+++ not a header", "echo"}, got[2].lines)
+	require.Equal(t, []int{5, 6}, got[2].lineNumbers)
+}
+
+// Test_historyArgs tests the function [historyArgs()]
+func Test_historyArgs(t *testing.T) {
+	args, err := historyArgs("repo", HistoryOptions{Range: "v1.0..HEAD", Since: "2021-06-01"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"--since=2021-06-01", "v1.0..HEAD", "--", "."}, args[len(args)-4:])
+
+	_, err = historyArgs("repo", HistoryOptions{Range: "--output=/tmp/file"})
+	require.Error(t, err)
+}
+
+// TestInspector_InspectHistory tests the function [InspectHistory()] over the commits of a git repository
+func TestInspector_InspectHistory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=Dev", "-c", "user.email=dev@example.com"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	write := func(name, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(repo, name)), os.ModePerm))
+		require.NoError(t, os.WriteFile(filepath.Join(repo, name), []byte(content), os.ModePerm))
+	}
+
+	git("init", "-q")
+	write("main.tf", "resource \"aws_db_instance\" \"default\" {\n  password = \"Mustbe8characters\"\n}\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "add db")
+	write("main.tf", "resource \"aws_db_instance\" \"default\" {\n  password = var.password\n}\n")
+	write("scripts/deploy.sh", "#!/bin/sh\n\npassword=\"Mustbe8characters\"\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "move the password")
+
+	ctx := context.Background()
+	secretsInspector, err := NewInspector(
+		ctx,
+		map[string]bool{},
+		&tracker.CITracker{},
+		&source.QueryInspectorParameters{
+			IncludeQueries: source.IncludeQueries{ByIDs: []string{}},
+			ExcludeQueries: source.ExcludeQueries{ByIDs: []string{}},
+		},
+		false,
+		60,
+		assets.SecretsQueryRegexRulesJSON,
+		false,
+	)
+	require.NoError(t, err)
+	require.True(t, IsGitRepository(repo))
+	require.False(t, IsGitRepository(t.TempDir()))
+
+	vulns, err := secretsInspector.InspectHistory(ctx, []string{repo}, repo, HistoryOptions{})
+	require.NoError(t, err)
+	require.Len(t, vulns, 1, "the secret must be reported once")
+	require.Equal(t, filepath.Join(repo, "main.tf"), vulns[0].FileName)
+	require.Equal(t, 2, vulns[0].Line)
+	require.Len(t, vulns[0].Commits, 2)
+	require.Equal(t, "main.tf", vulns[0].Commits[0].File)
+	require.Equal(t, "scripts/deploy.sh", vulns[0].Commits[1].File)
+	require.Equal(t, 3, vulns[0].Commits[1].Line)
+	require.Equal(t, "Dev <dev@example.com>", vulns[0].Commits[1].Author)
+	require.Contains(t, secretsInspector.GetSecrets(), "Mustbe8characters")
+
+	// the results of the history are excluded by their similarity ID
+	excluded, err := NewInspector(
+		ctx,
+		map[string]bool{vulns[0].SimilarityID: true},
+		&tracker.CITracker{},
+		&source.QueryInspectorParameters{
+			IncludeQueries: source.IncludeQueries{ByIDs: []string{}},
+			ExcludeQueries: source.ExcludeQueries{ByIDs: []string{}},
+		},
+		false,
+		60,
+		assets.SecretsQueryRegexRulesJSON,
+		false,
+	)
+	require.NoError(t, err)
+	vulns, err = excluded.InspectHistory(ctx, []string{repo}, repo, HistoryOptions{Range: "HEAD~1..HEAD"})
+	require.NoError(t, err)
+	require.Len(t, vulns, 1, "the range only has the second commit, the result has another similarity ID")
+	require.Equal(t, filepath.Join(repo, "scripts", "deploy.sh"), vulns[0].FileName)
+
+	vulns, err = excluded.InspectHistory(ctx, []string{repo}, repo, HistoryOptions{})
+	require.NoError(t, err)
+	require.Empty(t, vulns)
+
+	// the secret is still in scripts/deploy.sh, the scan of the files reports it and the history does not
+	scanned, err := NewInspector(
+		ctx,
+		map[string]bool{},
+		&tracker.CITracker{},
+		&source.QueryInspectorParameters{
+			IncludeQueries: source.IncludeQueries{ByIDs: []string{}},
+			ExcludeQueries: source.ExcludeQueries{ByIDs: []string{}},
+		},
+		false,
+		60,
+		assets.SecretsQueryRegexRulesJSON,
+		false,
+	)
+	require.NoError(t, err)
+	currentQuery := make(chan int64)
+	go func() {
+		for range currentQuery {
+		}
+	}()
+	defer close(currentQuery)
+	deployPath := filepath.Join(repo, "scripts", "deploy.sh")
+	deploy, err := os.ReadFile(deployPath)
+	require.NoError(t, err)
+	vulns, err = scanned.Inspect(ctx, []string{repo}, model.FileMetadatas{
+		{ID: "deploy", FilePath: deployPath, Kind: model.KindCOMMON, OriginalData: string(deploy)},
+	}, currentQuery)
+	require.NoError(t, err)
+	require.Len(t, vulns, 1)
+	require.Equal(t, 3, vulns[0].Line)
+
+	vulns, err = scanned.InspectHistory(ctx, []string{repo}, repo, HistoryOptions{})
+	require.NoError(t, err)
+	require.Empty(t, vulns, "the secret of the working tree must not be reported again by the history")
+}
@@ -6,8 +6,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"path"
+	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Checkmarx/kics/assets"
@@ -15,6 +19,7 @@ import (
 	"github.com/Checkmarx/kics/pkg/detector/docker"
 	"github.com/Checkmarx/kics/pkg/detector/helm"
 	engine "github.com/Checkmarx/kics/pkg/engine"
+	"github.com/Checkmarx/kics/pkg/engine/provider"
 	"github.com/Checkmarx/kics/pkg/engine/similarity"
 	"github.com/Checkmarx/kics/pkg/engine/source"
 	"github.com/Checkmarx/kics/pkg/model"
@@ -40,6 +45,15 @@ type Inspector struct {
 	vulnerabilities       []model.Vulnerability
 	queryExecutionTimeout time.Duration
 	foundLines            []int
+	secrets               map[string]struct{}
+	secretsMutex          sync.Mutex
+	suppressed            []model.Vulnerability
+	suppressedMutex       sync.Mutex
+	decodingDepth         int
+	// fingerprints are the fingerprints of the secrets found in the scanned files, the history does not report them
+	fingerprints map[string]struct{}
+	// onSecret receives the secrets found instead of reporting them as vulnerabilities, when set
+	onSecret func(query *RegexQuery, lineNumber int, issueLine, note string, secrets []string)
 }
 
 type Entropy struct {
@@ -52,20 +66,31 @@ type MultilineResult struct {
 	DetectLineGroup int `json:"detectLineGroup"`
 }
 
+// AllowRule excludes the matches of its regex, only in the files matching its path globs when set
 type AllowRule struct {
-	Description string `json:"description"`
-	RegexStr    string `json:"regex"`
+	Description string   `json:"description"`
+	RegexStr    string   `json:"regex"`
+	Paths       []string `json:"paths"`
 	Regex       *regexp.Regexp
 }
 
+// RegexQuery is a secrets rule, its severity, description and CWE default to the ones of the Passwords And Secrets
+// query. The rule is only run over the files matching its include path globs, except the ones matching its exclude
+// path globs, and only over the texts containing one of its keywords (case insensitive) when set
 type RegexQuery struct {
-	ID         string          `json:"id"`
-	Name       string          `json:"name"`
-	Multiline  MultilineResult `json:"multiline"`
-	RegexStr   string          `json:"regex"`
-	Entropies  []Entropy       `json:"entropies"`
-	AllowRules []AllowRule     `json:"allowRules"`
-	Regex      *regexp.Regexp
+	ID           string          `json:"id"`
+	Name         string          `json:"name"`
+	Severity     string          `json:"severity"`
+	Description  string          `json:"description"`
+	CWE          string          `json:"cwe"`
+	Keywords     []string        `json:"keywords"`
+	IncludePaths []string        `json:"includePaths"`
+	ExcludePaths []string        `json:"excludePaths"`
+	Multiline    MultilineResult `json:"multiline"`
+	RegexStr     string          `json:"regex"`
+	Entropies    []Entropy       `json:"entropies"`
+	AllowRules   []AllowRule     `json:"allowRules"`
+	Regex        *regexp.Regexp
 }
 
 type RegexRuleStruct struct {
@@ -111,6 +136,9 @@ func NewInspector(
 			allowRules:            make([]AllowRule, 0),
 			vulnerabilities:       make([]model.Vulnerability, 0),
 			queryExecutionTimeout: time.Duration(executionTimeout) * time.Second,
+			secrets:               make(map[string]struct{}),
+			fingerprints:          make(map[string]struct{}),
+			decodingDepth:         defaultDecodingDepth,
 		}, nil
 	}
 
@@ -157,11 +185,23 @@ func NewInspector(
 		vulnerabilities:       make([]model.Vulnerability, 0),
 		queryExecutionTimeout: queryExecutionTimeout,
 		foundLines:            make([]int, 0),
+		secrets:               make(map[string]struct{}),
+		fingerprints:          make(map[string]struct{}),
+		decodingDepth:         defaultDecodingDepth,
 	}, nil
 }
 
 func (c *Inspector) Inspect(ctx context.Context, basePaths []string,
 	files model.FileMetadatas, currentQuery chan<- int64) ([]model.Vulnerability, error) {
+	// the lines of the files are split and decoded once, for all the rules
+	contents := make([]*fileContent, len(files))
+	vaultedContents := make([]*fileContent, len(files))
+	for idx := range files {
+		contents[idx] = newFileContent(&files[idx], nil)
+		if files[idx].Vaulted != nil {
+			vaultedContents[idx] = newFileContent(&files[idx], files[idx].Vaulted)
+		}
+	}
 	for i := range c.regexQueries {
 		currentQuery <- 1
 
@@ -172,24 +212,72 @@ func (c *Inspector) Inspect(ctx context.Context, basePaths []string,
 			case <-timeoutCtx.Done():
 				return c.vulnerabilities, timeoutCtx.Err()
 			default:
-				// check file content line by line
-				if c.regexQueries[i].Multiline == (MultilineResult{}) {
-					lines := c.detector.SplitLines(&files[idx])
-
-					for lineNumber, currentLine := range lines {
-						c.checkLineByLine(&c.regexQueries[i], basePaths, &files[idx], lineNumber, currentLine)
-					}
-					continue
+				c.checkFile(&c.regexQueries[i], basePaths, &files[idx], nil, contents[idx])
+				if files[idx].Vaulted != nil {
+					// check the content decrypted from Ansible Vault, the results point to the vaulted source
+					c.checkFile(&c.regexQueries[i], basePaths, &files[idx], files[idx].Vaulted, vaultedContents[idx])
 				}
-
-				// check file content as a whole
-				c.checkFileContent(&c.regexQueries[i], basePaths, &files[idx])
 			}
 		}
 	}
 	return c.vulnerabilities, nil
 }
 
+// checkFile checks the content of the file, which is the content decrypted from its vaulted source when vaulted is set
+func (c *Inspector) checkFile(query *RegexQuery, basePaths []string, file *model.FileMetadata, vaulted *model.VaultedData,
+	content *fileContent) {
+	if !query.matchFile(file.FilePath) {
+		return
+	}
+
+	lines := content.splitLines(c.detector)
+
+	// check file content line by line
+	if query.Multiline == (MultilineResult{}) {
+		for lineNumber, currentLine := range lines {
+			if !c.checkLineByLine(query, basePaths, file, vaulted, lineNumber, currentLine, currentLine, nil) {
+				c.checkDecodedLine(query, basePaths, file, vaulted, content, lineNumber)
+			}
+		}
+		return
+	}
+
+	// check file content as a whole
+	c.checkFileContent(query, basePaths, file, content.metadata, vaulted)
+	for lineNumber := range lines {
+		c.checkDecodedLine(query, basePaths, file, vaulted, content, lineNumber)
+	}
+}
+
+// checkDecodedLine checks the values decoded from the encoded values of the line, the results point to the line
+// and note the encodings decoded. The line rules check each line of the decoded values, the multiline rules check
+// the decoded values as a whole
+func (c *Inspector) checkDecodedLine(query *RegexQuery, basePaths []string, file *model.FileMetadata,
+	vaulted *model.VaultedData, content *fileContent, lineNumber int) {
+	if c.decodingDepth <= 0 {
+		return
+	}
+	currentLine := content.lines[lineNumber]
+	decodedValues := content.decodedLine(lineNumber, c.decodingDepth)
+	for i := range decodedValues {
+		texts := []string{decodedValues[i].text}
+		if query.Multiline == (MultilineResult{}) {
+			texts = decodedValues[i].lines()
+		}
+		for _, text := range texts {
+			if c.checkLineByLine(query, basePaths, file, vaulted, lineNumber, currentLine, text, &decodedValues[i]) {
+				return
+			}
+		}
+	}
+}
+
+// SetDecodingDepth sets the number of nested encodings decoded to find the secrets of the encoded values,
+// the encoded values are not decoded when it is zero
+func (c *Inspector) SetDecodingDepth(depth int) {
+	c.decodingDepth = depth
+}
+
 func compileRegexQueries(
 	queryFilter *source.QueryInspectorParameters,
 	allRegexQueries []RegexQuery,
@@ -198,6 +286,8 @@ func compileRegexQueries(
 ) ([]RegexQuery, error) {
 	var regexQueries []RegexQuery
 	var includeSpecificSecretQuery bool
+	// builtinRules are the positions of the built-in rules merged with the custom rules, by ID
+	builtinRules := make(map[string]int)
 
 	allSecretsQueryAndCustom := false
 
@@ -211,6 +301,9 @@ func compileRegexQueries(
 		}
 		allSecretsQueryAndCustom = true
 		regexQueries = kicsRegexQueries.Rules
+		for i := range regexQueries {
+			builtinRules[regexQueries[i].ID] = i
+		}
 	}
 
 	for i := range allRegexQueries {
@@ -224,7 +317,7 @@ func compileRegexQueries(
 				allRegexQueries[i].ID,
 				allRegexQueries[i].ID,
 				SecretsQueryMetadata["category"],
-				SecretsQueryMetadata["severity"],
+				string(allRegexQueries[i].severity()),
 				queryFilter.ExcludeQueries.ByIDs,
 			) {
 				continue
@@ -233,20 +326,27 @@ func compileRegexQueries(
 				SecretsQueryMetadata["category"],
 				allRegexQueries[i].ID,
 				SecretsQueryMetadata["category"],
-				SecretsQueryMetadata["severity"],
+				string(allRegexQueries[i].severity()),
 				queryFilter.ExcludeQueries.ByCategories,
 			) {
 				continue
 			}
 			if !shouldExecuteQuery(
-				SecretsQueryMetadata["severity"],
+				string(allRegexQueries[i].severity()),
 				allRegexQueries[i].ID,
 				SecretsQueryMetadata["category"],
-				SecretsQueryMetadata["severity"],
+				string(allRegexQueries[i].severity()),
 				queryFilter.ExcludeQueries.BySeverities,
 			) {
 				continue
 			}
+			// a custom rule with the ID of a built-in rule overrides it
+			if idx, ok := builtinRules[allRegexQueries[i].ID]; ok {
+				log.Warn().Msgf("The custom secrets rule %s replaces the built-in rule %s (%s)",
+					allRegexQueries[i].Name, regexQueries[idx].Name, allRegexQueries[i].ID)
+				regexQueries[idx] = allRegexQueries[i]
+				continue
+			}
 			regexQueries = append(regexQueries, allRegexQueries[i])
 		}
 	}
@@ -256,24 +356,98 @@ func compileRegexQueries(
 			return regexQueries, err
 		}
 		regexQueries[i].Regex = compiledRegexp
-		for j := range regexQueries[i].AllowRules {
-			regexQueries[i].AllowRules[j].Regex = regexp.MustCompile(regexQueries[i].AllowRules[j].RegexStr)
+		if err := validateRegexQuery(&regexQueries[i]); err != nil {
+			return regexQueries, err
+		}
+		if regexQueries[i].AllowRules, err = compileRegex(regexQueries[i].AllowRules); err != nil {
+			return regexQueries, err
 		}
 	}
 	return regexQueries, nil
 }
 
+// validateRegexQuery checks the severity and the path globs of the rule, normalizing its severity and its keywords
+func validateRegexQuery(query *RegexQuery) error {
+	if query.Severity != "" {
+		severity := model.Severity(strings.ToUpper(query.Severity))
+		if !isSeverity(severity) {
+			return fmt.Errorf("the query %s defines an invalid severity (%s)", query.Name, query.Severity)
+		}
+		query.Severity = string(severity)
+	}
+	if err := validatePaths(query.Name, append(append([]string{}, query.IncludePaths...), query.ExcludePaths...)); err != nil {
+		return err
+	}
+	for i := range query.Keywords {
+		query.Keywords[i] = strings.ToLower(query.Keywords[i])
+	}
+	return nil
+}
+
+func isSeverity(severity model.Severity) bool {
+	for _, s := range model.AllSeverities {
+		if s == severity {
+			return true
+		}
+	}
+	return false
+}
+
+func validatePaths(name string, patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("the rule %s defines an invalid path (%s)", name, pattern)
+		}
+	}
+	return nil
+}
+
 func compileRegex(allowRules []AllowRule) ([]AllowRule, error) {
 	for j := range allowRules {
 		compiledRegex, err := regexp.Compile(allowRules[j].RegexStr)
 		if err != nil {
 			return nil, err
 		}
+		if err := validatePaths(allowRules[j].Description, allowRules[j].Paths); err != nil {
+			return nil, err
+		}
 		allowRules[j].Regex = compiledRegex
 	}
 	return allowRules, nil
 }
 
+// matchFile checks if the rule runs over the file
+func (q *RegexQuery) matchFile(filePath string) bool {
+	filePath = filepath.ToSlash(filePath)
+	if len(q.IncludePaths) > 0 && !provider.MatchPatterns(q.IncludePaths, filePath) {
+		return false
+	}
+	return !provider.MatchPatterns(q.ExcludePaths, filePath)
+}
+
+// hasKeyword checks if the text contains one of the keywords of the rule, it is a cheap check of the texts that
+// can not be matched by the regex of the rule
+func (q *RegexQuery) hasKeyword(text string) bool {
+	if len(q.Keywords) == 0 {
+		return true
+	}
+	text = strings.ToLower(text)
+	for _, keyword := range q.Keywords {
+		if strings.Contains(text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// severity returns the severity of the rule
+func (q *RegexQuery) severity() model.Severity {
+	if q.Severity != "" {
+		return model.Severity(q.Severity)
+	}
+	return model.Severity(strings.ToUpper(SecretsQueryMetadata["severity"]))
+}
+
 func (c *Inspector) GetQueriesLength() int {
 	return len(c.regexQueries)
 }
@@ -287,8 +461,8 @@ func isValueInArray(value string, array []string) bool {
 	return false
 }
 
-func (c *Inspector) isSecret(s string, query *RegexQuery) (isSecretRet bool, groups [][]string) {
-	if isAllowRule(s, query.AllowRules) || isAllowRule(s, c.allowRules) {
+func (c *Inspector) isSecret(s, filePath string, query *RegexQuery) (isSecretRet bool, groups [][]string) {
+	if !query.hasKeyword(s) || isAllowRule(s, filePath, query.AllowRules) || isAllowRule(s, filePath, c.allowRules) {
 		return false, [][]string{}
 	}
 
@@ -307,7 +481,7 @@ func (c *Inspector) isSecret(s string, query *RegexQuery) (isSecretRet bool, gro
 		if max == -1 {
 			continue
 		}
-		secret, newGroups := c.isSecret(strings.Join(append(splitedText[:max], splitedText[max+1:]...), "\n"), query)
+		secret, newGroups := c.isSecret(strings.Join(append(splitedText[:max], splitedText[max+1:]...), "\n"), filePath, query)
 		if !secret {
 			continue
 		}
@@ -320,8 +494,12 @@ func (c *Inspector) isSecret(s string, query *RegexQuery) (isSecretRet bool, gro
 	return false, [][]string{}
 }
 
-func isAllowRule(s string, allowRules []AllowRule) bool {
+// isAllowRule checks if the text is excluded by an allow rule applying to the file
+func isAllowRule(s, filePath string, allowRules []AllowRule) bool {
 	for i := range allowRules {
+		if len(allowRules[i].Paths) > 0 && !provider.MatchPatterns(allowRules[i].Paths, filepath.ToSlash(filePath)) {
+			continue
+		}
 		if allowRules[i].Regex.MatchString(s) {
 			return true
 		}
@@ -329,22 +507,26 @@ func isAllowRule(s string, allowRules []AllowRule) bool {
 	return false
 }
 
-func (c *Inspector) checkFileContent(query *RegexQuery, basePaths []string, file *model.FileMetadata) {
-	isSecret, groups := c.isSecret(file.OriginalData, query)
+func (c *Inspector) checkFileContent(query *RegexQuery, basePaths []string, file, content *model.FileMetadata,
+	vaulted *model.VaultedData) {
+	isSecret, groups := c.isSecret(content.OriginalData, file.FilePath, query)
 	if !isSecret {
 		return
 	}
 
-	lineVulns := c.secretsDetectLine(query, file, groups)
+	lineVulns := c.secretsDetectLine(query, content, groups)
 
 	for _, lineVuln := range lineVulns {
 		if len(query.Entropies) == 0 {
 			c.addVulnerability(
 				basePaths,
 				file,
+				vaulted,
 				query,
 				lineVuln.lineNumber,
 				lineVuln.lineContent,
+				"",
+				secretValues(query, lineVuln.groups),
 			)
 		}
 
@@ -364,9 +546,12 @@ func (c *Inspector) checkFileContent(query *RegexQuery, basePaths []string, file
 					c.addVulnerability(
 						basePaths,
 						file,
+						vaulted,
 						query,
 						lineVuln.lineNumber,
 						lineVuln.lineContent,
+						"",
+						secretValues(query, lineVuln.groups),
 					)
 				}
 			}
@@ -414,19 +599,31 @@ func (c *Inspector) secretsDetectLine(query *RegexQuery, file *model.FileMetadat
 	return lineVulneInfoSlice
 }
 
-func (c *Inspector) checkLineByLine(query *RegexQuery, basePaths []string, file *model.FileMetadata, lineNumber int, currentLine string) {
-	isSecret, groups := c.isSecret(currentLine, query)
+// checkLineByLine checks the text of the line, which is the line itself or a text decoded from the line, it returns
+// true when a secret of the text is reported
+func (c *Inspector) checkLineByLine(query *RegexQuery, basePaths []string, file *model.FileMetadata, vaulted *model.VaultedData,
+	lineNumber int, currentLine, text string, decoded *decodedValue) bool {
+	isSecret, groups := c.isSecret(text, file.FilePath, query)
 	if !isSecret {
-		return
+		return false
+	}
+	secrets := secretValues(query, groups[0])
+	if decoded != nil {
+		secrets = append(secrets, decoded.encoded)
 	}
 
+	reported := false
 	if len(query.Entropies) == 0 {
+		reported = true
 		c.addVulnerability(
 			basePaths,
 			file,
+			vaulted,
 			query,
 			lineNumber,
 			currentLine,
+			decoded.note(),
+			secrets,
 		)
 	}
 
@@ -435,7 +632,7 @@ func (c *Inspector) checkLineByLine(query *RegexQuery, basePaths []string, file
 
 		// if matched group does not exist continue
 		if len(groups[0]) <= entropy.Group {
-			return
+			return reported
 		}
 
 		isMatch, entropyFloat := CheckEntropyInterval(
@@ -445,18 +642,40 @@ func (c *Inspector) checkLineByLine(query *RegexQuery, basePaths []string, file
 		log.Debug().Msgf("match: %v :: %v", isMatch, fmt.Sprint(entropyFloat))
 
 		if isMatch {
+			reported = true
 			c.addVulnerability(
 				basePaths,
 				file,
+				vaulted,
 				query,
 				lineNumber,
 				currentLine,
+				decoded.note(),
+				secrets,
 			)
 		}
 	}
+	return reported
 }
 
-func (c *Inspector) addVulnerability(basePaths []string, file *model.FileMetadata, query *RegexQuery, lineNumber int, issueLine string) {
+func (c *Inspector) addVulnerability(basePaths []string, file *model.FileMetadata, vaulted *model.VaultedData,
+	query *RegexQuery, lineNumber int, issueLine, note string, secrets []string) {
+	if vaulted != nil {
+		// the decrypted line must not be reported, it is replaced by the line of its vaulted source
+		issueLine = "-"
+		if lineNumber >= 0 && lineNumber < len(vaulted.Lines) {
+			lineNumber = vaulted.Lines[lineNumber] - 1
+			if lines := c.detector.SplitLines(file); lineNumber < len(lines) {
+				issueLine = lines[lineNumber]
+			}
+		}
+	}
+
+	if c.onSecret != nil {
+		c.onSecret(query, lineNumber, issueLine, note, secrets)
+		return
+	}
+
 	simID, err := similarity.ComputeSimilarityID(
 		basePaths,
 		file.FilePath,
@@ -468,28 +687,127 @@ func (c *Inspector) addVulnerability(basePaths []string, file *model.FileMetadat
 		log.Error().Msg("unable to compute similarity ID")
 	}
 
-	if _, ok := c.excludeResults[engine.PtrStringToString(simID)]; !ok {
-		linesVuln := c.detector.GetAdjecent(file, lineNumber+1)
-		vuln := model.Vulnerability{
-			QueryID:          query.ID,
-			QueryName:        SecretsQueryMetadata["queryName"] + " - " + query.Name,
-			SimilarityID:     engine.PtrStringToString(simID),
-			FileID:           file.ID,
-			FileName:         file.FilePath,
-			Line:             linesVuln.Line,
-			VulnLines:        linesVuln.VulnLines,
-			IssueType:        "RedundantAttribute",
-			Platform:         SecretsQueryMetadata["platform"],
-			Severity:         model.SeverityHigh,
-			QueryURI:         SecretsQueryMetadata["descriptionUrl"],
-			Category:         SecretsQueryMetadata["category"],
-			Description:      SecretsQueryMetadata["descriptionText"],
-			DescriptionID:    SecretsQueryMetadata["descriptionID"],
-			KeyExpectedValue: "Hardcoded secret key should not appear in source",
-			KeyActualValue:   fmt.Sprintf("'%s' contains a secret", issueLine),
+	vuln := newVulnerability(query, engine.PtrStringToString(simID), c.detector.GetAdjecent(file, lineNumber+1))
+	vuln.FileID = file.ID
+	vuln.FileName = file.FilePath
+	vuln.KeyActualValue = fmt.Sprintf("'%s' contains a secret%s", issueLine, note)
+	// the secrets of the excluded results are also redacted, since they are reported as suppressed
+	c.addSecrets(secrets)
+	c.addFingerprint(secretFingerprint(query, secrets, issueLine))
+	if _, ok := c.excludeResults[vuln.SimilarityID]; ok {
+		c.suppress(&vuln)
+		return
+	}
+	c.vulnerabilities = append(c.vulnerabilities, vuln)
+}
+
+// newVulnerability creates the vulnerability of a secret found by the query
+func newVulnerability(query *RegexQuery, simID string, linesVuln model.VulnerabilityLines) model.Vulnerability {
+	description, cwe := query.Description, query.CWE
+	if description == "" {
+		description = SecretsQueryMetadata["descriptionText"]
+	}
+	if cwe == "" {
+		cwe = SecretsQueryMetadata["cwe"]
+	}
+	return model.Vulnerability{
+		QueryID:          query.ID,
+		QueryName:        SecretsQueryMetadata["queryName"] + " - " + query.Name,
+		SimilarityID:     simID,
+		Line:             linesVuln.Line,
+		VulnLines:        linesVuln.VulnLines,
+		IssueType:        "RedundantAttribute",
+		Platform:         SecretsQueryMetadata["platform"],
+		Severity:         query.severity(),
+		QueryURI:         SecretsQueryMetadata["descriptionUrl"],
+		Category:         SecretsQueryMetadata["category"],
+		Description:      description,
+		DescriptionID:    SecretsQueryMetadata["descriptionID"],
+		CWE:              cwe,
+		KeyExpectedValue: "Hardcoded secret key should not appear in source",
+	}
+}
+
+func (c *Inspector) addSecrets(secrets []string) {
+	c.secretsMutex.Lock()
+	defer c.secretsMutex.Unlock()
+	for _, secret := range secrets {
+		c.secrets[secret] = struct{}{}
+	}
+}
+
+// addFingerprint keeps the fingerprint of a secret found in the scanned files
+func (c *Inspector) addFingerprint(fingerprint string) {
+	c.secretsMutex.Lock()
+	defer c.secretsMutex.Unlock()
+	if c.fingerprints == nil {
+		c.fingerprints = make(map[string]struct{})
+	}
+	c.fingerprints[fingerprint] = struct{}{}
+}
+
+// hasFingerprint checks if the secret of the fingerprint was found in the scanned files
+func (c *Inspector) hasFingerprint(fingerprint string) bool {
+	c.secretsMutex.Lock()
+	defer c.secretsMutex.Unlock()
+	_, ok := c.fingerprints[fingerprint]
+	return ok
+}
+
+// suppress keeps the result excluded by its similarity ID
+func (c *Inspector) suppress(vuln *model.Vulnerability) {
+	vuln.Suppression = &model.Suppression{Kind: model.SuppressionExternal, Justification: "excluded by its similarity ID"}
+	c.suppressedMutex.Lock()
+	defer c.suppressedMutex.Unlock()
+	c.suppressed = append(c.suppressed, *vuln)
+}
+
+// GetSuppressed returns the results excluded by their similarity ID
+func (c *Inspector) GetSuppressed() []model.Vulnerability {
+	c.suppressedMutex.Lock()
+	defer c.suppressedMutex.Unlock()
+	return append([]model.Vulnerability{}, c.suppressed...)
+}
+
+// GetSecrets returns the secrets found by the inspector, used to redact them from the reports
+func (c *Inspector) GetSecrets() []string {
+	c.secretsMutex.Lock()
+	defer c.secretsMutex.Unlock()
+	secrets := make([]string, 0, len(c.secrets))
+	for secret := range c.secrets {
+		secrets = append(secrets, secret)
+	}
+	sort.Strings(secrets)
+	return secrets
+}
+
+// secretValues returns the secret of the match of the query: the group checked by the entropies or the group
+// detecting the line of multiline queries, otherwise the value of 'key = value' matches or the whole match,
+// secrets spanning several lines are split by line
+func secretValues(query *RegexQuery, groups []string) []string {
+	if len(groups) == 0 {
+		return []string{}
+	}
+	secret := groups[0]
+	switch {
+	case len(query.Entropies) > 0 && query.Entropies[0].Group < len(groups):
+		secret = groups[query.Entropies[0].Group]
+	case query.Multiline.DetectLineGroup > 0 && query.Multiline.DetectLineGroup < len(groups):
+		secret = groups[query.Multiline.DetectLineGroup]
+	case len(groups) > 1 && groups[len(groups)-1] != "":
+		value := groups[len(groups)-1]
+		if idx := strings.LastIndex(groups[0], value); idx > 0 && strings.ContainsAny(groups[0][:idx], ":=") {
+			secret = value
+		}
+	}
+
+	values := make([]string, 0)
+	for _, line := range strings.Split(secret, "\n") {
+		if value := strings.Trim(line, " \t\r\"'"); value != "" {
+			values = append(values, value)
 		}
-		c.vulnerabilities = append(c.vulnerabilities, vuln)
 	}
+	return values
 }
 
 // CheckEntropyInterval - verifies if a given token's entropy is within expected bounds
@@ -550,11 +868,17 @@ func getPasswordsAndSecretsQueryID() (string, error) {
 }
 
 func validateCustomSecretsQueriesID(allRegexQueries []RegexQuery) error {
+	ids := make(map[string]bool, len(allRegexQueries))
 	for i := range allRegexQueries {
 		re := regexp.MustCompile(`^[0-9a-fA-F]{8}\b-[0-9a-fA-F]{4}\b-[0-9a-fA-F]{4}\b-[0-9a-fA-F]{4}\b-[0-9a-fA-F]{12}$`)
 		if !(re.MatchString(allRegexQueries[i].ID)) {
 			return fmt.Errorf("the query %s defines an invalid query ID (%s)", allRegexQueries[i].Name, allRegexQueries[i].ID)
 		}
+		// the results are grouped by query ID, each rule must have its own ID to be reported as its own rule
+		if ids[allRegexQueries[i].ID] {
+			return fmt.Errorf("the query %s defines a duplicate query ID (%s)", allRegexQueries[i].Name, allRegexQueries[i].ID)
+		}
+		ids[allRegexQueries[i].ID] = true
 	}
 	return nil
 }
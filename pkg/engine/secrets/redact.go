@@ -0,0 +1,170 @@
+package secrets
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/Checkmarx/kics/pkg/model"
+)
+
+const (
+	// minSecretLength is the length of the shortest secret redacted, shorter values are too common to be replaced
+	minSecretLength = 4
+	// visibleSecretRatio is the ratio of the length of the secret shown at its start and its end
+	visibleSecretRatio = 8
+	maxVisibleSecret   = 4
+	secretHashLength   = 12
+)
+
+// RedactSecret returns the redacted value of the secret: a few characters of its start and its end (depending on its
+// length) and a hash of the secret, so the same secret can be recognized in the reports without being disclosed
+func RedactSecret(secret string) string {
+	runes := []rune(secret)
+	visible := len(runes) / visibleSecretRatio
+	if visible > maxVisibleSecret {
+		visible = maxVisibleSecret
+	}
+	sum := sha256.Sum256([]byte(secret))
+	return fmt.Sprintf("%s***%s (sha256:%s)",
+		string(runes[:visible]),
+		string(runes[len(runes)-visible:]),
+		hex.EncodeToString(sum[:])[:secretHashLength])
+}
+
+// Redactor replaces the secrets found in a scan by their redacted value
+type Redactor struct {
+	replacer *strings.Replacer
+}
+
+// NewRedactor creates a Redactor of the secrets
+func NewRedactor(secrets []string) *Redactor {
+	sorted := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		if len(secret) >= minSecretLength {
+			sorted = append(sorted, secret)
+		}
+	}
+	// the longest secrets are replaced first, so a secret containing another one is fully redacted
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return len(sorted[i]) > len(sorted[j])
+	})
+	oldNew := make([]string, 0, 2*len(sorted)) //nolint:gomnd
+	for _, secret := range sorted {
+		oldNew = append(oldNew, secret, RedactSecret(secret))
+	}
+	return &Redactor{replacer: strings.NewReplacer(oldNew...)}
+}
+
+// Redact returns the text with its secrets redacted
+func (r *Redactor) Redact(text string) string {
+	return r.replacer.Replace(text)
+}
+
+// RedactSummary redacts the secrets of the content shown by the results of the summary (code lines and values),
+// the paths and the metadata of the queries are kept
+func (r *Redactor) RedactSummary(summary *model.Summary) {
+	for _, queries := range []model.QueryResultSlice{summary.Queries, summary.Bom, summary.Suppressed} {
+		for i := range queries {
+			for j := range queries[i].Files {
+				r.redactFile(&queries[i].Files[j])
+			}
+		}
+	}
+}
+
+func (r *Redactor) redactFile(file *model.VulnerableFile) {
+	redactedLines := make([]model.CodeLine, len(file.VulnLines))
+	for i, line := range file.VulnLines {
+		redactedLines[i] = model.CodeLine{Position: line.Position, Line: r.Redact(line.Line)}
+	}
+	file.VulnLines = redactedLines
+	file.SearchValue = r.Redact(file.SearchValue)
+	file.KeyExpectedValue = r.Redact(file.KeyExpectedValue)
+	file.KeyActualValue = r.Redact(file.KeyActualValue)
+	if file.Value != nil {
+		value := r.Redact(*file.Value)
+		file.Value = &value
+	}
+}
+
+// RedactDocuments returns a copy of the documents with the secrets of their values redacted,
+// the id and the path of the file of the documents are kept
+func (r *Redactor) RedactDocuments(documents model.Documents) model.Documents {
+	redacted := model.Documents{Documents: make([]model.Document, len(documents.Documents))}
+	for i, document := range documents.Documents {
+		redacted.Documents[i] = r.redactValue(document).(model.Document)
+		for _, key := range []string{"id", "file"} {
+			if value, ok := document[key]; ok {
+				redacted.Documents[i][key] = value
+			}
+		}
+	}
+	return redacted
+}
+
+func (r *Redactor) redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return r.Redact(v)
+	case model.Document:
+		return model.Document(r.redactMap(v))
+	case map[string]interface{}:
+		return r.redactMap(v)
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i := range v {
+			redacted[i] = r.redactValue(v[i])
+		}
+		return redacted
+	default:
+		return r.redactReflect(value)
+	}
+}
+
+func (r *Redactor) redactMap(m map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		redacted[key] = r.redactValue(value)
+	}
+	return redacted
+}
+
+// redactReflect redacts the strings of the values of other types, such as the typed slices of the parsers
+func (r *Redactor) redactReflect(value interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.String:
+		return reflect.ValueOf(r.Redact(v.String())).Convert(v.Type()).Interface()
+	case reflect.Slice:
+		if v.IsNil() {
+			return value
+		}
+		redacted := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			if item := r.redactValue(v.Index(i).Interface()); item != nil {
+				redacted.Index(i).Set(reflect.ValueOf(item))
+			}
+		}
+		return redacted.Interface()
+	case reflect.Map:
+		if v.IsNil() || v.Type().Key().Kind() != reflect.String {
+			return value
+		}
+		redacted := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			item := iter.Value()
+			if redactedItem := r.redactValue(item.Interface()); redactedItem != nil {
+				item = reflect.ValueOf(redactedItem)
+			}
+			redacted.SetMapIndex(iter.Key(), item)
+		}
+		return redacted.Interface()
+	default:
+		return value
+	}
+}
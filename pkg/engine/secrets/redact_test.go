@@ -0,0 +1,115 @@
+package secrets
+
+import (
+	"testing"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRedactSecret tests the function [RedactSecret()]
+func TestRedactSecret(t *testing.T) {
+	tests := []struct {
+		secret string
+		want   string
+	}{
+		{secret: "hunter2", want: "*** (sha256:f52fbd32b2b3)"},
+		{secret: "Mustbe8characters", want: "Mu***rs (sha256:ddac187f92b2)"},
+		{secret: "AKIAIOSFODNN7EXAMPLEwJalrXUtnFEMIK7MDENG", want: "AKIA***DENG (sha256:362cca88359e)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.secret, func(t *testing.T) {
+			require.Equal(t, tt.want, RedactSecret(tt.secret))
+		})
+	}
+}
+
+// TestRedactor_Redact tests the functions [NewRedactor()] and [Redact()]
+func TestRedactor_Redact(t *testing.T) {
+	redactor := NewRedactor([]string{"abc", "Mustbe8", "Mustbe8characters"})
+	require.Equal(t,
+		"password = \""+RedactSecret("Mustbe8characters")+"\" # abc "+RedactSecret("Mustbe8"),
+		redactor.Redact("password = \"Mustbe8characters\" # abc Mustbe8"),
+		"the longest secrets must be redacted first and the short values kept")
+	require.Equal(t, "no secret", NewRedactor(nil).Redact("no secret"))
+}
+
+// TestRedactor_RedactSummary tests the function [RedactSummary()]
+func TestRedactor_RedactSummary(t *testing.T) {
+	value := "Mustbe8characters"
+	lines := []model.CodeLine{{Position: 3, Line: "  password = \"Mustbe8characters\""}}
+	summary := model.Summary{
+		Queries: model.QueryResultSlice{
+			{
+				QueryName: "Passwords And Secrets - Generic Password",
+				Files: []model.VulnerableFile{
+					{
+						FileName:       "main.tf",
+						Line:           3,
+						VulnLines:      lines,
+						KeyActualValue: "'password = \"Mustbe8characters\"' contains a secret",
+						Value:          &value,
+					},
+				},
+			},
+		},
+	}
+
+	NewRedactor([]string{"Mustbe8characters"}).RedactSummary(&summary)
+
+	redacted := RedactSecret("Mustbe8characters")
+	file := summary.Queries[0].Files[0]
+	require.Equal(t, "main.tf", file.FileName)
+	require.Equal(t, []model.CodeLine{{Position: 3, Line: "  password = \"" + redacted + "\""}}, file.VulnLines)
+	require.Equal(t, "'password = \""+redacted+"\"' contains a secret", file.KeyActualValue)
+	require.Equal(t, redacted, *file.Value)
+	require.Equal(t, "  password = \"Mustbe8characters\"", lines[0].Line, "the lines shared with the files must not change")
+	require.Equal(t, "Mustbe8characters", value)
+}
+
+// TestRedactor_RedactDocuments tests the function [RedactDocuments()]
+func TestRedactor_RedactDocuments(t *testing.T) {
+	documents := model.Documents{
+		Documents: []model.Document{
+			{
+				"id":   "Mustbe8characters",
+				"file": "Mustbe8characters/main.tf",
+				"resource": map[string]interface{}{
+					"aws_db_instance": map[string]interface{}{
+						"default": map[string]interface{}{
+							"password": "Mustbe8characters",
+							"port":     5432,
+							"tags":     []interface{}{"db", "Mustbe8characters"},
+						},
+					},
+				},
+				"lines": []string{"password = Mustbe8characters"},
+			},
+		},
+	}
+
+	got := NewRedactor([]string{"Mustbe8characters"}).RedactDocuments(documents)
+
+	redacted := RedactSecret("Mustbe8characters")
+	require.Equal(t, model.Documents{
+		Documents: []model.Document{
+			{
+				"id":   "Mustbe8characters",
+				"file": "Mustbe8characters/main.tf",
+				"resource": map[string]interface{}{
+					"aws_db_instance": map[string]interface{}{
+						"default": map[string]interface{}{
+							"password": redacted,
+							"port":     5432,
+							"tags":     []interface{}{"db", redacted},
+						},
+					},
+				},
+				"lines": []string{"password = " + redacted},
+			},
+		},
+	}, got)
+	require.Equal(t, "Mustbe8characters",
+		documents.Documents[0]["resource"].(map[string]interface{})["aws_db_instance"].(map[string]interface{})["default"].(map[string]interface{})["password"],
+		"the documents must not change")
+}
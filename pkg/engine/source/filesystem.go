@@ -42,6 +42,11 @@ const (
 	kicsDefault = "default"
 )
 
+// platformAliases maps platforms without queries of their own to the platform whose queries they reuse
+var platformAliases = map[string]string{
+	"bicep": "azureresourcemanager",
+}
+
 // NewFilesystemSource initializes a NewFilesystemSource with source to queries and types of queries to load
 func NewFilesystemSource(source string, types, cloudProviders []string, libraryPath string) *FilesystemSource {
 	log.Debug().Msg("source.NewFilesystemSource()")
@@ -174,7 +179,14 @@ func (s *FilesystemSource) CheckType(queryPlatform interface{}) bool {
 		return true
 	}
 	if s.Types[0] != "" {
-		return strings.Contains(strings.ToUpper(strings.Join(s.Types, ",")), strings.ToUpper(queryPlatform.(string)))
+		types := make([]string, 0, len(s.Types))
+		for _, t := range s.Types {
+			if alias, ok := platformAliases[strings.ToLower(t)]; ok {
+				t = alias
+			}
+			types = append(types, t)
+		}
+		return strings.Contains(strings.ToUpper(strings.Join(types, ",")), strings.ToUpper(queryPlatform.(string)))
 	}
 	return true
 }
@@ -416,6 +428,16 @@ func getPlatform(metadataPlatform string) string {
 		return "terraform"
 	case "AzureResourceManager":
 		return "azureResourceManager"
+	case "AsyncAPI":
+		return "asyncAPI"
+	case "GraphQL":
+		return "graphql"
+	case "DockerCompose":
+		return "dockerCompose"
+	case "CICD":
+		return "cicd"
+	case "Pulumi":
+		return "pulumi"
 	default:
 		return "unknown"
 	}
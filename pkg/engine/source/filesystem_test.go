@@ -618,17 +618,65 @@ func Test_getPlatform(t *testing.T) {
 func TestListSupportedPlatforms(t *testing.T) {
 	expected := []string{
 		"Ansible",
+		"AsyncAPI",
 		"AzureResourceManager",
+		"Bicep",
+		"CICD",
 		"CloudFormation",
+		"DockerCompose",
 		"Dockerfile",
+		"GraphQL",
 		"Kubernetes",
 		"OpenAPI",
+		"Pulumi",
 		"Terraform",
 	}
 	actual := ListSupportedPlatforms()
 	require.Equal(t, expected, actual, "expected=%s\ngot=%s", expected, actual)
 }
 
+// TestFilesystemSource_CheckType tests the function CheckType
+func TestFilesystemSource_CheckType(t *testing.T) {
+	tests := []struct {
+		name          string
+		types         []string
+		queryPlatform string
+		want          bool
+	}{
+		{
+			name:          "should load all queries when no type is given",
+			types:         []string{""},
+			queryPlatform: "Terraform",
+			want:          true,
+		},
+		{
+			name:          "should load common queries",
+			types:         []string{"Dockerfile"},
+			queryPlatform: "Common",
+			want:          true,
+		},
+		{
+			name:          "should not load queries of other platforms",
+			types:         []string{"Dockerfile"},
+			queryPlatform: "Terraform",
+			want:          false,
+		},
+		{
+			name:          "should load azure resource manager queries for bicep",
+			types:         []string{"Bicep"},
+			queryPlatform: "AzureResourceManager",
+			want:          true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewFilesystemSource("", tt.types, []string{""}, "")
+			require.Equal(t, tt.want, s.CheckType(tt.queryPlatform))
+		})
+	}
+}
+
 // TestReadInputData tests readInputData function
 func TestReadInputData(t *testing.T) {
 	tests := []struct {
@@ -2,6 +2,7 @@ package engine
 
 import (
 	"encoding/json"
+	"strconv"
 	"strings"
 
 	dec "github.com/Checkmarx/kics/pkg/detector"
@@ -82,8 +83,19 @@ var DefaultVulnerabilityBuilder = func(ctx *QueryContext, tracker Tracker,
 	similarityIDLineInfo = searchLineCalc.similarityIDLineInfo
 	linesVulne = searchLineCalc.linesVulne
 
+	if linesVulne.Line == -1 && file.Vaulted != nil {
+		linesVulne = vaultedLine(&file, searchKey, detector)
+	}
+
+	fileName := file.FilePath
+	if originFileName, originLines, ok := refOrigin(vObj, searchKey, lineNumber, &file, detector, ctx.refFiles); ok {
+		fileName = originFileName
+		linesVulne = originLines
+		similarityIDLineInfo = strconv.Itoa(originLines.Line)
+	}
+
 	if linesVulne.Line == -1 {
-		logWithFields.Warn().Msgf("Failed to detect line, query response %s", searchKey)
+		logWithFields.Warn().Msgf("Failed to detect line, query response %s", file.Vaulted.Redact(searchKey))
 	}
 
 	searchValue := ""
@@ -126,18 +138,18 @@ var DefaultVulnerabilityBuilder = func(ctx *QueryContext, tracker Tracker,
 
 	var similarityID *string
 
-	similarityID, err = similarity.ComputeSimilarityID(ctx.baseScanPaths, file.FilePath, queryID, similarityIDLineInfo, searchValue)
+	similarityID, err = similarity.ComputeSimilarityID(ctx.baseScanPaths, fileName, queryID, similarityIDLineInfo, searchValue)
 	if err != nil {
 		logWithFields.Err(err).Send()
 		tracker.FailedComputeSimilarityID()
 	}
 
-	return model.Vulnerability{
+	vulnerability := model.Vulnerability{
 		ID:               0,
 		SimilarityID:     PtrStringToString(similarityID),
 		ScanID:           ctx.scanID,
 		FileID:           file.ID,
-		FileName:         file.FilePath,
+		FileName:         fileName,
 		QueryName:        getStringFromMap("queryName", DefaultQueryName, overrideKey, vObj, &logWithFields),
 		QueryID:          queryID,
 		QueryURI:         getStringFromMap("descriptionUrl", DefaultQueryURI, overrideKey, vObj, &logWithFields),
@@ -155,6 +167,47 @@ var DefaultVulnerabilityBuilder = func(ctx *QueryContext, tracker Tracker,
 		KeyExpectedValue: PtrStringToString(mustMapKeyToString(vObj, "keyExpectedValue")),
 		KeyActualValue:   PtrStringToString(mustMapKeyToString(vObj, "keyActualValue")),
 		Value:            mustMapKeyToString(vObj, "value"),
+		Remediation:      mapKeyToRemediation(vObj),
+		ConstructPath:    constructPath(&file, searchKey),
 		Output:           string(output),
-	}, nil
+	}
+	redactVaulted(&vulnerability, file.Vaulted)
+
+	return vulnerability, nil
+}
+
+// vaultedLine finds the line of the result in the content decrypted from Ansible Vault, results of vaulted files
+// point to the vault header. The snippet is taken from the vaulted source
+func vaultedLine(file *model.FileMetadata, searchKey string, detector *dec.DetectLine) model.VulnerabilityLines {
+	line := 1
+	if !file.Vaulted.File {
+		decrypted := *file
+		decrypted.OriginalData = file.Vaulted.Source
+		if line = detector.DetectLine(&decrypted, searchKey).Line; line == -1 {
+			return model.VulnerabilityLines{Line: -1, VulnLines: []model.CodeLine{}}
+		}
+	}
+	return detector.GetAdjecent(file, line)
+}
+
+// redactVaulted removes the values decrypted from Ansible Vault from the vulnerability, the snippet is
+// taken from the vaulted source, so it does not contain them
+func redactVaulted(vulnerability *model.Vulnerability, vaulted *model.VaultedData) {
+	if vaulted == nil {
+		return
+	}
+	vulnerability.SearchKey = vaulted.Redact(vulnerability.SearchKey)
+	vulnerability.SearchValue = vaulted.Redact(vulnerability.SearchValue)
+	vulnerability.KeyExpectedValue = vaulted.Redact(vulnerability.KeyExpectedValue)
+	vulnerability.KeyActualValue = vaulted.Redact(vulnerability.KeyActualValue)
+	if vulnerability.Value != nil {
+		value := vaulted.Redact(*vulnerability.Value)
+		vulnerability.Value = &value
+	}
+	if vulnerability.Remediation != nil {
+		vulnerability.Remediation = &model.Remediation{
+			Before: vaulted.Redact(vulnerability.Remediation.Before),
+			After:  vaulted.Redact(vulnerability.Remediation.After),
+		}
+	}
 }
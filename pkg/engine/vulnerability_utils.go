@@ -3,7 +3,10 @@ package engine
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strconv"
+	"sync"
 
 	dec "github.com/Checkmarx/kics/pkg/detector"
 	"github.com/Checkmarx/kics/pkg/model"
@@ -38,6 +41,79 @@ func (s *searchLineCalculator) calculate() {
 	}
 }
 
+// refFiles caches the content of the files where the resolved references ('$ref') were defined, so each file is
+// read once for all the results placed inside its references
+type refFiles struct {
+	files map[string]*model.FileMetadata
+	mutex sync.Mutex
+}
+
+func newRefFiles() *refFiles {
+	return &refFiles{files: make(map[string]*model.FileMetadata)}
+}
+
+// get returns the content of the referenced file, nil when it could not be read
+// the files are read without caching when there is no cache
+func (r *refFiles) get(filePath string, kind model.FileKind) *model.FileMetadata {
+	if r == nil {
+		return readRefFile(filePath, kind)
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if file, ok := r.files[filePath]; ok {
+		return file
+	}
+	file := readRefFile(filePath, kind)
+	r.files[filePath] = file
+	return file
+}
+
+func readRefFile(filePath string, kind model.FileKind) *model.FileMetadata {
+	content, err := os.ReadFile(filepath.Clean(filePath))
+	if err != nil {
+		log.Error().Msgf("failed to read referenced file %s: %s", filePath, err)
+		return nil
+	}
+	return &model.FileMetadata{
+		Kind:         kind,
+		FilePath:     filePath,
+		OriginalData: string(content),
+	}
+}
+
+// refOrigin returns the file and lines where the vulnerable content was defined when the result
+// is placed inside a resolved reference ('$ref') that points to other place of the file or to other file
+// searchLineNr is the line found with the searchLine, which is more accurate for array elements
+func refOrigin(vObj map[string]interface{}, searchKey string, searchLineNr int, file *model.FileMetadata,
+	detector *dec.DetectLine, files *refFiles) (string, model.VulnerabilityLines, bool) {
+	pathComponents := dec.SplitSearchKey(searchKey)
+	if searchLine, ok := vObj["searchLine"].([]interface{}); ok {
+		pathComponents = make([]string, 0, len(searchLine))
+		for _, strElement := range searchLine {
+			if str, ok := strElement.(string); ok {
+				pathComponents = append(pathComponents, str)
+			}
+		}
+	}
+
+	origin, ok := dec.GetRefOrigin(pathComponents, file)
+	if !ok {
+		return "", model.VulnerabilityLines{}, false
+	}
+	if searchLineNr > 0 {
+		origin.Line = searchLineNr
+	}
+	if origin.FilePath == file.FilePath {
+		return file.FilePath, detector.GetAdjecent(file, origin.Line), true
+	}
+
+	originFile := files.get(origin.FilePath, file.Kind)
+	if originFile == nil {
+		return "", model.VulnerabilityLines{}, false
+	}
+	return origin.FilePath, detector.GetAdjecent(originFile, origin.Line), true
+}
+
 func mergeWithMetadata(base, additional map[string]interface{}) map[string]interface{} {
 	for k, v := range additional {
 		if _, ok := base[k]; ok {
@@ -61,6 +137,21 @@ func mustMapKeyToString(m map[string]interface{}, key string) *string {
 	return res
 }
 
+// mapKeyToRemediation returns the replacement of the optional 'remediation' key of the result, an object with the
+// text of the line to replace ('before') and the text replacing it ('after')
+func mapKeyToRemediation(m map[string]interface{}) *model.Remediation {
+	remediation, ok := m["remediation"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	before, _ := remediation["before"].(string)
+	after, _ := remediation["after"].(string)
+	if before == "" || before == after {
+		return nil
+	}
+	return &model.Remediation{Before: before, After: after}
+}
+
 func mapKeyToString(m map[string]interface{}, key string, allowNil bool) (*string, error) {
 	v, ok := m[key]
 	if !ok {
@@ -2,9 +2,12 @@ package engine
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
+	"github.com/Checkmarx/kics/pkg/model"
 	"github.com/stretchr/testify/require"
 )
 
@@ -131,6 +134,18 @@ func Test_mustMapKeyToString(t *testing.T) {
 	}
 }
 
+// Test_mapKeyToRemediation tests the functions [mapKeyToRemediation()]
+func Test_mapKeyToRemediation(t *testing.T) {
+	require.Equal(t, &model.Remediation{Before: "false", After: "true"}, mapKeyToRemediation(map[string]interface{}{
+		"remediation": map[string]interface{}{"before": "false", "after": "true"},
+	}))
+	require.Nil(t, mapKeyToRemediation(map[string]interface{}{}))
+	require.Nil(t, mapKeyToRemediation(map[string]interface{}{"remediation": "true"}))
+	require.Nil(t, mapKeyToRemediation(map[string]interface{}{
+		"remediation": map[string]interface{}{"before": "true", "after": "true"},
+	}))
+}
+
 // Test_ptrStringToString tests the functions [ptrStringToString()] and all the methods called by them
 func Test_PtrStringToString(t *testing.T) {
 	type args struct {
@@ -170,3 +185,21 @@ func Test_PtrStringToString(t *testing.T) {
 		})
 	}
 }
+
+// Test_refFiles tests that the referenced files are read once
+func Test_refFiles(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "schemas.yaml")
+	require.NoError(t, os.WriteFile(filePath, []byte("Pet:\n  type: object\n"), os.ModePerm))
+
+	files := newRefFiles()
+	file := files.get(filePath, model.KindYAML)
+	require.NotNil(t, file)
+	require.Equal(t, "Pet:\n  type: object\n", file.OriginalData)
+
+	require.NoError(t, os.WriteFile(filePath, []byte("Pet: {}\n"), os.ModePerm))
+	require.Same(t, file, files.get(filePath, model.KindYAML))
+	require.Nil(t, files.get(filepath.Join(t.TempDir(), "missing.yaml"), model.KindYAML))
+
+	var noCache *refFiles
+	require.Equal(t, "Pet: {}\n", noCache.get(filePath, model.KindYAML).OriginalData)
+}
@@ -0,0 +1,68 @@
+package kics
+
+import (
+	"context"
+	"io"
+
+	"github.com/Checkmarx/kics/pkg/engine/provider"
+	"github.com/Checkmarx/kics/pkg/engine/secrets"
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// SecretsService is a struct that contains a TextSourceProvider to receive every text file, a storage to save the
+// results and a secrets inspector that checks the secrets of the text files that are not scanned by the services,
+// such as the files not supported by the parsers or that failed to be parsed
+type SecretsService struct {
+	SourceProvider   provider.TextSourceProvider
+	Storage          Storage
+	SecretsInspector *secrets.Inspector
+	Tracker          Tracker
+	Filter           provider.TextFilter
+}
+
+// StartScan checks the secrets of the text files that are not part of the scanned files, using the scanID as reference
+func (s *SecretsService) StartScan(ctx context.Context, scanID string, scanned model.FileMetadatas) error {
+	log.Debug().Msg("secretsService.StartScan()")
+	scannedPaths := make(map[string]bool, len(scanned))
+	for i := range scanned {
+		scannedPaths[scanned[i].FilePath] = true
+	}
+
+	currentQuery := make(chan int64)
+	go func() {
+		for range currentQuery {
+		}
+	}()
+	defer close(currentQuery)
+
+	var vulnerabilities []model.Vulnerability
+	err := s.SourceProvider.GetTextSources(ctx, s.Filter, func(ctx context.Context, filename string, rc io.ReadCloser) error {
+		if scannedPaths[filename] {
+			return nil
+		}
+		s.Tracker.TrackFileFound()
+
+		content, err := getContent(rc)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get file content: %s", filename)
+		}
+		file := model.FileMetadata{
+			ID:           uuid.New().String(),
+			ScanID:       scanID,
+			OriginalData: string(*content),
+			Kind:         model.KindCOMMON,
+			FilePath:     filename,
+		}
+		// the files are inspected one at a time, so their content is not kept, the inspector returns all its results
+		vulnerabilities, err = s.SecretsInspector.Inspect(ctx, s.SourceProvider.GetBasePaths(), model.FileMetadatas{file}, currentQuery)
+		return errors.Wrap(err, "failed to inspect secrets")
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to read text sources")
+	}
+
+	return errors.Wrap(s.Storage.SaveVulnerabilities(ctx, vulnerabilities), "failed to save vulnerabilities")
+}
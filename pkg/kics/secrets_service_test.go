@@ -0,0 +1,69 @@
+package kics
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Checkmarx/kics/assets"
+	"github.com/Checkmarx/kics/internal/storage"
+	"github.com/Checkmarx/kics/internal/tracker"
+	"github.com/Checkmarx/kics/pkg/engine/provider"
+	"github.com/Checkmarx/kics/pkg/engine/secrets"
+	"github.com/Checkmarx/kics/pkg/engine/source"
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSecretsService_StartScan tests the function [StartScan()] of the secrets service
+func TestSecretsService_StartScan(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"deploy.sh":   "#!/bin/sh\nexport AWS_SECRET_ACCESS_KEY=\"wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY\"\n",
+		"scanned.tf":  "resource \"aws_db_instance\" \"default\" {\n  password = \"Mustbe8characters\"\n}\n",
+		"broken.tf":   "resource \"aws_db_instance\" \"default\" {\n  password = \"Mustbe8characters\"\n",
+		"binary.data": "password = \"Mustbe8characters\"\x00",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), os.ModePerm))
+	}
+
+	ctx := context.Background()
+	secretsInspector, err := secrets.NewInspector(
+		ctx,
+		map[string]bool{},
+		&tracker.CITracker{},
+		&source.QueryInspectorParameters{
+			IncludeQueries: source.IncludeQueries{ByIDs: []string{}},
+			ExcludeQueries: source.ExcludeQueries{ByIDs: []string{}},
+		},
+		false,
+		60,
+		assets.SecretsQueryRegexRulesJSON,
+		false,
+	)
+	require.NoError(t, err)
+	filesSource, err := provider.NewFileSystemSourceProvider([]string{dir}, []string{})
+	require.NoError(t, err)
+
+	store := storage.NewMemoryStorage()
+	service := &SecretsService{
+		SourceProvider:   filesSource,
+		Storage:          store,
+		SecretsInspector: secretsInspector,
+		Tracker:          &tracker.CITracker{},
+	}
+	// scanned.tf is parsed by the services, its secrets are checked with the scanned files
+	scanned := model.FileMetadatas{{FilePath: filepath.ToSlash(filepath.Join(dir, "scanned.tf"))}}
+	require.NoError(t, service.StartScan(ctx, "scanID", scanned))
+
+	vulnerabilities, err := store.GetVulnerabilities(ctx, "scanID")
+	require.NoError(t, err)
+	got := make(map[string]int)
+	for i := range vulnerabilities {
+		got[filepath.Base(vulnerabilities[i].FileName)] = vulnerabilities[i].Line
+	}
+	require.Equal(t, map[string]int{"deploy.sh": 2, "broken.tf": 2}, got)
+	require.Contains(t, secretsInspector.GetSecrets(), "Mustbe8characters")
+}
@@ -40,6 +40,10 @@ func (s *Service) sink(ctx context.Context, filename, scanID string, rc io.Reade
 	fileCommands := s.Parser.CommentsCommands(filename, *content)
 
 	for _, document := range documents.Docs {
+		// the content decrypted from Ansible Vault is not part of the document scanned by the queries
+		vaulted, _ := document[model.VaultedKey].(*model.VaultedData)
+		delete(document, model.VaultedKey)
+
 		_, err = json.Marshal(document)
 		if err != nil {
 			sentryReport.ReportSentry(&sentryReport.Report{
@@ -62,6 +66,7 @@ func (s *Service) sink(ctx context.Context, filename, scanID string, rc io.Reade
 			FilePath:         filename,
 			Commands:         fileCommands,
 			LinesIgnore:      documents.IgnoreLines,
+			Vaulted:          vaulted,
 		}
 		s.saveToFile(ctx, &file)
 	}
@@ -2,14 +2,22 @@
 package model
 
 import (
+	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
+	"unicode/utf8"
 
 	_ "github.com/mailru/easyjson/gen" //nolint
 	"github.com/rs/zerolog/log"
 )
 
+// VaultedKey is the key of the document that holds the content decrypted from Ansible Vault
+const VaultedKey = "_kics_vaulted"
+
+// RedactedValue replaces the values decrypted from Ansible Vault in the results
+const RedactedValue = "<vaulted>"
+
 // Constants to describe what kind of file refers
 const (
 	KindTerraform FileKind = "TF"
@@ -18,6 +26,8 @@ const (
 	KindDOCKER    FileKind = "DOCKERFILE"
 	KindCOMMON    FileKind = "*"
 	KindHELM      FileKind = "HELM"
+	KindBICEP     FileKind = "BICEP"
+	KindGRAPHQL   FileKind = "GRAPHQL"
 )
 
 // Constants to describe commands given from comments
@@ -98,9 +108,13 @@ type CodeLine struct {
 
 // ExtractedPathObject is the struct that contains the path location of extracted source
 // and a boolean to check if it is a local source
+// Archive is set when the files were extracted from the archive in Path, their paths are shown as '<archive>!/<file>'
+// Nested is set when the archive was found in the scanned paths, so it is not a scanned path itself
 type ExtractedPathObject struct {
 	Path      string
 	LocalPath bool
+	Archive   bool
+	Nested    bool
 }
 
 // CommentsCommands list of commands on a file that will be parsed
@@ -120,6 +134,71 @@ type FileMetadata struct {
 	IDInfo           map[int]interface{}
 	Commands         CommentsCommands
 	LinesIgnore      []int
+	Vaulted          *VaultedData
+}
+
+// VaultedData is the content of a document decrypted from Ansible Vault, it is only kept in memory to be checked
+// for secrets, Lines has the line of the vaulted source of each line of Content and Values has the decrypted values,
+// which are redacted from the results. File is set when the whole file is vaulted, otherwise Source is the original
+// content with the '!vault' strings replaced by their values, in the same lines, to find the lines of the results
+type VaultedData struct {
+	Content string
+	Lines   []int
+	Values  []string
+	Source  string
+	File    bool
+}
+
+// Redact replaces the decrypted values found in text, a value is only replaced where it is not part of a longer word
+func (v *VaultedData) Redact(text string) string {
+	if v == nil {
+		return text
+	}
+	values := make([]string, len(v.Values))
+	copy(values, v.Values)
+	// longer values first, so values that contain other values are fully redacted
+	sort.Slice(values, func(i, j int) bool {
+		return len(values[i]) > len(values[j])
+	})
+	for _, value := range values {
+		if value != "" {
+			text = redactValue(text, value)
+		}
+	}
+	return text
+}
+
+// redactValue replaces the occurrences of the value that are not preceded or followed by a word character
+func redactValue(text, value string) string {
+	var redacted strings.Builder
+	start := 0
+	for offset := 0; offset < len(text); {
+		idx := strings.Index(text[offset:], value)
+		if idx < 0 {
+			break
+		}
+		idx += offset
+		end := idx + len(value)
+		if isWordByte(text, idx-1) || isWordByte(text, end) {
+			offset = idx + 1
+			continue
+		}
+		redacted.WriteString(text[start:idx])
+		redacted.WriteString(RedactedValue)
+		start, offset = end, end
+	}
+	redacted.WriteString(text[start:])
+	return redacted.String()
+}
+
+// isWordByte returns true if the byte in the index of the text is part of a word, the bytes of multibyte
+// characters are always part of a word
+func isWordByte(text string, idx int) bool {
+	if idx < 0 || idx >= len(text) {
+		return false
+	}
+	c := text[idx]
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-' || c >= utf8.RuneSelf
 }
 
 // QueryMetadata is a representation of general information about a query
@@ -137,29 +216,63 @@ type QueryMetadata struct {
 // Vulnerability is a representation of a detected vulnerability in scanned files
 // after running a query
 type Vulnerability struct {
-	ID               int        `json:"id"`
-	ScanID           string     `db:"scan_id" json:"-"`
-	SimilarityID     string     `db:"similarity_id" json:"similarityID"`
-	FileID           string     `db:"file_id" json:"-"`
-	FileName         string     `db:"file_name" json:"fileName"`
-	QueryID          string     `db:"query_id" json:"queryID"`
-	QueryName        string     `db:"query_name" json:"queryName"`
-	QueryURI         string     `json:"-"`
-	Category         string     `json:"category"`
-	Description      string     `json:"description"`
-	DescriptionID    string     `json:"descriptionID"`
-	Platform         string     `db:"platform" json:"platform"`
-	Severity         Severity   `json:"severity"`
-	Line             int        `json:"line"`
-	VulnLines        []CodeLine `json:"vulnLines"`
-	IssueType        IssueType  `db:"issue_type" json:"issueType"`
-	SearchKey        string     `db:"search_key" json:"searchKey"`
-	SearchLine       int        `db:"search_line" json:"searchLine"`
-	SearchValue      string     `db:"search_value" json:"searchValue"`
-	KeyExpectedValue string     `db:"key_expected_value" json:"expectedValue"`
-	KeyActualValue   string     `db:"key_actual_value" json:"actualValue"`
-	Value            *string    `db:"value" json:"value"`
-	Output           string     `json:"-"`
+	ID               int          `json:"id"`
+	ScanID           string       `db:"scan_id" json:"-"`
+	SimilarityID     string       `db:"similarity_id" json:"similarityID"`
+	FileID           string       `db:"file_id" json:"-"`
+	FileName         string       `db:"file_name" json:"fileName"`
+	QueryID          string       `db:"query_id" json:"queryID"`
+	QueryName        string       `db:"query_name" json:"queryName"`
+	QueryURI         string       `json:"-"`
+	Category         string       `json:"category"`
+	Description      string       `json:"description"`
+	DescriptionID    string       `json:"descriptionID"`
+	CWE              string       `json:"cwe,omitempty"`
+	Platform         string       `db:"platform" json:"platform"`
+	Severity         Severity     `json:"severity"`
+	Line             int          `json:"line"`
+	VulnLines        []CodeLine   `json:"vulnLines"`
+	IssueType        IssueType    `db:"issue_type" json:"issueType"`
+	SearchKey        string       `db:"search_key" json:"searchKey"`
+	SearchLine       int          `db:"search_line" json:"searchLine"`
+	SearchValue      string       `db:"search_value" json:"searchValue"`
+	KeyExpectedValue string       `db:"key_expected_value" json:"expectedValue"`
+	KeyActualValue   string       `db:"key_actual_value" json:"actualValue"`
+	Value            *string      `db:"value" json:"value"`
+	ConstructPath    string       `json:"constructPath,omitempty"`
+	Commits          []Commit     `json:"commits,omitempty"`
+	Suppression      *Suppression `json:"suppression,omitempty"`
+	Remediation      *Remediation `json:"remediation,omitempty"`
+	Output           string       `json:"-"`
+}
+
+// Remediation is the replacement of the text of the line of a result that fixes it, given by the query
+type Remediation struct {
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// Commit is a git commit that added the content of a result found in the history of a repository
+type Commit struct {
+	SHA    string `json:"sha"`
+	Author string `json:"author"`
+	Date   string `json:"date"`
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+}
+
+// Kinds of suppression of a result
+const (
+	// SuppressionInSource is the kind of the results ignored by a 'kics-scan' comment of the scanned file
+	SuppressionInSource = "inSource"
+	// SuppressionExternal is the kind of the results excluded by the configuration of the scan
+	SuppressionExternal = "external"
+)
+
+// Suppression describes why a result found by a query is not reported as a vulnerability
+type Suppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification,omitempty"`
 }
 
 // QueryConfig is a struct that contains the fileKind and platform of the rego query
@@ -194,6 +307,21 @@ func (e Extensions) Include(ext string) bool {
 	return b
 }
 
+// compoundExtensions are the extensions made of two extensions that are handled as extensions on their own,
+// the JSON configuration syntax of Terraform is not parsed as generic JSON
+var compoundExtensions = []string{".tf.json", ".tfvars.json"}
+
+// FileExtension returns the extension of the file, compound extensions (e.g. '.tf.json') are returned as a whole
+func FileExtension(path string) string {
+	base := strings.ToLower(filepath.Base(path))
+	for _, ext := range compoundExtensions {
+		if strings.HasSuffix(base, ext) && base != ext {
+			return ext
+		}
+	}
+	return filepath.Ext(path)
+}
+
 // LineObject is the struct that will hold line information for each key
 type LineObject struct {
 	Line int                     `json:"_kics_line"`
@@ -95,3 +95,32 @@ func TestFileMetadatas(t *testing.T) {
 		require.Equal(t, Documents{Documents: []Document{}}, result)
 	})
 }
+
+// TestVaultedData_Redact tests the functions [Redact()] and all the methods called by them
+func TestVaultedData_Redact(t *testing.T) {
+	vaulted := &VaultedData{Values: []string{"secret", "secret-bucket", ""}}
+	require.Equal(t, "name={{<vaulted>}}.acl is <vaulted>", vaulted.Redact("name={{secret-bucket}}.acl is secret"))
+	// the values are not replaced inside longer words
+	require.Equal(t, "secrets: <vaulted>, mysecret, secret_key", vaulted.Redact("secrets: secret, mysecret, secret_key"))
+
+	var empty *VaultedData
+	require.Equal(t, "secret", empty.Redact("secret"))
+}
+
+func TestFileExtension(t *testing.T) {
+	tests := map[string]string{
+		"main.tf":              ".tf",
+		"main.tf.json":         ".tf.json",
+		"dir/prod.tfvars.json": ".tfvars.json",
+		"cdk.tf.json":          ".tf.json",
+		"package.json":         ".json",
+		".tf.json":             ".json",
+		"a/B.TF.JSON":          ".tf.json",
+		"Dockerfile":           "",
+	}
+	for path, want := range tests {
+		t.Run(path, func(t *testing.T) {
+			require.Equal(t, want, FileExtension(path))
+		})
+	}
+}
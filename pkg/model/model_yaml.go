@@ -3,15 +3,23 @@ package model
 import (
 	json "encoding/json"
 	"errors"
+	"fmt"
 	"strconv"
 
 	"github.com/rs/zerolog/log"
 	"gopkg.in/yaml.v3"
 )
 
+// maxYAMLAliasNodes is the maximum number of nodes added to a document by its aliases
+const maxYAMLAliasNodes = 100000
+
 // UnmarshalYAML is a custom yaml parser that places line information in the payload
 func (m *Document) UnmarshalYAML(value *yaml.Node) error {
-	dpc := unmarshal(value)
+	decoder := &yamlDecoder{expanding: make(map[*yaml.Node]bool)}
+	dpc, err := decoder.unmarshal(value)
+	if err != nil {
+		return err
+	}
 	if mapDcp, ok := dpc.(map[string]interface{}); ok {
 		// set line information for root level objects
 		mapDcp["_kics_lines"] = getLines(value, 0)
@@ -24,6 +32,38 @@ func (m *Document) UnmarshalYAML(value *yaml.Node) error {
 	return errors.New("failed to parse yaml content")
 }
 
+// yamlDecoder keeps the state of the expansion of the aliases of a document, to reject recursive aliases
+// and aliases expanding to too many nodes (billion laughs)
+type yamlDecoder struct {
+	// expanding are the anchored nodes being expanded by an alias
+	expanding map[*yaml.Node]bool
+	// expanded is the number of nodes added by the aliases
+	expanded int
+}
+
+// enter resolves the alias of the node, the anchored node is marked as being expanded until leave is called,
+// an alias of a node being expanded is recursive
+func (d *yamlDecoder) enter(val *yaml.Node) (resolved *yaml.Node, leave func(), err error) {
+	leave = func() {}
+	if val.Kind == yaml.AliasNode && val.Alias != nil {
+		anchored := resolveAlias(val)
+		if d.expanding[anchored] {
+			return nil, leave, fmt.Errorf("recursive yaml alias *%s at line %d", val.Value, val.Line)
+		}
+		d.expanding[anchored] = true
+		leave = func() { delete(d.expanding, anchored) }
+		val = anchored
+	}
+	if len(d.expanding) > 0 {
+		d.expanded++
+		if d.expanded > maxYAMLAliasNodes {
+			leave()
+			return nil, func() {}, fmt.Errorf("yaml aliases expand to more than %d nodes", maxYAMLAliasNodes)
+		}
+	}
+	return val, leave, nil
+}
+
 /*
 	YAML Node TYPES
 
@@ -34,53 +74,148 @@ func (m *Document) UnmarshalYAML(value *yaml.Node) error {
 */
 // unmarshal is the function that will parse the yaml elements and call the functions needed
 // to place their line information in the payload
-func unmarshal(val *yaml.Node) interface{} {
+func (d *yamlDecoder) unmarshal(node *yaml.Node) (interface{}, error) {
 	tmp := make(map[string]interface{})
+	val, leave, err := d.enter(node)
+	defer leave()
+	if err != nil {
+		return nil, err
+	}
 	ignoreCommentsYAML(val)
 
 	// if Yaml Node is an Array than we are working with ansible
 	// which need to be placed inside "playbooks"
 	if val.Kind == yaml.SequenceNode {
-		contentArray := make([]interface{}, 0)
-		for _, contentEntry := range val.Content {
-			contentArray = append(contentArray, unmarshal(contentEntry))
+		contentArray, err := d.unmarshalSequence(val)
+		if err != nil {
+			return nil, err
 		}
 		tmp["playbooks"] = contentArray
 	} else if val.Kind == yaml.ScalarNode {
 		// resolve Scalar Node
-		return scalarNodeResolver(val)
+		return scalarNodeResolver(val), nil
 	} else {
+		merged := make(map[string]interface{})
 		// iterate two by two, since first iteration is the key and the second is the value
 		for i := 0; i < len(val.Content); i += 2 {
 			if val.Content[i].Kind == yaml.ScalarNode {
-				switch val.Content[i+1].Kind {
-				case yaml.ScalarNode:
-					tmp[val.Content[i].Value] = scalarNodeResolver(val.Content[i+1])
-				// in case value iteration is a map
-				case yaml.MappingNode:
-					// unmarshall map value and get its line information
-					tt := unmarshal(val.Content[i+1]).(map[string]interface{})
-					tt["_kics_lines"] = getLines(val.Content[i+1], val.Content[i].Line)
-					tmp[val.Content[i].Value] = tt
-				// in case value iteration is an array
-				case yaml.SequenceNode:
-					contentArray := make([]interface{}, 0)
-					// unmarshall each iteration of the array
-					for _, contentEntry := range val.Content[i+1].Content {
-						contentArray = append(contentArray, unmarshal(contentEntry))
-					}
-					tmp[val.Content[i].Value] = contentArray
+				if err := d.unmarshalValue(tmp, merged, val.Content[i], val.Content[i+1]); err != nil {
+					return nil, err
 				}
 			}
 		}
+		// keys defined in the map override the merged ones
+		for key, value := range merged {
+			if _, ok := tmp[key]; !ok {
+				tmp[key] = value
+			}
+		}
+	}
+	return tmp, nil
+}
+
+// unmarshalValue places the value of the key in tmp, or the keys of the merged maps in merged
+func (d *yamlDecoder) unmarshalValue(tmp, merged map[string]interface{}, key, node *yaml.Node) error {
+	value, leave, err := d.enter(node)
+	defer leave()
+	if err != nil {
+		return err
+	}
+	// merge keys (<<: *anchor) add the keys of the anchored maps
+	if isMergeKey(key) {
+		return d.mergeValues(merged, value)
+	}
+	switch value.Kind {
+	case yaml.ScalarNode:
+		tmp[key.Value] = scalarNodeResolver(value)
+	// in case value iteration is a map
+	case yaml.MappingNode:
+		// unmarshall map value and get its line information
+		tt, err := d.unmarshal(value)
+		if err != nil {
+			return err
+		}
+		// the aliases of the map were checked when unmarshaled, so getLines can follow them
+		tt.(map[string]interface{})["_kics_lines"] = getLines(value, key.Line)
+		tmp[key.Value] = tt
+	// in case value iteration is an array
+	case yaml.SequenceNode:
+		contentArray, err := d.unmarshalSequence(value)
+		if err != nil {
+			return err
+		}
+		tmp[key.Value] = contentArray
+	}
+	return nil
+}
+
+// unmarshalSequence unmarshals each iteration of the array
+func (d *yamlDecoder) unmarshalSequence(val *yaml.Node) ([]interface{}, error) {
+	contentArray := make([]interface{}, 0, len(val.Content))
+	for _, contentEntry := range val.Content {
+		content, err := d.unmarshal(contentEntry)
+		if err != nil {
+			return nil, err
+		}
+		contentArray = append(contentArray, content)
+	}
+	return contentArray, nil
+}
+
+// resolveAlias returns the node referenced by an alias (*anchor) or the node itself
+func resolveAlias(val *yaml.Node) *yaml.Node {
+	for val.Kind == yaml.AliasNode && val.Alias != nil {
+		val = val.Alias
+	}
+	return val
+}
+
+// isMergeKey checks if the key is a YAML merge key (<<)
+func isMergeKey(key *yaml.Node) bool {
+	return key.Kind == yaml.ScalarNode && key.ShortTag() == "!!merge"
+}
+
+// mergeSources returns the maps merged by a merge key, which can be a single map or a list of maps
+func mergeSources(val *yaml.Node) []*yaml.Node {
+	sources := make([]*yaml.Node, 0)
+	switch val.Kind {
+	case yaml.MappingNode:
+		sources = append(sources, val)
+	case yaml.SequenceNode:
+		for _, entry := range val.Content {
+			if resolveAlias(entry).Kind == yaml.MappingNode {
+				sources = append(sources, entry)
+			}
+		}
 	}
-	return tmp
+	return sources
+}
+
+// mergeValues adds the keys of the merged maps that are not yet in merged, since the first maps take precedence
+func (d *yamlDecoder) mergeValues(merged map[string]interface{}, val *yaml.Node) error {
+	for _, source := range mergeSources(val) {
+		unmarshaled, err := d.unmarshal(source)
+		if err != nil {
+			return err
+		}
+		values, ok := unmarshaled.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key, value := range values {
+			if _, exists := merged[key]; !exists {
+				merged[key] = value
+			}
+		}
+	}
+	return nil
 }
 
 // getLines creates the map containing the line information for the yaml Node
 // def is the line to be used as "_kics__default"
 func getLines(val *yaml.Node, def int) map[string]LineObject {
 	lineMap := make(map[string]LineObject)
+	val = resolveAlias(val)
 
 	// line information map
 	lineMap["_kics__default"] = LineObject{
@@ -93,12 +228,25 @@ func getLines(val *yaml.Node, def int) map[string]LineObject {
 		return getSeqLines(val, def)
 	}
 
+	mergedLines := make(map[string]LineObject)
 	// iterate two by two, since first iteration is the key and the second is the value
 	for i := 0; i < len(val.Content); i += 2 {
+		value := resolveAlias(val.Content[i+1])
+		// merged keys keep the lines where they were defined in the anchored map
+		if isMergeKey(val.Content[i]) {
+			for _, source := range mergeSources(value) {
+				for key, line := range getLines(source, def) {
+					if _, exists := mergedLines[key]; !exists && key != "_kics__default" {
+						mergedLines[key] = line
+					}
+				}
+			}
+			continue
+		}
 		lineArr := make([]map[string]LineObject, 0)
 		// in case the value iteration is an array call getLines for each iteration of the array
-		if val.Content[i+1].Kind == yaml.SequenceNode {
-			for _, contentEntry := range val.Content[i+1].Content {
+		if value.Kind == yaml.SequenceNode {
+			for _, contentEntry := range value.Content {
 				defaultLine := val.Content[i].Line
 				if contentEntry.Kind == yaml.ScalarNode {
 					defaultLine = contentEntry.Line
@@ -116,6 +264,12 @@ func getLines(val *yaml.Node, def int) map[string]LineObject {
 		}
 	}
 
+	for key, line := range mergedLines {
+		if _, ok := lineMap[key]; !ok {
+			lineMap[key] = line
+		}
+	}
+
 	return lineMap
 }
 
@@ -2,6 +2,7 @@ package model
 
 import (
 	json "encoding/json"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -460,3 +461,89 @@ func compareJSONLine(t *testing.T, test1 interface{}, test2 string) {
 	require.NoError(t, err)
 	require.JSONEq(t, test2, string(stringefiedJSON))
 }
+
+// TestDocument_UnmarshalYAML_Anchors tests that aliases and merge keys are resolved with the lines where they are defined
+func TestDocument_UnmarshalYAML_Anchors(t *testing.T) {
+	content := `x-defaults: &defaults
+  privileged: true
+  restart: always
+services:
+  web:
+    <<: *defaults
+    restart: "no"
+    labels: &labels
+      team: web
+  worker:
+    labels: *labels
+`
+	var doc Document
+	require.NoError(t, yaml.Unmarshal([]byte(content), &doc))
+
+	compareJSONLine(t, doc["services"], `{
+		"_kics_lines": {
+			"_kics__default": {"_kics_line": 4},
+			"_kics_web": {"_kics_line": 5},
+			"_kics_worker": {"_kics_line": 10}
+		},
+		"web": {
+			"_kics_lines": {
+				"_kics__default": {"_kics_line": 5},
+				"_kics_privileged": {"_kics_line": 2},
+				"_kics_restart": {"_kics_line": 7},
+				"_kics_labels": {"_kics_line": 8}
+			},
+			"privileged": true,
+			"restart": "no",
+			"labels": {
+				"_kics_lines": {
+					"_kics__default": {"_kics_line": 8},
+					"_kics_team": {"_kics_line": 9}
+				},
+				"team": "web"
+			}
+		},
+		"worker": {
+			"_kics_lines": {
+				"_kics__default": {"_kics_line": 10},
+				"_kics_labels": {"_kics_line": 11}
+			},
+			"labels": {
+				"_kics_lines": {
+					"_kics__default": {"_kics_line": 11},
+					"_kics_team": {"_kics_line": 9}
+				},
+				"team": "web"
+			}
+		}
+	}`)
+}
+
+// TestDocument_UnmarshalYAML_RecursiveAliases tests that recursive aliases and aliases expanding to too many nodes
+// are rejected instead of exhausting the stack or the memory
+func TestDocument_UnmarshalYAML_RecursiveAliases(t *testing.T) {
+	laughs := "a: &a [x, x, x, x, x, x, x, x, x, x]\n"
+	for level := 'b'; level <= 'i'; level++ {
+		prev := string(level - 1)
+		laughs += fmt.Sprintf("%c: &%c [*%s, *%s, *%s, *%s, *%s, *%s, *%s, *%s, *%s, *%s]\n",
+			level, level, prev, prev, prev, prev, prev, prev, prev, prev, prev, prev)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "self reference", content: "a: &a\n  b: *a\n", wantErr: "recursive yaml alias *a at line 2"},
+		{name: "sequence self reference", content: "a: &a\n  - b\n  - *a\n", wantErr: "recursive yaml alias *a at line 3"},
+		{name: "merge self reference", content: "a: &a\n  b: c\n  <<: *a\n", wantErr: "recursive yaml alias *a at line 3"},
+		{name: "billion laughs", content: laughs, wantErr: "yaml aliases expand to more than 100000 nodes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc Document
+			err := yaml.Unmarshal([]byte(tt.content), &doc)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -20,19 +22,32 @@ type SeveritySummary struct {
 	TotalBOMResources int              `json:"total_bom_resources"`
 }
 
+// Baseline states of a result, when the results are compared against the results of a baseline
+const (
+	BaselineStateNew       = "new"
+	BaselineStateUnchanged = "unchanged"
+	BaselineStateUpdated   = "updated"
+	BaselineStateAbsent    = "absent"
+)
+
 // VulnerableFile contains information of a vulnerable file and where the vulnerability was found
 type VulnerableFile struct {
-	FileName         string     `json:"file_name"`
-	SimilarityID     string     `json:"similarity_id"`
-	Line             int        `json:"line"`
-	VulnLines        []CodeLine `json:"-"`
-	IssueType        IssueType  `json:"issue_type"`
-	SearchKey        string     `json:"search_key"`
-	SearchLine       int        `json:"search_line"`
-	SearchValue      string     `json:"search_value"`
-	KeyExpectedValue string     `json:"expected_value"`
-	KeyActualValue   string     `json:"actual_value"`
-	Value            *string    `json:"value,omitempty"`
+	FileName         string       `json:"file_name"`
+	SimilarityID     string       `json:"similarity_id"`
+	Line             int          `json:"line"`
+	VulnLines        []CodeLine   `json:"-"`
+	IssueType        IssueType    `json:"issue_type"`
+	SearchKey        string       `json:"search_key"`
+	SearchLine       int          `json:"search_line"`
+	SearchValue      string       `json:"search_value"`
+	KeyExpectedValue string       `json:"expected_value"`
+	KeyActualValue   string       `json:"actual_value"`
+	Value            *string      `json:"value,omitempty"`
+	ConstructPath    string       `json:"construct_path,omitempty"`
+	Commits          []Commit     `json:"commits,omitempty"`
+	BaselineState    string       `json:"baseline_state,omitempty"`
+	Suppression      *Suppression `json:"suppression,omitempty"`
+	Remediation      *Remediation `json:"remediation,omitempty"`
 }
 
 // QueryResult contains a query that tested positive ID, name, severity and a list of files that tested vulnerable
@@ -45,6 +60,7 @@ type QueryResult struct {
 	Category                    string           `json:"category"`
 	Description                 string           `json:"description"`
 	DescriptionID               string           `json:"description_id"`
+	CWE                         string           `json:"cwe,omitempty"`
 	CISDescriptionIDFormatted   string           `json:"cis_description_id,omitempty"`
 	CISDescriptionTitle         string           `json:"cis_description_title,omitempty"`
 	CISDescriptionTextFormatted string           `json:"cis_description_text,omitempty"`
@@ -92,6 +108,8 @@ type Summary struct {
 	ScannedPaths []string         `json:"paths"`
 	Queries      QueryResultSlice `json:"queries"`
 	Bom          QueryResultSlice `json:"bill_of_materials,omitempty"`
+	// Suppressed are the results ignored by 'kics-scan' comments or excluded by '--exclude-results'
+	Suppressed QueryResultSlice `json:"suppressed,omitempty"`
 }
 
 // PathParameters - structure wraps the required fields for temporary path translation
@@ -101,7 +119,7 @@ type PathParameters struct {
 }
 
 var (
-	queryRegex   = regexp.MustCompile(`\?([\w-]+(=[\w-]*)?(&[\w-]+(=[\w-]*)?)*)?`)
+	queryRegex   = regexp.MustCompile(`\?([\w-]+(=[\w:.-]*)?(&[\w-]+(=[\w:.-]*)?)*)?`)
 	urlAuthRegex = regexp.MustCompile(`((ssh|https?)://)(\S+(:\S*)?@).*`)
 )
 
@@ -118,28 +136,34 @@ func getRelativePath(basePath, filePath string) string {
 	return returnPath
 }
 
+// replaceIfTemporaryPath replaces the temporary path of the extracted sources by the given path, the files of
+// archives are shown as '<archive>!/<file>', where the archive path is also replaced when it is temporary
 func replaceIfTemporaryPath(filePath string, pathExtractionMap map[string]ExtractedPathObject) string {
-	prettyPath := filePath
 	for key, val := range pathExtractionMap {
-		if strings.Contains(filePath, key) {
-			splittedPath := strings.Split(filePath, key)
-			if !val.LocalPath {
-				// remove authentication information from the URL
-				sanitizedURL := removeURLCredentials(val.Path)
-				// remove query parameters '?key=value&key2=value'
-				return filepath.FromSlash(queryRegex.ReplaceAllString(sanitizedURL, "") + splittedPath[1])
-			}
-			prettyPath = filepath.FromSlash(filepath.Base(val.Path) + splittedPath[1])
-		} else {
-			prettyPath = filePath
+		if !strings.Contains(filePath, key) {
+			continue
+		}
+		splittedPath := strings.Split(filePath, key)
+		if val.Archive {
+			return replaceIfTemporaryPath(filepath.FromSlash(val.Path), pathExtractionMap) + "!" + filepath.FromSlash(splittedPath[1])
+		}
+		if !val.LocalPath {
+			// remove authentication information from the URL
+			sanitizedURL := removeURLCredentials(val.Path)
+			// remove query parameters '?key=value&key2=value'
+			return filepath.FromSlash(queryRegex.ReplaceAllString(sanitizedURL, "") + splittedPath[1])
 		}
+		return filepath.FromSlash(filepath.Base(val.Path) + splittedPath[1])
 	}
-	return prettyPath
+	return filePath
 }
 
 func removeAllURLCredentials(pathExtractionMap map[string]ExtractedPathObject) []string {
 	sanitizedScannedPaths := make([]string, 0)
 	for _, val := range pathExtractionMap {
+		if val.Nested {
+			continue
+		}
 		if !val.LocalPath {
 			sanitizedURL := removeURLCredentials(val.Path)
 			sanitizedScannedPaths = append(sanitizedScannedPaths, sanitizedURL)
@@ -176,10 +200,188 @@ func resolvePath(filePath string, pathExtractionMap map[string]ExtractedPathObje
 func CreateSummary(counters Counters, vulnerabilities []Vulnerability,
 	scanID string, pathExtractionMap map[string]ExtractedPathObject, version Version) Summary {
 	log.Debug().Msg("model.CreateSummary()")
-	q := make(map[string]QueryResult, len(vulnerabilities))
+	q := groupQueryResults(vulnerabilities, pathExtractionMap)
 	severitySummary := SeveritySummary{
 		ScanID: scanID,
 	}
+
+	queries := make([]QueryResult, 0, len(q))
+	sevs := map[Severity]int{SeverityTrace: 0, SeverityInfo: 0, SeverityLow: 0, SeverityMedium: 0, SeverityHigh: 0}
+	for idx := range q {
+		sevs[q[idx].Severity] += len(q[idx].Files)
+
+		if q[idx].Severity == SeverityTrace {
+			continue
+		}
+		queries = append(queries, q[idx])
+
+		severitySummary.TotalCounter += len(q[idx].Files)
+	}
+	sortQueryResults(queries)
+
+	materials := make([]QueryResult, 0, len(q))
+	for idx := range q {
+		if q[idx].Severity == SeverityTrace {
+			materials = append(materials, q[idx])
+			severitySummary.TotalBOMResources += len(q[idx].Files)
+		}
+	}
+
+	severitySummary.SeverityCounters = sevs
+
+	return Summary{
+		Bom:             materials,
+		Counters:        counters,
+		Queries:         queries,
+		SeveritySummary: severitySummary,
+		ScannedPaths:    removeAllURLCredentials(pathExtractionMap),
+		LatestVersion:   version,
+	}
+}
+
+// AddSuppressed adds the suppressed results to the summary, they are not counted as results of the scan
+func (s *Summary) AddSuppressed(vulnerabilities []Vulnerability, pathExtractionMap map[string]ExtractedPathObject) {
+	q := groupQueryResults(vulnerabilities, pathExtractionMap)
+	for idx := range q {
+		if q[idx].Severity != SeverityTrace {
+			s.Suppressed = append(s.Suppressed, q[idx])
+		}
+	}
+	sortQueryResults(s.Suppressed)
+}
+
+// MergeSummaries merges the summaries of several scans into a single summary, the results found by more than one
+// scan are kept once and the severity counters are computed from the merged results
+// The files counters are summed, since each scan is expected to scan different files, while the queries counters
+// keep the highest value of the scans. A summary equal to one already merged (e.g. the same report given twice)
+// is skipped, so its counters are not summed twice
+func MergeSummaries(summaries []Summary) Summary {
+	merged := Summary{ScannedPaths: make([]string, 0)}
+	scannedPaths := make(map[string]bool)
+	queries := make([]QueryResultSlice, 0, len(summaries))
+	materials := make([]QueryResultSlice, 0, len(summaries))
+	suppressed := make([]QueryResultSlice, 0, len(summaries))
+	mergedSummaries := make(map[[sha256.Size]byte]bool, len(summaries))
+
+	for i := range summaries {
+		summary := &summaries[i]
+		if content, err := json.Marshal(summary); err == nil {
+			hash := sha256.Sum256(content)
+			if mergedSummaries[hash] {
+				log.Warn().Msgf("Skipping summary of scan %s, already merged", summary.ScanID)
+				continue
+			}
+			mergedSummaries[hash] = true
+		}
+		if merged.Version == "" {
+			merged.Version = summary.Version
+		}
+		if merged.ScanID == "" {
+			merged.ScanID = summary.ScanID
+		}
+		merged.ScannedFiles += summary.ScannedFiles
+		merged.ParsedFiles += summary.ParsedFiles
+		merged.FailedToScanFiles += summary.FailedToScanFiles
+		merged.TotalQueries = maxInt(merged.TotalQueries, summary.TotalQueries)
+		merged.FailedToExecuteQueries = maxInt(merged.FailedToExecuteQueries, summary.FailedToExecuteQueries)
+		merged.FailedSimilarityID = maxInt(merged.FailedSimilarityID, summary.FailedSimilarityID)
+
+		if !summary.Start.IsZero() && (merged.Start.IsZero() || summary.Start.Before(merged.Start)) {
+			merged.Start = summary.Start
+		}
+		if summary.End.After(merged.End) {
+			merged.End = summary.End
+		}
+
+		for _, path := range summary.ScannedPaths {
+			if !scannedPaths[path] {
+				scannedPaths[path] = true
+				merged.ScannedPaths = append(merged.ScannedPaths, path)
+			}
+		}
+
+		queries = append(queries, summary.Queries)
+		materials = append(materials, summary.Bom)
+		suppressed = append(suppressed, summary.Suppressed)
+	}
+
+	// a result suppressed in a scan but reported by another one is kept as a result
+	reported := make(map[string]bool)
+	merged.Queries = mergeQueryResults(queries, reported)
+	merged.Bom = mergeQueryResults(materials, make(map[string]bool))
+	merged.Suppressed = mergeQueryResults(suppressed, reported)
+
+	merged.SeverityCounters = map[Severity]int{
+		SeverityTrace: 0, SeverityInfo: 0, SeverityLow: 0, SeverityMedium: 0, SeverityHigh: 0,
+	}
+	for idx := range merged.Queries {
+		merged.SeverityCounters[merged.Queries[idx].Severity] += len(merged.Queries[idx].Files)
+		merged.TotalCounter += len(merged.Queries[idx].Files)
+	}
+	for idx := range merged.Bom {
+		merged.SeverityCounters[merged.Bom[idx].Severity] += len(merged.Bom[idx].Files)
+		merged.TotalBOMResources += len(merged.Bom[idx].Files)
+	}
+
+	return merged
+}
+
+// mergeQueryResults merges the results of the queries, skipping the files whose key was already seen
+func mergeQueryResults(results []QueryResultSlice, seen map[string]bool) QueryResultSlice {
+	queries := make(map[string]*QueryResult)
+	order := make([]string, 0)
+	for _, slice := range results {
+		for i := range slice {
+			query, ok := queries[slice[i].QueryID]
+			if !ok {
+				query = &QueryResult{}
+				*query = slice[i]
+				query.Files = make([]VulnerableFile, 0, len(slice[i].Files))
+				queries[slice[i].QueryID] = query
+				order = append(order, slice[i].QueryID)
+			}
+			for j := range slice[i].Files {
+				key := mergeKey(slice[i].QueryID, &slice[i].Files[j])
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				query.Files = append(query.Files, slice[i].Files[j])
+			}
+		}
+	}
+
+	merged := make(QueryResultSlice, 0, len(order))
+	for _, id := range order {
+		if len(queries[id].Files) > 0 {
+			merged = append(merged, *queries[id])
+		}
+	}
+	sortQueryResults(merged)
+	return merged
+}
+
+// mergeKey returns the key of a result, its query, file, line and similarity ID, since the similarity IDs are
+// relative to the path scanned and can be the same for different files of different scans
+// The search key tells apart the results without similarity ID
+func mergeKey(queryID string, file *VulnerableFile) string {
+	key := fmt.Sprintf("%s:%s:%d:%s", queryID, file.FileName, file.Line, file.SimilarityID)
+	if file.SimilarityID == "" {
+		key += ":" + file.SearchKey
+	}
+	return key
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// groupQueryResults groups the vulnerabilities by query
+func groupQueryResults(vulnerabilities []Vulnerability, pathExtractionMap map[string]ExtractedPathObject) map[string]QueryResult {
+	q := make(map[string]QueryResult, len(vulnerabilities))
 	for i := range vulnerabilities {
 		item := vulnerabilities[i]
 		if _, ok := q[item.QueryID]; !ok {
@@ -192,6 +394,7 @@ func CreateSummary(counters Counters, vulnerabilities []Vulnerability,
 				Category:      item.Category,
 				Description:   item.Description,
 				DescriptionID: item.DescriptionID,
+				CWE:           item.CWE,
 			}
 		}
 
@@ -207,24 +410,19 @@ func CreateSummary(counters Counters, vulnerabilities []Vulnerability,
 			KeyExpectedValue: item.KeyExpectedValue,
 			KeyActualValue:   item.KeyActualValue,
 			Value:            item.Value,
+			ConstructPath:    item.ConstructPath,
+			Commits:          item.Commits,
+			Suppression:      item.Suppression,
+			Remediation:      item.Remediation,
 		})
 
 		q[item.QueryID] = qItem
 	}
+	return q
+}
 
-	queries := make([]QueryResult, 0, len(q))
-	sevs := map[Severity]int{SeverityTrace: 0, SeverityInfo: 0, SeverityLow: 0, SeverityMedium: 0, SeverityHigh: 0}
-	for idx := range q {
-		sevs[q[idx].Severity] += len(q[idx].Files)
-
-		if q[idx].Severity == SeverityTrace {
-			continue
-		}
-		queries = append(queries, q[idx])
-
-		severitySummary.TotalCounter += len(q[idx].Files)
-	}
-
+// sortQueryResults sorts the queries by severity and name
+func sortQueryResults(queries []QueryResult) {
 	severityOrder := map[Severity]int{SeverityTrace: 4, SeverityInfo: 3, SeverityLow: 2, SeverityMedium: 1, SeverityHigh: 0}
 	sort.Slice(queries, func(i, j int) bool {
 		if severityOrder[queries[i].Severity] == severityOrder[queries[j].Severity] {
@@ -232,25 +430,6 @@ func CreateSummary(counters Counters, vulnerabilities []Vulnerability,
 		}
 		return severityOrder[queries[i].Severity] < severityOrder[queries[j].Severity]
 	})
-
-	materials := make([]QueryResult, 0, len(q))
-	for idx := range q {
-		if q[idx].Severity == SeverityTrace {
-			materials = append(materials, q[idx])
-			severitySummary.TotalBOMResources += len(q[idx].Files)
-		}
-	}
-
-	severitySummary.SeverityCounters = sevs
-
-	return Summary{
-		Bom:             materials,
-		Counters:        counters,
-		Queries:         queries,
-		SeveritySummary: severitySummary,
-		ScannedPaths:    removeAllURLCredentials(pathExtractionMap),
-		LatestVersion:   version,
-	}
 }
 
 // PrintVersionCheck - Prints and logs warning if not using KICS latest version
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -97,6 +98,78 @@ func TestCreateSummary(t *testing.T) {
 	})
 }
 
+// TestSummary_AddSuppressed tests the function [AddSuppressed()], the suppressed results are not counted
+func TestSummary_AddSuppressed(t *testing.T) {
+	suppression := &Suppression{Kind: SuppressionInSource, Justification: "ignored"}
+	vulnerabilities := []Vulnerability{
+		{QueryID: "low", QueryName: "low query", Severity: SeverityLow, FileName: "a.tf", Line: 1, Suppression: suppression},
+		{QueryID: "high", QueryName: "high query", Severity: SeverityHigh, FileName: "b.tf", Line: 2, Suppression: suppression},
+		{QueryID: "high", QueryName: "high query", Severity: SeverityHigh, FileName: "c.tf", Line: 3, Suppression: suppression},
+		{QueryID: "bom", QueryName: "bom query", Severity: SeverityTrace, FileName: "d.tf", Line: 4, Suppression: suppression},
+	}
+
+	summary := CreateSummary(Counters{}, []Vulnerability{}, "scanID", map[string]ExtractedPathObject{}, Version{})
+	summary.AddSuppressed(vulnerabilities, map[string]ExtractedPathObject{})
+
+	require.Equal(t, 0, summary.TotalCounter)
+	require.Len(t, summary.Suppressed, 2)
+	require.Equal(t, "high", summary.Suppressed[0].QueryID)
+	require.Len(t, summary.Suppressed[0].Files, 2)
+	require.Equal(t, suppression, summary.Suppressed[0].Files[0].Suppression)
+	require.Equal(t, "low", summary.Suppressed[1].QueryID)
+}
+
+// TestMergeSummaries tests the function [MergeSummaries()], the results are deduplicated and the counters recomputed
+func TestMergeSummaries(t *testing.T) {
+	start := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	first := CreateSummary(Counters{ScannedFiles: 2, ParsedFiles: 2, TotalQueries: 10}, []Vulnerability{
+		{QueryID: "high", QueryName: "high query", Severity: SeverityHigh, FileName: "a.tf", Line: 1, SimilarityID: "1"},
+		{QueryID: "low", QueryName: "low query", Severity: SeverityLow, FileName: "a.tf", Line: 2, SimilarityID: "2"},
+		{QueryID: "bom", QueryName: "bom query", Severity: SeverityTrace, FileName: "a.tf", Line: 3, SimilarityID: "3"},
+	}, "first", map[string]ExtractedPathObject{}, Version{})
+	first.ScannedPaths = []string{"infra"}
+	first.Times = Times{Start: start, End: start.Add(time.Minute)}
+	first.AddSuppressed([]Vulnerability{
+		{QueryID: "medium", QueryName: "medium query", Severity: SeverityMedium, FileName: "b.tf", Line: 1, SimilarityID: "4"},
+	}, map[string]ExtractedPathObject{})
+
+	second := CreateSummary(Counters{ScannedFiles: 3, ParsedFiles: 1, TotalQueries: 12}, []Vulnerability{
+		{QueryID: "high", QueryName: "high query", Severity: SeverityHigh, FileName: "a.tf", Line: 1, SimilarityID: "1"},
+		{QueryID: "high", QueryName: "high query", Severity: SeverityHigh, FileName: "c.tf", Line: 5, SimilarityID: "5"},
+		{QueryID: "medium", QueryName: "medium query", Severity: SeverityMedium, FileName: "b.tf", Line: 1, SimilarityID: "4"},
+		// similarity IDs are relative to the scanned path, the same ID on another file is another result
+		{QueryID: "low", QueryName: "low query", Severity: SeverityLow, FileName: "d.tf", Line: 2, SimilarityID: "2"},
+	}, "second", map[string]ExtractedPathObject{}, Version{})
+	second.ScannedPaths = []string{"infra", "charts"}
+	second.Times = Times{Start: start.Add(-time.Minute), End: start.Add(time.Second)}
+
+	// the first summary is given twice, its counters are summed once
+	merged := MergeSummaries([]Summary{first, second, first})
+
+	require.Equal(t, "first", merged.ScanID)
+	require.Equal(t, Counters{ScannedFiles: 5, ParsedFiles: 3, TotalQueries: 12}, merged.Counters)
+	require.Equal(t, Times{Start: start.Add(-time.Minute), End: start.Add(time.Minute)}, merged.Times)
+	require.Equal(t, []string{"infra", "charts"}, merged.ScannedPaths)
+	require.Equal(t, map[Severity]int{
+		SeverityTrace:  1,
+		SeverityInfo:   0,
+		SeverityLow:    2,
+		SeverityMedium: 1,
+		SeverityHigh:   2,
+	}, merged.SeverityCounters)
+	require.Equal(t, 5, merged.TotalCounter)
+	require.Equal(t, 1, merged.TotalBOMResources)
+
+	require.Len(t, merged.Queries, 3)
+	require.Equal(t, "high", merged.Queries[0].QueryID)
+	require.Len(t, merged.Queries[0].Files, 2)
+	require.Equal(t, "medium", merged.Queries[1].QueryID)
+	require.Equal(t, "low", merged.Queries[2].QueryID)
+	require.Len(t, merged.Queries[2].Files, 2)
+	require.Len(t, merged.Bom, 1)
+	require.Empty(t, merged.Suppressed)
+}
+
 func TestModel_resolvePath(t *testing.T) {
 	pwd, err := os.Getwd()
 	if err != nil {
@@ -138,6 +211,39 @@ func TestModel_resolvePath(t *testing.T) {
 			},
 			want: filepath.FromSlash("https//test/relativepath/testing/file/vuln"),
 		},
+		{
+			name: "test_with_checksum_param",
+			args: args{
+				filePath: filepath.FromSlash("/tmp/file/vuln"),
+				pathExtractionMap: map[string]ExtractedPathObject{
+					filepath.FromSlash("/tmp"): {
+						Path:      filepath.FromSlash("https//test/relativepath/main.tf?checksum=sha256:0a1b2c&archive=false"),
+						LocalPath: false,
+					},
+				},
+			},
+			want: filepath.FromSlash("https//test/relativepath/main.tf/file/vuln"),
+		},
+		{
+			name: "test_with_nested_archives",
+			args: args{
+				filePath: filepath.FromSlash("/tmp/kics-archive-2/templates/deploy.yaml"),
+				pathExtractionMap: map[string]ExtractedPathObject{
+					filepath.FromSlash("/tmp/kics-extract-1"): {
+						Path:      "bundle.tar",
+						LocalPath: true,
+						Archive:   true,
+					},
+					filepath.FromSlash("/tmp/kics-archive-2"): {
+						Path:      filepath.FromSlash("/tmp/kics-extract-1/charts/app-1.2.tgz"),
+						LocalPath: true,
+						Archive:   true,
+						Nested:    true,
+					},
+				},
+			},
+			want: filepath.FromSlash("bundle.tar!/charts/app-1.2.tgz!/templates/deploy.yaml"),
+		},
 		{
 			name: "test_with_query_local",
 			args: args{
@@ -236,10 +342,29 @@ func TestRemoveAllURLCredentials(t *testing.T) {
 				"/tmp/file/vuln": "/user/archive.zip",
 			},
 		},
+		{
+			pathExtractionMap: map[string]ExtractedPathObject{
+				"/tmp/file/vuln": {
+					Path:      "/user/archive.zip",
+					LocalPath: true,
+					Archive:   true,
+				},
+				"/tmp/file/nested": {
+					Path:      "/tmp/file/vuln/lambda.zip",
+					LocalPath: true,
+					Archive:   true,
+					Nested:    true,
+				},
+			},
+			want: map[string]string{
+				"/tmp/file/vuln": "/user/archive.zip",
+			},
+		},
 	}
 	for _, tt := range input {
 		got := removeAllURLCredentials(tt.pathExtractionMap)
-		for key := range tt.pathExtractionMap {
+		require.Len(t, got, len(tt.want))
+		for key := range tt.want {
 			require.Contains(t, got, tt.want[key])
 		}
 	}
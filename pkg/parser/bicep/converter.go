@@ -0,0 +1,199 @@
+package bicep
+
+import (
+	"strings"
+
+	"github.com/Checkmarx/kics/pkg/model"
+)
+
+// converter gathers Bicep declarations into the ARM template structure
+// (parameters, variables, resources and outputs) with KICS line information
+type converter struct {
+	parameters     map[string]interface{}
+	variables      map[string]interface{}
+	outputs        map[string]interface{}
+	resources      []interface{}
+	parameterLines map[string]model.LineObject
+	variableLines  map[string]model.LineObject
+	outputLines    map[string]model.LineObject
+	resourceLines  []map[string]model.LineObject
+}
+
+func newConverter() *converter {
+	return &converter{
+		parameters:     make(map[string]interface{}),
+		variables:      make(map[string]interface{}),
+		outputs:        make(map[string]interface{}),
+		resources:      make([]interface{}, 0),
+		parameterLines: make(map[string]model.LineObject),
+		variableLines:  make(map[string]model.LineObject),
+		outputLines:    make(map[string]model.LineObject),
+		resourceLines:  make([]map[string]model.LineObject, 0),
+	}
+}
+
+// armType converts a Bicep type into the equivalent ARM template parameter type
+func armType(bicepType string, secure bool) string {
+	switch bicepType {
+	case "string":
+		if secure {
+			return "secureString"
+		}
+	case "object":
+		if secure {
+			return "secureObject"
+		}
+	}
+	return bicepType
+}
+
+func (c *converter) addParameter(name, paramType string, value interface{}, hasValue bool,
+	decorators []decorator, line int) {
+	secure := false
+	param := make(map[string]interface{})
+	lines := map[string]model.LineObject{
+		"_kics__default": {Line: line},
+		"_kics_type":     {Line: line},
+	}
+
+	for _, dec := range decorators {
+		switch dec.name {
+		case "secure":
+			secure = true
+		case "allowed":
+			if len(dec.args) > 0 {
+				param["allowedValues"] = dec.args[0]
+				lines["_kics_allowedValues"] = model.LineObject{Line: line}
+			}
+		case "description":
+			if len(dec.args) > 0 {
+				param["metadata"] = map[string]interface{}{
+					"description": dec.args[0],
+					kicsLines: map[string]model.LineObject{
+						"_kics__default":    {Line: line},
+						"_kics_description": {Line: line},
+					},
+				}
+			}
+		case "minValue", "maxValue", "minLength", "maxLength":
+			if len(dec.args) > 0 {
+				param[dec.name] = dec.args[0]
+				lines["_kics_"+dec.name] = model.LineObject{Line: line}
+			}
+		}
+	}
+
+	param["type"] = armType(paramType, secure)
+	if hasValue {
+		param["defaultValue"] = value
+		lines["_kics_defaultValue"] = model.LineObject{Line: line}
+	}
+	param[kicsLines] = lines
+
+	c.parameters[name] = param
+	c.parameterLines["_kics_"+name] = model.LineObject{Line: line}
+}
+
+func (c *converter) addVariable(name string, value interface{}, arrLines []map[string]model.LineObject, line int) {
+	c.variables[name] = value
+	c.variableLines["_kics_"+name] = model.LineObject{
+		Line: line,
+		Arr:  arrLines,
+	}
+}
+
+func (c *converter) addOutput(name, outputType string, value interface{}, line int) {
+	c.outputs[name] = map[string]interface{}{
+		"type":  outputType,
+		"value": value,
+		kicsLines: map[string]model.LineObject{
+			"_kics__default": {Line: line},
+			"_kics_type":     {Line: line},
+			"_kics_value":    {Line: line},
+		},
+	}
+	c.outputLines["_kics_"+name] = model.LineObject{Line: line}
+}
+
+// addResource adds a resource declaration to the template
+// 'existing' resources are only references and are not deployed, so only their child resources are kept,
+// moved to the root level with their fully qualified type
+func (c *converter) addResource(res *resourceDecl) {
+	if !res.existing {
+		c.appendResource(res.value)
+		return
+	}
+
+	children, ok := res.value["resources"].([]interface{})
+	if !ok {
+		return
+	}
+	parentType, _ := res.value["type"].(string)
+	for _, child := range children {
+		childValue, ok := child.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if childType, ok := childValue["type"].(string); ok && !strings.HasPrefix(childType, parentType+"/") {
+			childValue["type"] = parentType + "/" + childType
+		}
+		c.appendResource(childValue)
+	}
+}
+
+func (c *converter) appendResource(value map[string]interface{}) {
+	c.resources = append(c.resources, value)
+	c.resourceLines = append(c.resourceLines, value[kicsLines].(map[string]model.LineObject))
+}
+
+// document returns the ARM template document with the root line information
+func (c *converter) document() model.Document {
+	doc := model.Document{
+		"resources": c.resources,
+	}
+	resourcesLine := 0
+	if len(c.resourceLines) > 0 {
+		resourcesLine = c.resourceLines[0]["_kics__default"].Line
+	}
+	lines := map[string]model.LineObject{
+		"_kics__default": {Line: 0},
+		"_kics_resources": {
+			Line: resourcesLine,
+			Arr:  c.resourceLines,
+		},
+	}
+
+	if len(c.parameters) > 0 {
+		c.parameters[kicsLines] = withDefault(c.parameterLines)
+		doc["parameters"] = c.parameters
+		lines["_kics_parameters"] = model.LineObject{Line: firstLine(c.parameterLines)}
+	}
+	if len(c.variables) > 0 {
+		c.variables[kicsLines] = withDefault(c.variableLines)
+		doc["variables"] = c.variables
+		lines["_kics_variables"] = model.LineObject{Line: firstLine(c.variableLines)}
+	}
+	if len(c.outputs) > 0 {
+		c.outputs[kicsLines] = withDefault(c.outputLines)
+		doc["outputs"] = c.outputs
+		lines["_kics_outputs"] = model.LineObject{Line: firstLine(c.outputLines)}
+	}
+	doc[kicsLines] = lines
+
+	return doc
+}
+
+func firstLine(lines map[string]model.LineObject) int {
+	first := 0
+	for _, line := range lines {
+		if first == 0 || (line.Line > 0 && line.Line < first) {
+			first = line.Line
+		}
+	}
+	return first
+}
+
+func withDefault(lines map[string]model.LineObject) map[string]model.LineObject {
+	lines["_kics__default"] = model.LineObject{Line: firstLine(lines)}
+	return lines
+}
@@ -0,0 +1,50 @@
+package bicep
+
+import (
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/pkg/errors"
+)
+
+// Parser - parser for Bicep files
+type Parser struct {
+}
+
+// Resolve - replace or modifies in-memory content before parsing
+func (p *Parser) Resolve(fileContent []byte, filename string) (*[]byte, error) {
+	return &fileContent, nil
+}
+
+// Parse - parses a Bicep file into the same document structure of an ARM template
+func (p *Parser) Parse(_ string, fileContent []byte) ([]model.Document, []int, error) {
+	doc, err := newSyntaxParser(string(fileContent)).parseFile()
+	if err != nil {
+		return nil, []int{}, errors.Wrap(err, "failed to parse Bicep file")
+	}
+
+	return []model.Document{doc}, []int{}, nil
+}
+
+// GetKind returns the kind of the parser
+func (p *Parser) GetKind() model.FileKind {
+	return model.KindBICEP
+}
+
+// SupportedExtensions returns Bicep extensions
+func (p *Parser) SupportedExtensions() []string {
+	return []string{".bicep"}
+}
+
+// SupportedTypes returns types supported by this parser, which are Bicep and AzureResourceManager
+func (p *Parser) SupportedTypes() []string {
+	return []string{"Bicep", "AzureResourceManager"}
+}
+
+// GetCommentToken return the comment token of Bicep - //
+func (p *Parser) GetCommentToken() string {
+	return "//"
+}
+
+// StringifyContent converts original content into string formated version
+func (p *Parser) StringifyContent(content []byte) (string, error) {
+	return string(content), nil
+}
@@ -0,0 +1,193 @@
+package bicep
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+var have = `targetScope = 'resourceGroup'
+
+@secure()
+param adminPassword string = 'P@ssw0rd'
+param location string = resourceGroup().location
+
+var tags = {
+  env: 'dev'
+}
+
+resource sa 'Microsoft.Storage/storageAccounts@2021-02-01' = {
+  name: 'storage'
+  location: location
+  properties: {
+    supportsHttpsTrafficOnly: false // comment
+    allowBlobPublicAccess: contains(tags, 'env') ? true : false
+  }
+  resource blob 'blobServices' = {
+    name: 'default'
+  }
+}
+
+resource vnet 'Microsoft.Network/virtualNetworks@2020-06-01' existing = {
+  name: 'vnet'
+  resource subnet 'subnets' = {
+    name: 'subnet'
+  }
+}
+
+module mod './module.bicep' = {
+  name: 'mod'
+}
+
+resource nsgs 'Microsoft.Network/networkSecurityGroups@2020-06-01' = [for i in range(0, 2): {
+  name: 'nsg${i}'
+}]
+
+output storageID string = sa.id
+`
+
+// TestParser_GetKind tests the functions [GetKind()] and all the methods called by them
+func TestParser_GetKind(t *testing.T) {
+	p := &Parser{}
+	require.Equal(t, model.KindBICEP, p.GetKind())
+}
+
+// TestParser_SupportedExtensions tests the functions [SupportedExtensions()] and all the methods called by them
+func TestParser_SupportedExtensions(t *testing.T) {
+	p := &Parser{}
+	require.Equal(t, []string{".bicep"}, p.SupportedExtensions())
+}
+
+// TestParser_SupportedTypes tests the functions [SupportedTypes()] and all the methods called by them
+func TestParser_SupportedTypes(t *testing.T) {
+	p := &Parser{}
+	require.Equal(t, []string{"Bicep", "AzureResourceManager"}, p.SupportedTypes())
+}
+
+// Test_GetCommentToken must get the token that represents a comment
+func Test_GetCommentToken(t *testing.T) {
+	p := &Parser{}
+	require.Equal(t, "//", p.GetCommentToken())
+}
+
+// TestParser_Parse tests the functions [Parse()] and all the methods called by them
+func TestParser_Parse(t *testing.T) {
+	p := &Parser{}
+
+	docs, _, err := p.Parse("main.bicep", []byte(have))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+
+	resources, ok := docs[0]["resources"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, resources, 3)
+
+	storage := resources[0].(map[string]interface{})
+	require.Equal(t, "Microsoft.Storage/storageAccounts", storage["type"])
+	require.Equal(t, "2021-02-01", storage["apiVersion"])
+	require.Equal(t, "[location]", storage["location"])
+
+	properties := storage["properties"].(map[string]interface{})
+	require.Equal(t, false, properties["supportsHttpsTrafficOnly"])
+	require.Equal(t, "[contains(tags, 'env') ? true : false]", properties["allowBlobPublicAccess"])
+
+	children := storage["resources"].([]interface{})
+	require.Len(t, children, 1)
+	require.Equal(t, "blobServices", children[0].(map[string]interface{})["type"])
+
+	subnet := resources[1].(map[string]interface{})
+	require.Equal(t, "Microsoft.Network/virtualNetworks/subnets", subnet["type"])
+
+	nsgs := resources[2].(map[string]interface{})
+	require.Equal(t, "nsg${i}", nsgs["name"])
+	require.Equal(t, "nsgs", nsgs["copy"].(map[string]interface{})["name"])
+
+	parameters := docs[0]["parameters"].(map[string]interface{})
+	require.Equal(t, "secureString", parameters["adminPassword"].(map[string]interface{})["type"])
+	require.Equal(t, "P@ssw0rd", parameters["adminPassword"].(map[string]interface{})["defaultValue"])
+	require.Equal(t, "[resourceGroup().location]", parameters["location"].(map[string]interface{})["defaultValue"])
+
+	require.Contains(t, docs[0]["variables"], "tags")
+	require.Contains(t, docs[0]["outputs"], "storageID")
+}
+
+// TestParser_LineInformation tests the line information placed in the parsed document
+func TestParser_LineInformation(t *testing.T) {
+	p := &Parser{}
+
+	docs, _, err := p.Parse("main.bicep", []byte(have))
+	require.NoError(t, err)
+
+	storage := docs[0]["resources"].([]interface{})[0].(map[string]interface{})
+	lines := storage["_kics_lines"].(map[string]model.LineObject)
+	require.Equal(t, 11, lines["_kics_type"].Line)
+	require.Equal(t, 12, lines["_kics_name"].Line)
+	require.Equal(t, 14, lines["_kics_properties"].Line)
+
+	propertiesLines := storage["properties"].(map[string]interface{})["_kics_lines"].(map[string]model.LineObject)
+	require.Equal(t, 15, propertiesLines["_kics_supportsHttpsTrafficOnly"].Line)
+}
+
+// TestParser_ParseInvalid tests that invalid Bicep files return an error
+func TestParser_ParseInvalid(t *testing.T) {
+	p := &Parser{}
+
+	_, _, err := p.Parse("main.bicep", []byte("resource sa 'Microsoft.Storage/storageAccounts@2021-02-01' = {\n  name: 'x'\n"))
+	require.Error(t, err)
+}
+
+// TestParser_ParseUnexpectedTokens tests that unexpected closing brackets return an error instead of looping forever
+func TestParser_ParseUnexpectedTokens(t *testing.T) {
+	for _, content := range []string{"import foo }", "targetScope ]", "var a = )", "metadata x = 'a' }"} {
+		t.Run(content, func(t *testing.T) {
+			done := make(chan error, 1)
+			go func() {
+				_, _, err := (&Parser{}).Parse("main.bicep", []byte(content))
+				done <- err
+			}()
+			select {
+			case err := <-done:
+				require.Error(t, err)
+			case <-time.After(5 * time.Second):
+				t.Fatal("the parser did not return")
+			}
+		})
+	}
+}
+
+// TestParser_ParseImports tests that the symbols, namespaces and providers imported are skipped
+func TestParser_ParseImports(t *testing.T) {
+	content := `import {a, b} from 'shared.bicep'
+import {
+  c as d
+  e
+} from 'types.bicep'
+import * as ns from 'ns.bicep'
+import 'az@1.0.0'
+
+resource sa 'Microsoft.Storage/storageAccounts@2021-02-01' = {
+  name: a
+}
+`
+	docs, _, err := (&Parser{}).Parse("main.bicep", []byte(content))
+	require.NoError(t, err)
+	resources := docs[0]["resources"].([]interface{})
+	require.Len(t, resources, 1)
+	require.Equal(t, "[a]", resources[0].(map[string]interface{})["name"])
+	lines := resources[0].(map[string]interface{})["_kics_lines"].(map[string]model.LineObject)
+	require.Equal(t, 9, lines["_kics_type"].Line)
+
+	_, _, err = (&Parser{}).Parse("main.bicep", []byte("import {a, b from 'shared.bicep'\n"))
+	require.Error(t, err)
+}
+
+// Test_Resolve tests the functions [Resolve()] and all the methods called by them
+func Test_Resolve(t *testing.T) {
+	p := &Parser{}
+
+	resolved, err := p.Resolve([]byte(have), "main.bicep")
+	require.NoError(t, err)
+	require.Equal(t, []byte(have), *resolved)
+}
@@ -0,0 +1,768 @@
+package bicep
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Checkmarx/kics/pkg/model"
+)
+
+const kicsLines = "_kics_lines"
+
+// decorator is a Bicep decorator (@name(args)) placed before a declaration
+type decorator struct {
+	name string
+	args []interface{}
+}
+
+// resourceDecl is a Bicep resource declaration converted to its ARM representation
+// value holds the resource body and line is the line where the declaration starts
+type resourceDecl struct {
+	symbol   string
+	existing bool
+	value    map[string]interface{}
+	line     int
+}
+
+// syntaxParser is a small recursive descent parser for the Bicep language
+// src is the file content, pos is the current offset and line the current line (1-based)
+type syntaxParser struct {
+	src  string
+	pos  int
+	line int
+}
+
+func newSyntaxParser(content string) *syntaxParser {
+	return &syntaxParser{
+		src:  strings.ReplaceAll(content, "\r\n", "\n"),
+		pos:  0,
+		line: 1,
+	}
+}
+
+func (p *syntaxParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *syntaxParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *syntaxParser) hasPrefix(prefix string) bool {
+	return strings.HasPrefix(p.src[p.pos:], prefix)
+}
+
+// hasKeyword checks if the next token is the given keyword and not part of a longer identifier
+func (p *syntaxParser) hasKeyword(keyword string) bool {
+	if !p.hasPrefix(keyword) {
+		return false
+	}
+	next := p.pos + len(keyword)
+	return next >= len(p.src) || !isIdentChar(p.src[next])
+}
+
+func (p *syntaxParser) advance(n int) {
+	for i := 0; i < n && !p.eof(); i++ {
+		if p.src[p.pos] == '\n' {
+			p.line++
+		}
+		p.pos++
+	}
+}
+
+func (p *syntaxParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+// skipSpaces skips blanks and comments, newlines are only skipped when withNewLines is set
+func (p *syntaxParser) skipSpaces(withNewLines bool) {
+	for !p.eof() {
+		switch c := p.peek(); {
+		case c == ' ' || c == '\t' || c == '\r':
+			p.advance(1)
+		case c == '\n' && withNewLines:
+			p.advance(1)
+		case p.hasPrefix("//"):
+			for !p.eof() && p.peek() != '\n' {
+				p.advance(1)
+			}
+		case p.hasPrefix("/*"):
+			end := strings.Index(p.src[p.pos+2:], "*/")
+			if end < 0 {
+				p.advance(len(p.src))
+				return
+			}
+			p.advance(end + 4) //nolint:gomnd
+		default:
+			return
+		}
+	}
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func (p *syntaxParser) readIdentifier() string {
+	start := p.pos
+	for !p.eof() && isIdentChar(p.peek()) {
+		p.advance(1)
+	}
+	return p.src[start:p.pos]
+}
+
+// atValueEnd checks if the parser is at the end of a value (new line, comma, closing bracket or EOF)
+func (p *syntaxParser) atValueEnd() bool {
+	save, saveLine := p.pos, p.line
+	p.skipSpaces(false)
+	c := p.peek()
+	end := p.eof() || c == '\n' || c == ',' || c == '}' || c == ']' || c == ')' || c == ':'
+	p.pos, p.line = save, saveLine
+	return end
+}
+
+// parseFile parses the whole Bicep file and returns the ARM-like document
+func (p *syntaxParser) parseFile() (model.Document, error) {
+	conv := newConverter()
+	decorators := make([]decorator, 0)
+
+	for {
+		p.skipSpaces(true)
+		if p.eof() {
+			break
+		}
+		if p.peek() == '@' {
+			dec, err := p.parseDecorator()
+			if err != nil {
+				return nil, err
+			}
+			decorators = append(decorators, dec)
+			continue
+		}
+
+		line := p.line
+		keyword := p.readIdentifier()
+		if keyword == "" {
+			return nil, p.errorf("unexpected character '%c'", p.peek())
+		}
+		if err := p.parseDeclaration(conv, keyword, line, decorators); err != nil {
+			return nil, err
+		}
+		decorators = make([]decorator, 0)
+	}
+
+	return conv.document(), nil
+}
+
+func (p *syntaxParser) parseDeclaration(conv *converter, keyword string, line int, decorators []decorator) error {
+	switch keyword {
+	case "param":
+		name, paramType, value, hasValue, err := p.parseTypedDeclaration()
+		if err != nil {
+			return err
+		}
+		conv.addParameter(name, paramType, value, hasValue, decorators, line)
+	case "var":
+		p.skipSpaces(false)
+		name := p.readIdentifier()
+		if err := p.expect('='); err != nil {
+			return err
+		}
+		value, arrLines, err := p.parseValue()
+		if err != nil {
+			return err
+		}
+		conv.addVariable(name, value, arrLines, line)
+	case "output":
+		name, outputType, value, _, err := p.parseTypedDeclaration()
+		if err != nil {
+			return err
+		}
+		conv.addOutput(name, outputType, value, line)
+	case "resource":
+		res, err := p.parseResource(line)
+		if err != nil {
+			return err
+		}
+		conv.addResource(res)
+	case "import":
+		return p.skipImport()
+	default:
+		// targetScope, module, metadata, import and type declarations are not relevant for queries
+		return p.skipDeclaration()
+	}
+	return nil
+}
+
+// skipDeclaration consumes a declaration until the end of its value
+func (p *syntaxParser) skipDeclaration() error {
+	for {
+		p.skipSpaces(false)
+		if p.eof() || p.peek() == '\n' {
+			return nil
+		}
+		if p.peek() == '=' {
+			p.advance(1)
+			_, _, err := p.parseValue()
+			return err
+		}
+		if _, _, err := p.parseValue(); err != nil {
+			return err
+		}
+	}
+}
+
+// skipImport consumes an import declaration, which can import symbols ("import {a, b as c} from '<file>'"),
+// a namespace ("import * as ns from '<file>'") or a provider ("import '<provider>@<version>' with {...} as <alias>")
+func (p *syntaxParser) skipImport() error {
+	for {
+		p.skipSpaces(false)
+		switch c := p.peek(); {
+		case p.eof() || c == '\n':
+			return nil
+		case c == '{':
+			// the imported symbols have no nested braces
+			end := strings.IndexByte(p.src[p.pos:], '}')
+			if end < 0 {
+				return p.errorf("unterminated import")
+			}
+			p.advance(end + 1)
+		case c == '\'':
+			if _, err := p.parseString(); err != nil {
+				return err
+			}
+		case isIdentChar(c):
+			p.readIdentifier()
+		case c == '*' || c == ',':
+			p.advance(1)
+		default:
+			return p.errorf("unexpected character '%c' in import", c)
+		}
+	}
+}
+
+func (p *syntaxParser) expect(c byte) error {
+	p.skipSpaces(false)
+	if p.peek() != c {
+		return p.errorf("expected '%c'", c)
+	}
+	p.advance(1)
+	return nil
+}
+
+// parseTypedDeclaration parses declarations with the form '<name> <type> [= <value>]'
+func (p *syntaxParser) parseTypedDeclaration() (name, declType string, value interface{}, hasValue bool, err error) {
+	p.skipSpaces(false)
+	name = p.readIdentifier()
+	p.skipSpaces(false)
+	typeStart := p.pos
+	for !p.eof() && p.peek() != '=' && p.peek() != '\n' && !p.hasPrefix("//") {
+		p.advance(1)
+	}
+	declType = strings.TrimSpace(p.src[typeStart:p.pos])
+	if p.peek() != '=' {
+		return name, declType, nil, false, nil
+	}
+	p.advance(1)
+	value, _, err = p.parseValue()
+	return name, declType, value, true, err
+}
+
+func (p *syntaxParser) parseDecorator() (decorator, error) {
+	p.advance(1)
+	dec := decorator{
+		name: p.readIdentifier(),
+		args: make([]interface{}, 0),
+	}
+	// namespaced decorators such as @sys.description(...)
+	for p.peek() == '.' {
+		p.advance(1)
+		dec.name = p.readIdentifier()
+	}
+	if p.peek() != '(' {
+		return dec, nil
+	}
+	p.advance(1)
+	for {
+		p.skipSpaces(true)
+		if p.eof() {
+			return dec, p.errorf("unterminated decorator '%s'", dec.name)
+		}
+		if p.peek() == ')' {
+			p.advance(1)
+			return dec, nil
+		}
+		if p.peek() == ',' {
+			p.advance(1)
+			continue
+		}
+		arg, _, err := p.parseValue()
+		if err != nil {
+			return dec, err
+		}
+		dec.args = append(dec.args, arg)
+	}
+}
+
+// parseResource parses "<symbol> '<type>@<apiVersion>' [existing] = [if (<cond>)] <body>"
+func (p *syntaxParser) parseResource(line int) (*resourceDecl, error) {
+	p.skipSpaces(false)
+	res := &resourceDecl{
+		symbol: p.readIdentifier(),
+		line:   line,
+	}
+	p.skipSpaces(false)
+	if p.peek() != '\'' {
+		return nil, p.errorf("expected resource type for '%s'", res.symbol)
+	}
+	typeValue, err := p.parseString()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces(false)
+	if p.hasKeyword("existing") {
+		p.readIdentifier()
+		res.existing = true
+	}
+	if err = p.expect('='); err != nil {
+		return nil, err
+	}
+
+	condition := ""
+	p.skipSpaces(false)
+	if p.hasKeyword("if") {
+		p.advance(2) //nolint:gomnd
+		p.skipSpaces(false)
+		condition = p.readBalanced('(', ')')
+		p.skipSpaces(true)
+	}
+
+	var body interface{}
+	loop := ""
+	if p.peek() == '[' {
+		body, loop, err = p.parseForExpression()
+	} else {
+		body, _, err = p.parseObject()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := body.(map[string]interface{})
+	if !ok {
+		return nil, p.errorf("invalid body for resource '%s'", res.symbol)
+	}
+
+	resourceType, apiVersion := splitResourceType(typeValue.(string))
+	value["type"] = resourceType
+	if apiVersion != "" {
+		value["apiVersion"] = apiVersion
+	}
+	lines := value[kicsLines].(map[string]model.LineObject)
+	lines["_kics__default"] = model.LineObject{Line: line}
+	lines["_kics_type"] = model.LineObject{Line: line}
+	lines["_kics_apiVersion"] = model.LineObject{Line: line}
+	if condition != "" {
+		value["condition"] = "[" + condition + "]"
+		lines["_kics_condition"] = model.LineObject{Line: line}
+	}
+	if loop != "" {
+		value["copy"] = map[string]interface{}{
+			"name":  res.symbol,
+			"count": "[length(" + loop + ")]",
+		}
+		lines["_kics_copy"] = model.LineObject{Line: line}
+	}
+	res.value = value
+	return res, nil
+}
+
+// parseForExpression parses "[for <item> in <collection>: <body>]" returning the body and the collection
+func (p *syntaxParser) parseForExpression() (body interface{}, collection string, err error) {
+	p.advance(1)
+	p.skipSpaces(true)
+	if !p.hasKeyword("for") {
+		return nil, "", p.errorf("expected for expression")
+	}
+	p.advance(3) //nolint:gomnd
+	header := p.readUntilTopLevel(':')
+	if idx := strings.Index(header, " in "); idx >= 0 {
+		collection = strings.TrimSpace(header[idx+4:])
+	}
+	p.advance(1)
+	p.skipSpaces(true)
+	if p.hasKeyword("if") {
+		p.advance(2) //nolint:gomnd
+		p.skipSpaces(false)
+		p.readBalanced('(', ')')
+		p.skipSpaces(true)
+	}
+	body, _, err = p.parseValue()
+	if err != nil {
+		return nil, "", err
+	}
+	p.skipSpaces(true)
+	if p.peek() != ']' {
+		return nil, "", p.errorf("unterminated for expression")
+	}
+	p.advance(1)
+	return body, collection, nil
+}
+
+// splitResourceType splits 'Microsoft.Storage/storageAccounts@2021-02-01' into type and api version
+func splitResourceType(value string) (resourceType, apiVersion string) {
+	if idx := strings.LastIndex(value, "@"); idx >= 0 {
+		return value[:idx], value[idx+1:]
+	}
+	return value, ""
+}
+
+// parseValue parses any Bicep value returning it and, for arrays, the line information of its elements
+func (p *syntaxParser) parseValue() (value interface{}, arrLines []map[string]model.LineObject, err error) {
+	p.skipSpaces(true)
+	if p.eof() {
+		return nil, nil, p.errorf("unexpected end of file")
+	}
+	startPos, startLine := p.pos, p.line
+
+	switch c := p.peek(); {
+	case c == '{':
+		value, _, err = p.parseObject()
+	case c == '[':
+		value, arrLines, err = p.parseArray()
+	case c == '\'':
+		value, err = p.parseString()
+	case c == '-' || (c >= '0' && c <= '9'):
+		if value, err = p.parseNumber(); err != nil {
+			p.pos, p.line = startPos, startLine
+			value, err = p.parseExpression()
+			return value, nil, err
+		}
+	default:
+		ident := p.readIdentifier()
+		switch ident {
+		case "true":
+			value = true
+		case "false":
+			value = false
+		case "null":
+			value = nil
+		default:
+			p.pos, p.line = startPos, startLine
+			value, err = p.parseExpression()
+			return value, nil, err
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the literal is only part of a bigger expression (e.g. 'a' == b ? 1 : 2)
+	if !p.atValueEnd() {
+		p.pos, p.line = startPos, startLine
+		value, err = p.parseExpression()
+		return value, nil, err
+	}
+	return value, arrLines, nil
+}
+
+// parseObject parses an object body, nested resource declarations are placed in its 'resources' key
+func (p *syntaxParser) parseObject() (interface{}, []map[string]model.LineObject, error) {
+	if err := p.expect('{'); err != nil {
+		return nil, nil, err
+	}
+	obj := make(map[string]interface{})
+	lines := map[string]model.LineObject{
+		"_kics__default": {Line: p.line},
+	}
+	resources := make([]interface{}, 0)
+	resourcesLines := make([]map[string]model.LineObject, 0)
+
+	for {
+		p.skipSpaces(true)
+		if p.eof() {
+			return nil, nil, p.errorf("unterminated object")
+		}
+		if p.peek() == '}' {
+			p.advance(1)
+			break
+		}
+		if p.peek() == ',' {
+			p.advance(1)
+			continue
+		}
+		if p.peek() == '@' {
+			if _, err := p.parseDecorator(); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+
+		keyLine := p.line
+		key, err := p.parseKey()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		p.skipSpaces(false)
+		if key == "resource" && p.peek() != ':' {
+			res, errRes := p.parseResource(keyLine)
+			if errRes != nil {
+				return nil, nil, errRes
+			}
+			resources = append(resources, res.value)
+			resourcesLines = append(resourcesLines, res.value[kicsLines].(map[string]model.LineObject))
+			continue
+		}
+
+		if err = p.expect(':'); err != nil {
+			return nil, nil, err
+		}
+		value, arrLines, err := p.parseValue()
+		if err != nil {
+			return nil, nil, err
+		}
+		obj[key] = value
+		lines["_kics_"+key] = model.LineObject{
+			Line: keyLine,
+			Arr:  arrLines,
+		}
+	}
+
+	if len(resources) > 0 {
+		obj["resources"] = resources
+		lines["_kics_resources"] = model.LineObject{
+			Line: resourcesLines[0]["_kics__default"].Line,
+			Arr:  resourcesLines,
+		}
+	}
+	obj[kicsLines] = lines
+	return obj, nil, nil
+}
+
+func (p *syntaxParser) parseKey() (string, error) {
+	if p.peek() == '\'' {
+		key, err := p.parseString()
+		if err != nil {
+			return "", err
+		}
+		return key.(string), nil
+	}
+	key := p.readIdentifier()
+	if key == "" {
+		return "", p.errorf("expected object key")
+	}
+	return key, nil
+}
+
+func (p *syntaxParser) parseArray() (interface{}, []map[string]model.LineObject, error) {
+	save, saveLine := p.pos, p.line
+	p.advance(1)
+	p.skipSpaces(true)
+	if p.hasKeyword("for") {
+		p.pos, p.line = save, saveLine
+		value, err := p.parseExpression()
+		return value, nil, err
+	}
+
+	arr := make([]interface{}, 0)
+	arrLines := make([]map[string]model.LineObject, 0)
+	for {
+		p.skipSpaces(true)
+		if p.eof() {
+			return nil, nil, p.errorf("unterminated array")
+		}
+		if p.peek() == ']' {
+			p.advance(1)
+			return arr, arrLines, nil
+		}
+		if p.peek() == ',' {
+			p.advance(1)
+			continue
+		}
+		line := p.line
+		value, _, err := p.parseValue()
+		if err != nil {
+			return nil, nil, err
+		}
+		arr = append(arr, value)
+		if obj, ok := value.(map[string]interface{}); ok {
+			arrLines = append(arrLines, obj[kicsLines].(map[string]model.LineObject))
+		} else {
+			arrLines = append(arrLines, map[string]model.LineObject{"_kics__default": {Line: line}})
+		}
+	}
+}
+
+// parseString parses single quoted strings, multi-line strings (”'...”') and keeps interpolations as they are
+func (p *syntaxParser) parseString() (interface{}, error) {
+	if p.hasPrefix("'''") {
+		p.advance(3) //nolint:gomnd
+		end := strings.Index(p.src[p.pos:], "'''")
+		if end < 0 {
+			return nil, p.errorf("unterminated multi-line string")
+		}
+		value := strings.TrimPrefix(p.src[p.pos:p.pos+end], "\n")
+		p.advance(end + 3) //nolint:gomnd
+		return value, nil
+	}
+
+	p.advance(1)
+	var sb strings.Builder
+	depth := 0
+	for !p.eof() {
+		c := p.peek()
+		switch {
+		case c == '\\' && p.pos+1 < len(p.src):
+			sb.WriteString(unescape(p.src[p.pos+1]))
+			p.advance(2) //nolint:gomnd
+			continue
+		case p.hasPrefix("${"):
+			depth++
+			sb.WriteString("${")
+			p.advance(2) //nolint:gomnd
+			continue
+		case c == '}' && depth > 0:
+			depth--
+		case c == '\'' && depth == 0:
+			p.advance(1)
+			return sb.String(), nil
+		case c == '\n':
+			return nil, p.errorf("unterminated string")
+		}
+		sb.WriteByte(c)
+		p.advance(1)
+	}
+	return nil, p.errorf("unterminated string")
+}
+
+func unescape(c byte) string {
+	switch c {
+	case 'n':
+		return "\n"
+	case 'r':
+		return "\r"
+	case 't':
+		return "\t"
+	default:
+		return string(c)
+	}
+}
+
+func (p *syntaxParser) parseNumber() (interface{}, error) {
+	start := p.pos
+	if p.peek() == '-' {
+		p.advance(1)
+	}
+	for !p.eof() && ((p.peek() >= '0' && p.peek() <= '9') || p.peek() == '.') {
+		p.advance(1)
+	}
+	number, err := strconv.ParseFloat(p.src[start:p.pos], 64) //nolint:gomnd
+	if err != nil {
+		return nil, p.errorf("invalid number '%s'", p.src[start:p.pos])
+	}
+	if number == float64(int(number)) {
+		return int(number), nil
+	}
+	return number, nil
+}
+
+// parseExpression reads a raw Bicep expression and represents it as an ARM template expression,
+// an expression always consumes at least one character, otherwise the character is unexpected
+func (p *syntaxParser) parseExpression() (interface{}, error) {
+	start := p.pos
+	expression := func() (interface{}, error) {
+		if p.pos == start {
+			if p.eof() {
+				return nil, p.errorf("unexpected end of file")
+			}
+			return nil, p.errorf("unexpected character '%c'", p.peek())
+		}
+		return "[" + strings.TrimSpace(p.src[start:p.pos]) + "]", nil
+	}
+	depth := 0
+	inString := false
+	for !p.eof() {
+		c := p.peek()
+		if inString {
+			if c == '\\' {
+				p.advance(2) //nolint:gomnd
+				continue
+			}
+			if c == '\'' {
+				inString = false
+			}
+			p.advance(1)
+			continue
+		}
+		switch c {
+		case '\'':
+			inString = true
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			if depth == 0 {
+				return expression()
+			}
+			depth--
+		case '\n', ',':
+			if depth == 0 {
+				return expression()
+			}
+		}
+		if depth == 0 && p.hasPrefix("//") {
+			break
+		}
+		p.advance(1)
+	}
+	return expression()
+}
+
+// readBalanced reads the content between the open and close characters, including nested pairs
+func (p *syntaxParser) readBalanced(open, closing byte) string {
+	if p.peek() != open {
+		return ""
+	}
+	p.advance(1)
+	start := p.pos
+	depth := 1
+	for !p.eof() {
+		switch p.peek() {
+		case open:
+			depth++
+		case closing:
+			depth--
+			if depth == 0 {
+				content := p.src[start:p.pos]
+				p.advance(1)
+				return strings.TrimSpace(content)
+			}
+		}
+		p.advance(1)
+	}
+	return strings.TrimSpace(p.src[start:])
+}
+
+// readUntilTopLevel reads until the given character is found outside of brackets
+func (p *syntaxParser) readUntilTopLevel(stop byte) string {
+	start := p.pos
+	depth := 0
+	for !p.eof() {
+		c := p.peek()
+		if c == stop && depth == 0 {
+			break
+		}
+		switch c {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		}
+		p.advance(1)
+	}
+	return p.src[start:p.pos]
+}
@@ -14,9 +14,16 @@ import (
 type Parser struct {
 }
 
+// BuildArgsParser is a Dockerfile parser, BuildArgs overrides the default values of the build args (ARG)
+type BuildArgsParser struct {
+	Parser
+	BuildArgs map[string]string
+}
+
 // Resource Separates the list of commands by file
 type Resource struct {
 	CommandList map[string][]Command `json:"command"`
+	Stages      map[string]Stage     `json:"stages"`
 }
 
 // Command is the struct for each dockerfile command
@@ -38,6 +45,15 @@ func (p *Parser) Resolve(fileContent []byte, filename string) (*[]byte, error) {
 
 // Parse - parses dockerfile to Json
 func (p *Parser) Parse(_ string, fileContent []byte) ([]model.Document, []int, error) {
+	return parse(fileContent, nil)
+}
+
+// Parse parses the Dockerfile to JSON, the build args are resolved with the values of BuildArgs
+func (p *BuildArgsParser) Parse(_ string, fileContent []byte) ([]model.Document, []int, error) {
+	return parse(fileContent, p.BuildArgs)
+}
+
+func parse(fileContent []byte, buildArgs map[string]string) ([]model.Document, []int, error) {
 	var documents []model.Document
 	reader := bytes.NewReader(fileContent)
 
@@ -46,9 +62,10 @@ func (p *Parser) Parse(_ string, fileContent []byte) ([]model.Document, []int, e
 		return nil, []int{}, errors.Wrap(err, "failed to parse Dockerfile")
 	}
 
-	fromValue := "args"
+	fromValue := argsKey
 	from := make(map[string][]Command)
 	ignoreStruct := newIgnore()
+	graph := newStageGraph(buildArgs)
 
 	for _, child := range parsed.AST.Children {
 		child.Value = strings.ToLower(child.Value)
@@ -80,12 +97,14 @@ func (p *Parser) Parse(_ string, fileContent []byte) ([]model.Document, []int, e
 			cmd.Value = append(cmd.Value, n.Value)
 		}
 
+		graph.add(fromValue, &cmd)
 		from[fromValue] = append(from[fromValue], cmd)
 	}
 
 	doc := &model.Document{}
 	var resource Resource
 	resource.CommandList = from
+	resource.Stages = graph.getStages()
 
 	j, err := json.Marshal(resource)
 	if err != nil {
@@ -0,0 +1,302 @@
+package docker
+
+import (
+	"strconv"
+	"strings"
+)
+
+// argsKey is the key of the commands declared before the first 'FROM' instruction
+const argsKey = "args"
+
+// substitutedCommands are the instructions whose values Docker substitutes with the build args and the environment
+var substitutedCommands = map[string]bool{
+	"add":        true,
+	"arg":        true,
+	"copy":       true,
+	"env":        true,
+	"expose":     true,
+	"from":       true,
+	"label":      true,
+	"stopsignal": true,
+	"user":       true,
+	"volume":     true,
+	"workdir":    true,
+}
+
+// Stage is a build stage of the Dockerfile, its commands are the ones of the command list with the same key.
+// BaseStage and CopyFrom are the keys of the stages it is built from and copies files from, User is the user
+// set by the last 'USER' instruction (inherited from the base stage) and Final marks the stage that is shipped
+type Stage struct {
+	Index     int
+	Alias     string
+	Image     string
+	BaseStage string
+	CopyFrom  []string
+	User      string
+	Final     bool
+	StartLine int `json:"_kics_line"`
+}
+
+// stageGraph resolves the build args and the environment of each stage and links the stages
+// built from or copying files from other stages
+type stageGraph struct {
+	buildArgs  map[string]string
+	globalArgs map[string]string
+	stages     map[string]*Stage
+	order      []string
+	aliases    map[string]string
+	envs       map[string]map[string]string
+	current    string
+	vars       map[string]string
+}
+
+func newStageGraph(buildArgs map[string]string) *stageGraph {
+	return &stageGraph{
+		buildArgs:  buildArgs,
+		globalArgs: make(map[string]string),
+		stages:     make(map[string]*Stage),
+		order:      make([]string, 0),
+		aliases:    make(map[string]string),
+		envs:       make(map[string]map[string]string),
+		current:    argsKey,
+		vars:       make(map[string]string),
+	}
+}
+
+// add updates the graph with the command of the stage with the given key and substitutes its values
+func (g *stageGraph) add(key string, cmd *Command) {
+	if cmd.Cmd == "from" {
+		g.addStage(key, cmd)
+		return
+	}
+	if cmd.Cmd == "onbuild" || !substitutedCommands[cmd.Cmd] {
+		return
+	}
+
+	if g.current == argsKey {
+		if cmd.Cmd == "arg" {
+			g.declareArgs(cmd, g.globalArgs, g.globalArgs)
+		}
+		return
+	}
+
+	switch cmd.Cmd {
+	case "arg":
+		g.declareArgs(cmd, g.vars, g.globalArgs)
+	case "env":
+		// the values of the instruction are substituted with the environment prior to the instruction
+		for idx := 1; idx < len(cmd.Value); idx += 2 {
+			cmd.Value[idx] = expand(cmd.Value[idx], g.vars)
+		}
+		for idx := 0; idx+1 < len(cmd.Value); idx += 2 {
+			g.vars[cmd.Value[idx]] = unquote(cmd.Value[idx+1])
+			g.envs[g.current][cmd.Value[idx]] = unquote(cmd.Value[idx+1])
+		}
+	case "label":
+		for idx := 1; idx < len(cmd.Value); idx += 2 {
+			cmd.Value[idx] = expand(cmd.Value[idx], g.vars)
+		}
+	default:
+		for idx := range cmd.Value {
+			cmd.Value[idx] = expand(cmd.Value[idx], g.vars)
+		}
+	}
+
+	if cmd.Cmd == "user" && len(cmd.Value) > 0 {
+		g.stages[g.current].User = cmd.Value[0]
+	}
+	if cmd.Cmd == "copy" {
+		g.addCopyFrom(cmd)
+	}
+}
+
+// addStage starts a new stage, the base image is substituted with the build args declared before the first 'FROM'
+func (g *stageGraph) addStage(key string, cmd *Command) {
+	for idx := range cmd.Value {
+		cmd.Value[idx] = expand(cmd.Value[idx], g.globalArgs)
+	}
+
+	stage := &Stage{
+		Index:     len(g.order),
+		StartLine: cmd.StartLine,
+	}
+	if len(cmd.Value) > 0 {
+		stage.Image = cmd.Value[0]
+	}
+	if len(cmd.Value) > 2 && strings.EqualFold(cmd.Value[1], "as") {
+		stage.Alias = cmd.Value[2]
+	}
+
+	env := make(map[string]string)
+	if base, ok := g.aliases[strings.ToLower(stage.Image)]; ok {
+		stage.BaseStage = base
+		stage.User = g.stages[base].User
+		for name, value := range g.envs[base] {
+			env[name] = value
+		}
+	}
+
+	g.vars = make(map[string]string, len(env))
+	for name, value := range env {
+		g.vars[name] = value
+	}
+	g.envs[key] = env
+	g.stages[key] = stage
+	g.order = append(g.order, key)
+	if stage.Alias != "" {
+		g.aliases[strings.ToLower(stage.Alias)] = key
+	}
+	g.current = key
+}
+
+// declareArgs sets the values of the declared build args, the value given to the build is used when present,
+// otherwise the default value of the declaration or the value declared before the first 'FROM'
+func (g *stageGraph) declareArgs(cmd *Command, vars, inherited map[string]string) {
+	for idx, declaration := range cmd.Value {
+		parts := strings.SplitN(declaration, "=", 2)
+		name := parts[0]
+		if len(parts) == 2 {
+			parts[1] = expand(parts[1], vars)
+			cmd.Value[idx] = name + "=" + parts[1]
+		}
+
+		if value, ok := g.buildArgs[name]; ok {
+			vars[name] = value
+		} else if len(parts) == 2 {
+			vars[name] = unquote(parts[1])
+		} else if value, ok := inherited[name]; ok {
+			vars[name] = value
+		}
+	}
+}
+
+// addCopyFrom links the stage to the stage referenced by the '--from' flag, by alias or by index
+func (g *stageGraph) addCopyFrom(cmd *Command) {
+	for idx, flag := range cmd.Flags {
+		if !strings.HasPrefix(flag, "--from=") {
+			continue
+		}
+		from := expand(strings.TrimPrefix(flag, "--from="), g.vars)
+		cmd.Flags[idx] = "--from=" + from
+
+		key, ok := g.aliases[strings.ToLower(from)]
+		if index, err := strconv.Atoi(from); !ok && err == nil && index >= 0 && index < len(g.order) {
+			key, ok = g.order[index], true
+		}
+		if ok {
+			g.stages[g.current].CopyFrom = append(g.stages[g.current].CopyFrom, key)
+		}
+	}
+}
+
+// getStages returns the stages by key, the last one is the final stage, the one that is shipped
+func (g *stageGraph) getStages() map[string]Stage {
+	stages := make(map[string]Stage, len(g.stages))
+	for _, key := range g.order {
+		stages[key] = *g.stages[key]
+	}
+	if len(g.order) > 0 {
+		final := stages[g.order[len(g.order)-1]]
+		final.Final = true
+		stages[g.order[len(g.order)-1]] = final
+	}
+	return stages
+}
+
+// expand substitutes the variables referenced in the word ($NAME, ${NAME}, ${NAME:-word}, ${NAME:+word}),
+// the references to unknown variables are kept so the original value is still checked
+func expand(word string, vars map[string]string) string {
+	if !strings.Contains(word, "$") {
+		return word
+	}
+
+	var sb strings.Builder
+	for idx := 0; idx < len(word); idx++ {
+		char := word[idx]
+		switch {
+		case char == '\\' && idx+1 < len(word) && word[idx+1] == '$':
+			sb.WriteString(word[idx : idx+2])
+			idx++
+		case char == '$' && idx+1 < len(word) && word[idx+1] == '{':
+			end := closingBrace(word, idx+2)
+			if end < 0 {
+				sb.WriteString(word[idx:])
+				return sb.String()
+			}
+			sb.WriteString(expandBraces(word[idx:end+1], word[idx+2:end], vars))
+			idx = end
+		case char == '$':
+			end := idx + 1
+			for end < len(word) && isNameChar(word[end]) {
+				end++
+			}
+			if value, ok := vars[word[idx+1:end]]; ok && end > idx+1 {
+				sb.WriteString(value)
+			} else {
+				sb.WriteString(word[idx:end])
+			}
+			idx = end - 1
+		default:
+			sb.WriteByte(char)
+		}
+	}
+	return sb.String()
+}
+
+// expandBraces substitutes the '${...}' reference, the original reference is returned for unknown variables
+func expandBraces(original, reference string, vars map[string]string) string {
+	name, modifier, word := reference, "", ""
+	if idx := strings.Index(reference, ":"); idx > 0 && idx+1 < len(reference) {
+		name, modifier, word = reference[:idx], reference[idx+1:idx+2], reference[idx+2:]
+	}
+
+	value, ok := vars[name]
+	switch modifier {
+	case "-":
+		if ok && value != "" {
+			return value
+		}
+		return expand(word, vars)
+	case "+":
+		if !ok {
+			return original
+		}
+		if value != "" {
+			return expand(word, vars)
+		}
+		return ""
+	case "":
+		if ok {
+			return value
+		}
+	}
+	return original
+}
+
+func closingBrace(word string, start int) int {
+	depth := 1
+	for idx := start; idx < len(word); idx++ {
+		switch word[idx] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return idx
+			}
+		}
+	}
+	return -1
+}
+
+func isNameChar(char byte) bool {
+	return char == '_' || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || (char >= '0' && char <= '9')
+}
+
+// unquote removes the quotes surrounding the value of a build arg or an environment variable
+func unquote(value string) string {
+	if len(value) > 1 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
@@ -0,0 +1,90 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test_expand tests the functions [expand()] and all the methods called by them
+func Test_expand(t *testing.T) {
+	vars := map[string]string{
+		"IMAGE": "alpine",
+		"TAG":   "3.14",
+		"EMPTY": "",
+	}
+	tests := []struct {
+		word string
+		want string
+	}{
+		{word: "alpine:3.14", want: "alpine:3.14"},
+		{word: "$IMAGE:$TAG", want: "alpine:3.14"},
+		{word: "${IMAGE}:${TAG}", want: "alpine:3.14"},
+		{word: "${UNKNOWN}/$UNKNOWN", want: "${UNKNOWN}/$UNKNOWN"},
+		{word: "${EMPTY:-debian}", want: "debian"},
+		{word: "${UNKNOWN:-$IMAGE}", want: "alpine"},
+		{word: "${TAG:+latest}", want: "latest"},
+		{word: "${EMPTY:+latest}", want: ""},
+		{word: "${UNKNOWN:+latest}", want: "${UNKNOWN:+latest}"},
+		{word: "\\$IMAGE", want: "\\$IMAGE"},
+		{word: "price: 5$", want: "price: 5$"},
+		{word: "${IMAGE", want: "${IMAGE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			require.Equal(t, tt.want, expand(tt.word, vars))
+		})
+	}
+}
+
+// Test_stageGraph tests the functions [getStages()] and all the methods called by them
+func Test_stageGraph(t *testing.T) {
+	sample := `
+ARG BASE_IMAGE=golang:1.16
+ARG APP_USER=root
+FROM ${BASE_IMAGE} AS builder
+ENV GOPATH=/go
+ENV APP_DIR=$GOPATH/app DATA_DIR=$APP_DIR/data
+WORKDIR $APP_DIR
+USER builder
+
+FROM builder AS tests
+RUN go test ./...
+
+FROM alpine:3.14
+ARG APP_USER
+COPY --from=builder /go/app /app
+COPY --from=1 /reports /reports
+COPY --from=nginx:latest /etc/nginx/nginx.conf /nginx.conf
+USER $APP_USER
+`
+	p := &BuildArgsParser{BuildArgs: map[string]string{"BASE_IMAGE": "golang:1.17"}}
+	docs, _, err := p.Parse("Dockerfile", []byte(sample))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+
+	stages := docs[0]["stages"].(map[string]interface{})
+	require.Len(t, stages, 3)
+
+	builder := stages["${BASE_IMAGE} AS builder"].(map[string]interface{})
+	require.Equal(t, "golang:1.17", builder["Image"])
+	require.Equal(t, "builder", builder["Alias"])
+	require.Equal(t, "builder", builder["User"])
+	require.Equal(t, false, builder["Final"])
+
+	tests := stages["builder AS tests"].(map[string]interface{})
+	require.Equal(t, "${BASE_IMAGE} AS builder", tests["BaseStage"])
+	require.Equal(t, "builder", tests["User"])
+
+	final := stages["alpine:3.14"].(map[string]interface{})
+	require.Equal(t, true, final["Final"])
+	require.Equal(t, "root", final["User"])
+	require.Equal(t, []interface{}{"${BASE_IMAGE} AS builder", "builder AS tests"}, final["CopyFrom"])
+
+	commands := docs[0]["command"].(map[string]interface{})
+	builderCommands := commands["${BASE_IMAGE} AS builder"].([]interface{})
+	require.Equal(t, []interface{}{"golang:1.17", "AS", "builder"}, builderCommands[0].(map[string]interface{})["Value"])
+	require.Equal(t, []interface{}{"APP_DIR", "/go/app", "DATA_DIR", "$APP_DIR/data"}, builderCommands[2].(map[string]interface{})["Value"])
+	require.Equal(t, []interface{}{"/go/app"}, builderCommands[3].(map[string]interface{})["Value"])
+	require.Equal(t, "FROM ${BASE_IMAGE} AS builder", builderCommands[0].(map[string]interface{})["Original"])
+}
@@ -0,0 +1,102 @@
+package graphql
+
+import (
+	"github.com/Checkmarx/kics/pkg/model"
+)
+
+// document gathers the definitions of a GraphQL schema into the KICS document structure
+// (schema, types and directives) with KICS line information
+type document struct {
+	schema         map[string]interface{}
+	schemaLine     int
+	types          map[string]interface{}
+	typeLines      map[string]model.LineObject
+	directives     map[string]interface{}
+	directiveLines map[string]model.LineObject
+}
+
+func newDocument() *document {
+	return &document{
+		types:          make(map[string]interface{}),
+		typeLines:      make(map[string]model.LineObject),
+		directives:     make(map[string]interface{}),
+		directiveLines: make(map[string]model.LineObject),
+	}
+}
+
+// getSchema returns the schema definition and its lines, creating it the first time
+func (d *document) getSchema(line int) (map[string]interface{}, map[string]model.LineObject) {
+	if d.schema == nil {
+		d.schemaLine = line
+		d.schema = map[string]interface{}{
+			kicsLines: map[string]model.LineObject{kicsDefault: {Line: line}},
+		}
+	}
+	return d.schema, d.schema[kicsLines].(map[string]model.LineObject)
+}
+
+// getType returns the definition of the named type and its lines, creating it the first time
+// so that type extensions ('extend type') are merged into the original definition
+func (d *document) getType(name, kind string, line int) (map[string]interface{}, map[string]model.LineObject) {
+	if typeDef, ok := d.types[name].(map[string]interface{}); ok {
+		return typeDef, typeDef[kicsLines].(map[string]model.LineObject)
+	}
+
+	lines := map[string]model.LineObject{
+		kicsDefault:  {Line: line},
+		"_kics_name": {Line: line},
+		"_kics_kind": {Line: line},
+	}
+	typeDef := map[string]interface{}{
+		"name":       name,
+		"kind":       kind,
+		"directives": make([]interface{}, 0),
+		kicsLines:    lines,
+	}
+	d.types[name] = typeDef
+	d.typeLines["_kics_"+name] = model.LineObject{Line: line}
+	return typeDef, lines
+}
+
+func (d *document) addDirective(name string, line int, directive map[string]interface{}) {
+	d.directives[name] = directive
+	d.directiveLines["_kics_"+name] = model.LineObject{Line: line}
+}
+
+// build returns the KICS document of the schema
+func (d *document) build() model.Document {
+	d.typeLines[kicsDefault] = model.LineObject{Line: firstLine(d.typeLines)}
+	d.directiveLines[kicsDefault] = model.LineObject{Line: firstLine(d.directiveLines)}
+	d.types[kicsLines] = d.typeLines
+	d.directives[kicsLines] = d.directiveLines
+
+	lines := map[string]model.LineObject{
+		kicsDefault:        {Line: 0},
+		"_kics_types":      d.typeLines[kicsDefault],
+		"_kics_directives": d.directiveLines[kicsDefault],
+	}
+	doc := model.Document{
+		"types":      d.types,
+		"directives": d.directives,
+	}
+	if d.schema != nil {
+		doc["schema"] = d.schema
+		lines["_kics_schema"] = model.LineObject{Line: d.schemaLine}
+	}
+	doc[kicsLines] = lines
+	return doc
+}
+
+// firstLine returns the lowest line of the definitions, 0 if there are none
+func firstLine(lines map[string]model.LineObject) int {
+	first := 0
+	for key, line := range lines {
+		if key == kicsDefault {
+			continue
+		}
+		if first == 0 || line.Line < first {
+			first = line.Line
+		}
+	}
+	return first
+}
@@ -0,0 +1,270 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunctuator
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+// token is a lexical token of a GraphQL document, line is the line where the token starts (1-based)
+type token struct {
+	kind  tokenKind
+	value string
+	line  int
+}
+
+// lexer splits a GraphQL document into tokens, ignoring white spaces, commas and comments
+type lexer struct {
+	src  string
+	pos  int
+	line int
+}
+
+func tokenize(content string) ([]token, error) {
+	l := &lexer{
+		src:  strings.TrimPrefix(strings.ReplaceAll(content, "\r\n", "\n"), "\ufeff"),
+		line: 1,
+	}
+
+	tokens := make([]token, 0)
+	for {
+		tok, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, tok)
+		if tok.kind == tokenEOF {
+			return tokens, nil
+		}
+	}
+}
+
+func (l *lexer) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", l.line, fmt.Sprintf(format, args...))
+}
+
+func (l *lexer) eof() bool {
+	return l.pos >= len(l.src)
+}
+
+func (l *lexer) peek() byte {
+	if l.eof() {
+		return 0
+	}
+	return l.src[l.pos]
+}
+
+func (l *lexer) advance(n int) {
+	for i := 0; i < n && !l.eof(); i++ {
+		if l.src[l.pos] == '\n' {
+			l.line++
+		}
+		l.pos++
+	}
+}
+
+func (l *lexer) skipIgnored() {
+	for !l.eof() {
+		switch l.peek() {
+		case ' ', '\t', '\n', '\r', ',':
+			l.advance(1)
+		case '#':
+			for !l.eof() && l.peek() != '\n' {
+				l.advance(1)
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipIgnored()
+	if l.eof() {
+		return token{kind: tokenEOF, line: l.line}, nil
+	}
+
+	line := l.line
+	c := l.peek()
+	switch {
+	case strings.HasPrefix(l.src[l.pos:], "..."):
+		l.advance(3) //nolint:gomnd
+		return token{kind: tokenPunctuator, value: "...", line: line}, nil
+	case strings.IndexByte("!$&():=@[]{}|", c) >= 0:
+		l.advance(1)
+		return token{kind: tokenPunctuator, value: string(c), line: line}, nil
+	case isNameStart(c):
+		start := l.pos
+		for !l.eof() && isNameContinue(l.peek()) {
+			l.advance(1)
+		}
+		return token{kind: tokenName, value: l.src[start:l.pos], line: line}, nil
+	case c == '-' || isDigit(c):
+		return l.readNumber(line)
+	case strings.HasPrefix(l.src[l.pos:], `"""`):
+		value, err := l.readBlockString()
+		return token{kind: tokenString, value: value, line: line}, err
+	case c == '"':
+		value, err := l.readString()
+		return token{kind: tokenString, value: value, line: line}, err
+	}
+
+	return token{}, l.errorf("unexpected character '%c'", c)
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNameContinue(c byte) bool {
+	return isNameStart(c) || isDigit(c)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func (l *lexer) readDigits() {
+	for !l.eof() && isDigit(l.peek()) {
+		l.advance(1)
+	}
+}
+
+func (l *lexer) readNumber(line int) (token, error) {
+	start := l.pos
+	kind := tokenInt
+	if l.peek() == '-' {
+		l.advance(1)
+	}
+	if !isDigit(l.peek()) {
+		return token{}, l.errorf("invalid number, expected digit but got '%c'", l.peek())
+	}
+	l.readDigits()
+	if l.peek() == '.' {
+		kind = tokenFloat
+		l.advance(1)
+		l.readDigits()
+	}
+	if c := l.peek(); c == 'e' || c == 'E' {
+		kind = tokenFloat
+		l.advance(1)
+		if c := l.peek(); c == '+' || c == '-' {
+			l.advance(1)
+		}
+		l.readDigits()
+	}
+	return token{kind: kind, value: l.src[start:l.pos], line: line}, nil
+}
+
+// readString reads a single line string, resolving its escape sequences
+func (l *lexer) readString() (string, error) {
+	l.advance(1)
+	var sb strings.Builder
+	for {
+		if l.eof() || l.peek() == '\n' {
+			return "", l.errorf("unterminated string")
+		}
+		c := l.peek()
+		switch c {
+		case '"':
+			l.advance(1)
+			return sb.String(), nil
+		case '\\':
+			if err := l.readEscape(&sb); err != nil {
+				return "", err
+			}
+		default:
+			r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+			sb.WriteRune(r)
+			l.advance(size)
+		}
+	}
+}
+
+func (l *lexer) readEscape(sb *strings.Builder) error {
+	l.advance(1)
+	escapes := map[byte]string{'"': `"`, '\\': `\`, '/': "/", 'b': "\b", 'f': "\f", 'n': "\n", 'r': "\r", 't': "\t"}
+	c := l.peek()
+	if value, ok := escapes[c]; ok {
+		sb.WriteString(value)
+		l.advance(1)
+		return nil
+	}
+	if c == 'u' && l.pos+5 <= len(l.src) {
+		code, err := strconv.ParseUint(l.src[l.pos+1:l.pos+5], 16, 32)
+		if err == nil {
+			sb.WriteRune(rune(code))
+			l.advance(5) //nolint:gomnd
+			return nil
+		}
+	}
+	return l.errorf("invalid escape sequence '\\%c'", c)
+}
+
+// readBlockString reads a block string ("""...""") and returns its value without the common indentation
+func (l *lexer) readBlockString() (string, error) {
+	l.advance(3) //nolint:gomnd
+	var sb strings.Builder
+	for {
+		if l.eof() {
+			return "", l.errorf("unterminated block string")
+		}
+		rest := l.src[l.pos:]
+		switch {
+		case strings.HasPrefix(rest, `\"""`):
+			sb.WriteString(`"""`)
+			l.advance(4) //nolint:gomnd
+		case strings.HasPrefix(rest, `"""`):
+			l.advance(3) //nolint:gomnd
+			return blockStringValue(sb.String()), nil
+		default:
+			sb.WriteByte(l.peek())
+			l.advance(1)
+		}
+	}
+}
+
+// blockStringValue removes the common indentation and the leading and trailing blank lines of a block string
+func blockStringValue(raw string) string {
+	lines := strings.Split(raw, "\n")
+
+	commonIndent := -1
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" {
+			continue
+		}
+		if indent := len(line) - len(trimmed); commonIndent < 0 || indent < commonIndent {
+			commonIndent = indent
+		}
+	}
+	if commonIndent > 0 {
+		for i := 1; i < len(lines); i++ {
+			if len(lines[i]) >= commonIndent {
+				lines[i] = lines[i][commonIndent:]
+			} else {
+				lines[i] = strings.TrimLeft(lines[i], " \t")
+			}
+		}
+	}
+
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
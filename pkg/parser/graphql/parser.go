@@ -0,0 +1,55 @@
+package graphql
+
+import (
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/pkg/errors"
+)
+
+// Parser - parser for GraphQL schema files (SDL)
+type Parser struct {
+}
+
+// Resolve - replace or modifies in-memory content before parsing
+func (p *Parser) Resolve(fileContent []byte, filename string) (*[]byte, error) {
+	return &fileContent, nil
+}
+
+// Parse - parses a GraphQL schema into a document with its types, directives and schema definition
+func (p *Parser) Parse(_ string, fileContent []byte) ([]model.Document, []int, error) {
+	tokens, err := tokenize(string(fileContent))
+	if err != nil {
+		return nil, []int{}, errors.Wrap(err, "failed to parse GraphQL file")
+	}
+
+	doc, err := newSyntaxParser(tokens).parseDocument()
+	if err != nil {
+		return nil, []int{}, errors.Wrap(err, "failed to parse GraphQL file")
+	}
+
+	return []model.Document{doc}, []int{}, nil
+}
+
+// GetKind returns the kind of the parser
+func (p *Parser) GetKind() model.FileKind {
+	return model.KindGRAPHQL
+}
+
+// SupportedExtensions returns GraphQL extensions
+func (p *Parser) SupportedExtensions() []string {
+	return []string{".graphql", ".graphqls", ".gql"}
+}
+
+// SupportedTypes returns types supported by this parser, which are GraphQL
+func (p *Parser) SupportedTypes() []string {
+	return []string{"GraphQL"}
+}
+
+// GetCommentToken return the comment token of GraphQL - #
+func (p *Parser) GetCommentToken() string {
+	return "#"
+}
+
+// StringifyContent converts original content into string formated version
+func (p *Parser) StringifyContent(content []byte) (string, error) {
+	return string(content), nil
+}
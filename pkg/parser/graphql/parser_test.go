@@ -0,0 +1,191 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+var have = `"""
+The schema of the API
+"""
+schema @auth(requires: USER) {
+  query: Query
+  mutation: Mutation
+}
+
+directive @auth(requires: Role = ADMIN) repeatable on OBJECT | FIELD_DEFINITION
+
+# root query type
+type Query implements Node & Entity @key(fields: "id") {
+  "the users"
+  users(first: Int = 10, filter: UserFilter): [User!]! @deprecated(reason: "use search")
+  me: User
+}
+
+extend type Query {
+  posts: [Post]
+}
+
+enum Role { ADMIN, USER }
+
+union SearchResult = | User | Post
+
+input UserFilter {
+  name: String = "a"
+  tags: [String!] = ["x", "y"]
+}
+
+scalar Date @specifiedBy(url: "https://tools.ietf.org/html/rfc3339")
+
+query GetUsers($first: Int) {
+  users(first: $first) { id }
+}
+`
+
+func getMap(t *testing.T, value interface{}, keys ...string) map[string]interface{} {
+	current := value
+	for _, key := range keys {
+		m, ok := current.(map[string]interface{})
+		if doc, isDoc := current.(model.Document); isDoc {
+			m, ok = map[string]interface{}(doc), true
+		}
+		require.True(t, ok, "key %s is not an object", key)
+		current = m[key]
+	}
+	m, ok := current.(map[string]interface{})
+	require.True(t, ok)
+	return m
+}
+
+func getLine(t *testing.T, obj map[string]interface{}, key string) int {
+	lines, ok := obj[kicsLines].(map[string]model.LineObject)
+	require.True(t, ok)
+	return lines["_kics_"+key].Line
+}
+
+// TestParser_GetKind tests the functions [GetKind()] and all the methods called by them
+func TestParser_GetKind(t *testing.T) {
+	p := &Parser{}
+	require.Equal(t, model.KindGRAPHQL, p.GetKind())
+}
+
+// TestParser_SupportedExtensions tests the functions [SupportedExtensions()] and all the methods called by them
+func TestParser_SupportedExtensions(t *testing.T) {
+	p := &Parser{}
+	require.Equal(t, []string{".graphql", ".graphqls", ".gql"}, p.SupportedExtensions())
+}
+
+// TestParser_SupportedTypes tests the functions [SupportedTypes()] and all the methods called by them
+func TestParser_SupportedTypes(t *testing.T) {
+	p := &Parser{}
+	require.Equal(t, []string{"GraphQL"}, p.SupportedTypes())
+}
+
+// Test_GetCommentToken must get the token that represents a comment
+func Test_GetCommentToken(t *testing.T) {
+	p := &Parser{}
+	require.Equal(t, "#", p.GetCommentToken())
+}
+
+// TestParser_Parse tests the functions [Parse()] and all the methods called by them
+func TestParser_Parse(t *testing.T) {
+	p := &Parser{}
+
+	docs, _, err := p.Parse("schema.graphql", []byte(have))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	doc := docs[0]
+
+	t.Run("schema", func(t *testing.T) {
+		schema := getMap(t, doc, "schema")
+		require.Equal(t, "Query", schema["query"])
+		require.Equal(t, "Mutation", schema["mutation"])
+		require.Len(t, schema["directives"], 1)
+		require.Equal(t, 6, getLine(t, schema, "mutation"))
+	})
+
+	t.Run("directive_definition", func(t *testing.T) {
+		auth := getMap(t, doc, "directives", "auth")
+		require.Equal(t, true, auth["repeatable"])
+		require.Equal(t, []interface{}{"OBJECT", "FIELD_DEFINITION"}, auth["on"])
+		require.Equal(t, "ADMIN", getMap(t, auth, "arguments", "requires")["defaultValue"])
+	})
+
+	t.Run("object_type", func(t *testing.T) {
+		query := getMap(t, doc, "types", "Query")
+		require.Equal(t, "OBJECT", query["kind"])
+		require.Equal(t, []interface{}{"Node", "Entity"}, query["interfaces"])
+		require.Equal(t, 12, getLine(t, getMap(t, doc, "types"), "Query"))
+
+		users := getMap(t, query, "fields", "users")
+		require.Equal(t, "[User!]!", users["type"])
+		require.Equal(t, "User", users["namedType"])
+		require.Equal(t, true, users["list"])
+		require.Equal(t, true, users["nonNull"])
+		require.Equal(t, "the users", users["description"])
+		require.Equal(t, int64(10), getMap(t, users, "arguments", "first")["defaultValue"])
+		require.Equal(t, 14, getLine(t, getMap(t, query, "fields"), "users"))
+
+		directives, ok := users["directives"].([]interface{})
+		require.True(t, ok)
+		require.Len(t, directives, 1)
+		require.Equal(t, "use search", getMap(t, directives[0], "arguments")["reason"])
+	})
+
+	t.Run("type_extension", func(t *testing.T) {
+		posts := getMap(t, doc, "types", "Query", "fields", "posts")
+		require.Equal(t, "[Post]", posts["type"])
+		require.Equal(t, false, posts["nonNull"])
+		require.Equal(t, 19, getLine(t, getMap(t, doc, "types", "Query", "fields"), "posts"))
+	})
+
+	t.Run("other_types", func(t *testing.T) {
+		require.Contains(t, getMap(t, doc, "types", "Role", "values"), "USER")
+		require.Equal(t, []interface{}{"User", "Post"}, getMap(t, doc, "types", "SearchResult")["types"])
+		require.Equal(t, []interface{}{"x", "y"}, getMap(t, doc, "types", "UserFilter", "inputFields", "tags")["defaultValue"])
+		require.Equal(t, "SCALAR", getMap(t, doc, "types", "Date")["kind"])
+	})
+
+	t.Run("operations_are_ignored", func(t *testing.T) {
+		require.NotContains(t, getMap(t, doc, "types"), "GetUsers")
+	})
+}
+
+// TestParser_Parse_Errors tests that invalid GraphQL schemas return an error
+func TestParser_Parse_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{
+			name:    "unterminated_string",
+			content: "type Query { \"description\n me: User }",
+		},
+		{
+			name:    "missing_field_type",
+			content: "type Query { me }",
+		},
+		{
+			name:    "unknown_definition",
+			content: "object Query { me: User }",
+		},
+		{
+			name:    "unexpected_character",
+			content: "type Query { me: User ? }",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := (&Parser{}).Parse("schema.graphql", []byte(tt.content))
+			require.Error(t, err)
+		})
+	}
+}
+
+// Test_blockStringValue tests the functions [blockStringValue()] and all the methods called by them
+func Test_blockStringValue(t *testing.T) {
+	require.Equal(t, "first\n  second", blockStringValue("\n    first\n      second\n  "))
+}
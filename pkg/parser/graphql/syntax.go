@@ -0,0 +1,678 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Checkmarx/kics/pkg/model"
+)
+
+const (
+	kicsLines   = "_kics_lines"
+	kicsDefault = "_kics__default"
+)
+
+// typeKinds maps the GraphQL type definition keywords to their introspection kind (__TypeKind)
+var typeKinds = map[string]string{
+	"type":      "OBJECT",
+	"interface": "INTERFACE",
+	"input":     "INPUT_OBJECT",
+	"enum":      "ENUM",
+	"union":     "UNION",
+	"scalar":    "SCALAR",
+}
+
+// executableKeywords are the keywords that start operations and fragments, which are not part of the schema
+var executableKeywords = map[string]bool{
+	"query":        true,
+	"mutation":     true,
+	"subscription": true,
+	"fragment":     true,
+}
+
+// syntaxParser is a recursive descent parser for the GraphQL type system definition language (SDL)
+// tokens are the lexical tokens of the document and pos is the index of the current token
+type syntaxParser struct {
+	tokens []token
+	pos    int
+}
+
+func newSyntaxParser(tokens []token) *syntaxParser {
+	return &syntaxParser{
+		tokens: tokens,
+	}
+}
+
+func (p *syntaxParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *syntaxParser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *syntaxParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", p.peek().line, fmt.Sprintf(format, args...))
+}
+
+// isPunctuator checks if the current token is the given punctuator
+func (p *syntaxParser) isPunctuator(value string) bool {
+	tok := p.peek()
+	return tok.kind == tokenPunctuator && tok.value == value
+}
+
+// isKeyword checks if the current token is the given name
+func (p *syntaxParser) isKeyword(value string) bool {
+	tok := p.peek()
+	return tok.kind == tokenName && tok.value == value
+}
+
+// skipPunctuator consumes the current token if it is the given punctuator
+func (p *syntaxParser) skipPunctuator(value string) bool {
+	if p.isPunctuator(value) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *syntaxParser) expectPunctuator(value string) error {
+	if !p.skipPunctuator(value) {
+		return p.errorf("expected '%s' but got '%s'", value, p.peek().value)
+	}
+	return nil
+}
+
+func (p *syntaxParser) expectName() (token, error) {
+	if tok := p.peek(); tok.kind == tokenName {
+		return p.next(), nil
+	}
+	return token{}, p.errorf("expected name but got '%s'", p.peek().value)
+}
+
+// parseDocument parses all the definitions of the document
+func (p *syntaxParser) parseDocument() (model.Document, error) {
+	doc := newDocument()
+
+	for p.peek().kind != tokenEOF {
+		description, hasDescription := p.parseDescription()
+		tok := p.peek()
+
+		if tok.kind == tokenPunctuator && tok.value == "{" || tok.kind == tokenName && executableKeywords[tok.value] {
+			if err := p.skipExecutableDefinition(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		keyword, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		extend := keyword.value == "extend"
+		if extend {
+			if keyword, err = p.expectName(); err != nil {
+				return nil, err
+			}
+		}
+
+		switch kind, isType := typeKinds[keyword.value]; {
+		case keyword.value == "schema":
+			err = p.parseSchemaDefinition(doc, keyword.line)
+		case keyword.value == "directive" && !extend:
+			err = p.parseDirectiveDefinition(doc, keyword.line, description, hasDescription)
+		case isType:
+			err = p.parseTypeDefinition(doc, kind, keyword.line, description, hasDescription)
+		default:
+			err = fmt.Errorf("line %d: unexpected definition '%s'", keyword.line, keyword.value)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return doc.build(), nil
+}
+
+// skipExecutableDefinition consumes an operation or fragment definition until the end of its selection set
+func (p *syntaxParser) skipExecutableDefinition() error {
+	for !p.isPunctuator("{") {
+		if p.peek().kind == tokenEOF {
+			return p.errorf("unexpected end of document")
+		}
+		p.next()
+	}
+	depth := 0
+	for {
+		tok := p.next()
+		switch {
+		case tok.kind == tokenEOF:
+			return p.errorf("unexpected end of document")
+		case tok.kind == tokenPunctuator && tok.value == "{":
+			depth++
+		case tok.kind == tokenPunctuator && tok.value == "}":
+			depth--
+			if depth == 0 {
+				return nil
+			}
+		}
+	}
+}
+
+func (p *syntaxParser) parseDescription() (string, bool) {
+	if tok := p.peek(); tok.kind == tokenString {
+		p.next()
+		return tok.value, true
+	}
+	return "", false
+}
+
+// parseSchemaDefinition parses 'schema @directives { query: Query ... }'
+func (p *syntaxParser) parseSchemaDefinition(doc *document, line int) error {
+	schema, lines := doc.getSchema(line)
+
+	directives, directiveLines, err := p.parseDirectives()
+	if err != nil {
+		return err
+	}
+	appendDirectives(schema, lines, directives, directiveLines)
+
+	if !p.skipPunctuator("{") {
+		return nil
+	}
+	for !p.skipPunctuator("}") {
+		operation, err := p.expectName()
+		if err != nil {
+			return err
+		}
+		if err := p.expectPunctuator(":"); err != nil {
+			return err
+		}
+		typeName, err := p.expectName()
+		if err != nil {
+			return err
+		}
+		schema[operation.value] = typeName.value
+		lines["_kics_"+operation.value] = model.LineObject{Line: operation.line}
+	}
+	return nil
+}
+
+// parseDirectiveDefinition parses 'directive @name(arguments) repeatable on LOCATION | LOCATION'
+func (p *syntaxParser) parseDirectiveDefinition(doc *document, line int, description string, hasDescription bool) error {
+	if err := p.expectPunctuator("@"); err != nil {
+		return err
+	}
+	name, err := p.expectName()
+	if err != nil {
+		return err
+	}
+
+	directive := map[string]interface{}{
+		"name":       name.value,
+		"repeatable": false,
+	}
+	lines := map[string]model.LineObject{
+		kicsDefault:  {Line: line},
+		"_kics_name": {Line: name.line},
+	}
+	if hasDescription {
+		directive["description"] = description
+		lines["_kics_description"] = model.LineObject{Line: line}
+	}
+
+	arguments, argumentsLine, err := p.parseArgumentsDefinition(name.line)
+	if err != nil {
+		return err
+	}
+	directive["arguments"] = arguments
+	lines["_kics_arguments"] = model.LineObject{Line: argumentsLine}
+
+	if p.isKeyword("repeatable") {
+		directive["repeatable"] = true
+		lines["_kics_repeatable"] = model.LineObject{Line: p.next().line}
+	}
+	if !p.isKeyword("on") {
+		return p.errorf("expected 'on' but got '%s'", p.peek().value)
+	}
+	onLine := p.next().line
+
+	locations := make([]interface{}, 0)
+	locationLines := make([]map[string]model.LineObject, 0)
+	p.skipPunctuator("|")
+	for {
+		location, err := p.expectName()
+		if err != nil {
+			return err
+		}
+		locations = append(locations, location.value)
+		locationLines = append(locationLines, map[string]model.LineObject{kicsDefault: {Line: location.line}})
+		if !p.skipPunctuator("|") {
+			break
+		}
+	}
+	directive["on"] = locations
+	lines["_kics_on"] = model.LineObject{Line: onLine, Arr: locationLines}
+
+	directive[kicsLines] = lines
+	doc.addDirective(name.value, name.line, directive)
+	return nil
+}
+
+// parseTypeDefinition parses the definition (or extension) of a named type
+func (p *syntaxParser) parseTypeDefinition(doc *document, kind string, line int,
+	description string, hasDescription bool) error {
+	name, err := p.expectName()
+	if err != nil {
+		return err
+	}
+	typeDef, lines := doc.getType(name.value, kind, name.line)
+	if hasDescription {
+		typeDef["description"] = description
+		lines["_kics_description"] = model.LineObject{Line: line}
+	}
+
+	if p.isKeyword("implements") {
+		if err := p.parseImplements(typeDef, lines); err != nil {
+			return err
+		}
+	}
+
+	directives, directiveLines, err := p.parseDirectives()
+	if err != nil {
+		return err
+	}
+	appendDirectives(typeDef, lines, directives, directiveLines)
+
+	switch kind {
+	case "OBJECT", "INTERFACE":
+		return p.parseFieldsDefinition(typeDef, lines, "fields", false)
+	case "INPUT_OBJECT":
+		return p.parseFieldsDefinition(typeDef, lines, "inputFields", true)
+	case "ENUM":
+		return p.parseEnumValues(typeDef, lines)
+	case "UNION":
+		return p.parseUnionMembers(typeDef, lines)
+	}
+	return nil
+}
+
+// parseImplements parses the interfaces implemented by an object or interface ('implements A & B')
+func (p *syntaxParser) parseImplements(typeDef map[string]interface{}, lines map[string]model.LineObject) error {
+	implementsLine := p.next().line
+	interfaces, _ := typeDef["interfaces"].([]interface{})
+	interfaceLines := lines["_kics_interfaces"].Arr
+
+	p.skipPunctuator("&")
+	for {
+		name, err := p.expectName()
+		if err != nil {
+			return err
+		}
+		interfaces = append(interfaces, name.value)
+		interfaceLines = append(interfaceLines, map[string]model.LineObject{kicsDefault: {Line: name.line}})
+		if !p.skipPunctuator("&") {
+			break
+		}
+	}
+
+	typeDef["interfaces"] = interfaces
+	lines["_kics_interfaces"] = model.LineObject{Line: implementsLine, Arr: interfaceLines}
+	return nil
+}
+
+// parseFieldsDefinition parses the fields of objects and interfaces or the input fields of input objects
+func (p *syntaxParser) parseFieldsDefinition(typeDef map[string]interface{}, lines map[string]model.LineObject,
+	key string, input bool) error {
+	if !p.isPunctuator("{") {
+		return nil
+	}
+	openLine := p.next().line
+
+	fields, _ := typeDef[key].(map[string]interface{})
+	if fields == nil {
+		fields = map[string]interface{}{
+			kicsLines: map[string]model.LineObject{kicsDefault: {Line: openLine}},
+		}
+		lines["_kics_"+key] = model.LineObject{Line: openLine}
+	}
+	fieldLines := fields[kicsLines].(map[string]model.LineObject)
+
+	for !p.skipPunctuator("}") {
+		description, hasDescription := p.parseDescription()
+		name, field, err := p.parseField(input)
+		if err != nil {
+			return err
+		}
+		if hasDescription {
+			field["description"] = description
+			field[kicsLines].(map[string]model.LineObject)["_kics_description"] = model.LineObject{Line: name.line}
+		}
+		fields[name.value] = field
+		fieldLines["_kics_"+name.value] = model.LineObject{Line: name.line}
+	}
+
+	typeDef[key] = fields
+	return nil
+}
+
+// parseField parses a field definition ('name(arguments): Type @directives') or
+// an input value definition ('name: Type = default @directives')
+func (p *syntaxParser) parseField(input bool) (token, map[string]interface{}, error) {
+	name, err := p.expectName()
+	if err != nil {
+		return token{}, nil, err
+	}
+	field := map[string]interface{}{
+		"name": name.value,
+	}
+	lines := map[string]model.LineObject{
+		kicsDefault:  {Line: name.line},
+		"_kics_name": {Line: name.line},
+	}
+
+	if !input {
+		arguments, argumentsLine, err := p.parseArgumentsDefinition(name.line)
+		if err != nil {
+			return token{}, nil, err
+		}
+		field["arguments"] = arguments
+		lines["_kics_arguments"] = model.LineObject{Line: argumentsLine}
+	}
+
+	if err := p.expectPunctuator(":"); err != nil {
+		return token{}, nil, err
+	}
+	typeLine := p.peek().line
+	if err := p.parseTypeReference(field); err != nil {
+		return token{}, nil, err
+	}
+	for _, key := range []string{"type", "namedType", "list", "nonNull"} {
+		lines["_kics_"+key] = model.LineObject{Line: typeLine}
+	}
+
+	if p.skipPunctuator("=") {
+		valueLine := p.peek().line
+		value, err := p.parseValue()
+		if err != nil {
+			return token{}, nil, err
+		}
+		field["defaultValue"] = value
+		lines["_kics_defaultValue"] = model.LineObject{Line: valueLine}
+	}
+
+	directives, directiveLines, err := p.parseDirectives()
+	if err != nil {
+		return token{}, nil, err
+	}
+	appendDirectives(field, lines, directives, directiveLines)
+
+	field[kicsLines] = lines
+	return name, field, nil
+}
+
+// parseArgumentsDefinition parses the arguments of a field or directive, the map is always returned, even if empty
+func (p *syntaxParser) parseArgumentsDefinition(line int) (map[string]interface{}, int, error) {
+	argumentLines := map[string]model.LineObject{kicsDefault: {Line: line}}
+	arguments := map[string]interface{}{kicsLines: argumentLines}
+
+	if !p.isPunctuator("(") {
+		return arguments, line, nil
+	}
+	line = p.next().line
+	argumentLines[kicsDefault] = model.LineObject{Line: line}
+
+	for !p.skipPunctuator(")") {
+		description, hasDescription := p.parseDescription()
+		name, argument, err := p.parseField(true)
+		if err != nil {
+			return nil, line, err
+		}
+		if hasDescription {
+			argument["description"] = description
+		}
+		arguments[name.value] = argument
+		argumentLines["_kics_"+name.value] = model.LineObject{Line: name.line}
+	}
+	return arguments, line, nil
+}
+
+// parseTypeReference parses a type reference (e.g. '[User!]!') and sets its representation in the field
+func (p *syntaxParser) parseTypeReference(field map[string]interface{}) error {
+	typeRef, namedType, list, err := p.parseType()
+	if err != nil {
+		return err
+	}
+	field["type"] = typeRef
+	field["namedType"] = namedType
+	field["list"] = list
+	field["nonNull"] = typeRef[len(typeRef)-1] == '!'
+	return nil
+}
+
+func (p *syntaxParser) parseType() (typeRef, namedType string, list bool, err error) {
+	if p.skipPunctuator("[") {
+		inner, named, _, err := p.parseType()
+		if err != nil {
+			return "", "", false, err
+		}
+		if err := p.expectPunctuator("]"); err != nil {
+			return "", "", false, err
+		}
+		typeRef, namedType, list = "["+inner+"]", named, true
+	} else {
+		name, err := p.expectName()
+		if err != nil {
+			return "", "", false, err
+		}
+		typeRef, namedType = name.value, name.value
+	}
+	if p.skipPunctuator("!") {
+		typeRef += "!"
+	}
+	return typeRef, namedType, list, nil
+}
+
+// parseEnumValues parses the values of an enum definition
+func (p *syntaxParser) parseEnumValues(typeDef map[string]interface{}, lines map[string]model.LineObject) error {
+	if !p.isPunctuator("{") {
+		return nil
+	}
+	openLine := p.next().line
+
+	values, _ := typeDef["values"].(map[string]interface{})
+	if values == nil {
+		values = map[string]interface{}{
+			kicsLines: map[string]model.LineObject{kicsDefault: {Line: openLine}},
+		}
+		lines["_kics_values"] = model.LineObject{Line: openLine}
+	}
+	valueLines := values[kicsLines].(map[string]model.LineObject)
+
+	for !p.skipPunctuator("}") {
+		description, hasDescription := p.parseDescription()
+		name, err := p.expectName()
+		if err != nil {
+			return err
+		}
+		value := map[string]interface{}{
+			"name": name.value,
+		}
+		enumLines := map[string]model.LineObject{
+			kicsDefault:  {Line: name.line},
+			"_kics_name": {Line: name.line},
+		}
+		if hasDescription {
+			value["description"] = description
+		}
+		directives, directiveLines, err := p.parseDirectives()
+		if err != nil {
+			return err
+		}
+		appendDirectives(value, enumLines, directives, directiveLines)
+		value[kicsLines] = enumLines
+
+		values[name.value] = value
+		valueLines["_kics_"+name.value] = model.LineObject{Line: name.line}
+	}
+
+	typeDef["values"] = values
+	return nil
+}
+
+// parseUnionMembers parses the member types of an union definition ('= A | B')
+func (p *syntaxParser) parseUnionMembers(typeDef map[string]interface{}, lines map[string]model.LineObject) error {
+	if !p.isPunctuator("=") {
+		return nil
+	}
+	equalsLine := p.next().line
+
+	members, _ := typeDef["types"].([]interface{})
+	memberLines := lines["_kics_types"].Arr
+
+	p.skipPunctuator("|")
+	for {
+		name, err := p.expectName()
+		if err != nil {
+			return err
+		}
+		members = append(members, name.value)
+		memberLines = append(memberLines, map[string]model.LineObject{kicsDefault: {Line: name.line}})
+		if !p.skipPunctuator("|") {
+			break
+		}
+	}
+
+	typeDef["types"] = members
+	lines["_kics_types"] = model.LineObject{Line: equalsLine, Arr: memberLines}
+	return nil
+}
+
+// parseDirectives parses the directives applied to a definition ('@name(argument: value)')
+func (p *syntaxParser) parseDirectives() (directives []interface{}, lines []map[string]model.LineObject, err error) {
+	directives = make([]interface{}, 0)
+	lines = make([]map[string]model.LineObject, 0)
+
+	for p.skipPunctuator("@") {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, nil, err
+		}
+		arguments := make(map[string]interface{})
+		argumentLines := map[string]model.LineObject{kicsDefault: {Line: name.line}}
+
+		if p.skipPunctuator("(") {
+			for !p.skipPunctuator(")") {
+				argument, err := p.expectName()
+				if err != nil {
+					return nil, nil, err
+				}
+				if err := p.expectPunctuator(":"); err != nil {
+					return nil, nil, err
+				}
+				value, err := p.parseValue()
+				if err != nil {
+					return nil, nil, err
+				}
+				arguments[argument.value] = value
+				argumentLines["_kics_"+argument.value] = model.LineObject{Line: argument.line}
+			}
+		}
+		arguments[kicsLines] = argumentLines
+
+		directives = append(directives, map[string]interface{}{
+			"name":      name.value,
+			"arguments": arguments,
+			kicsLines: map[string]model.LineObject{
+				kicsDefault:       {Line: name.line},
+				"_kics_name":      {Line: name.line},
+				"_kics_arguments": {Line: name.line},
+			},
+		})
+		lines = append(lines, map[string]model.LineObject{kicsDefault: {Line: name.line}})
+	}
+	return directives, lines, nil
+}
+
+// parseValue parses a constant value (scalars, enum values, lists and objects)
+func (p *syntaxParser) parseValue() (interface{}, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenInt:
+		return strconv.ParseInt(tok.value, 10, 64)
+	case tokenFloat:
+		return strconv.ParseFloat(tok.value, 64)
+	case tokenString:
+		return tok.value, nil
+	case tokenName:
+		switch tok.value {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		return tok.value, nil
+	case tokenPunctuator:
+		switch tok.value {
+		case "$":
+			name, err := p.expectName()
+			return "$" + name.value, err
+		case "[":
+			list := make([]interface{}, 0)
+			for !p.skipPunctuator("]") {
+				value, err := p.parseValue()
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, value)
+			}
+			return list, nil
+		case "{":
+			obj := make(map[string]interface{})
+			for !p.skipPunctuator("}") {
+				key, err := p.expectName()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expectPunctuator(":"); err != nil {
+					return nil, err
+				}
+				if obj[key.value], err = p.parseValue(); err != nil {
+					return nil, err
+				}
+			}
+			return obj, nil
+		}
+	case tokenEOF:
+		return nil, fmt.Errorf("line %d: unexpected end of document", tok.line)
+	}
+	return nil, fmt.Errorf("line %d: unexpected value '%s'", tok.line, tok.value)
+}
+
+// appendDirectives adds the directives to the ones already applied to the definition (extensions add directives)
+func appendDirectives(obj map[string]interface{}, lines map[string]model.LineObject,
+	directives []interface{}, directiveLines []map[string]model.LineObject) {
+	existing, ok := obj["directives"].([]interface{})
+	if !ok {
+		existing = make([]interface{}, 0)
+	}
+	if len(directives) == 0 {
+		if !ok {
+			obj["directives"] = existing
+		}
+		return
+	}
+
+	line := lines[kicsDefault].Line
+	if len(directiveLines) > 0 {
+		line = directiveLines[0][kicsDefault].Line
+	}
+	obj["directives"] = append(existing, directives...)
+	lines["_kics_directives"] = model.LineObject{Line: line, Arr: append(lines["_kics_directives"].Arr, directiveLines...)}
+}
@@ -5,10 +5,13 @@ import (
 	"encoding/json"
 
 	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/Checkmarx/kics/pkg/resolver/openapi"
 )
 
-// Parser defines a parser type
+// Parser defines a parser type, the '$ref' pointers of OpenAPI documents are only followed to files inside
+// ScanPaths (the directory of the document when empty)
 type Parser struct {
+	ScanPaths   []string
 	shouldIdent bool
 }
 
@@ -18,7 +21,7 @@ func (p *Parser) Resolve(fileContent []byte, filename string) (*[]byte, error) {
 }
 
 // Parse parses json file and returns it as a Document
-func (p *Parser) Parse(_ string, fileContent []byte) ([]model.Document, []int, error) {
+func (p *Parser) Parse(filePath string, fileContent []byte) ([]model.Document, []int, error) {
 	r := model.Document{}
 	err := json.Unmarshal(fileContent, &r)
 	if err != nil {
@@ -34,7 +37,7 @@ func (p *Parser) Parse(_ string, fileContent []byte) ([]model.Document, []int, e
 	kicsPlan, err := parseTFPlan(kicsJSON)
 	if err != nil {
 		// JSON is not a tf plan
-		return []model.Document{kicsJSON}, []int{}, nil
+		return []model.Document{openapi.NewResolver(p.ScanPaths...).Resolve(kicsJSON, filePath)}, []int{}, nil
 	}
 
 	p.shouldIdent = true
@@ -54,7 +57,7 @@ func (p *Parser) GetKind() model.FileKind {
 
 // SupportedTypes returns types supported by this parser, which are cloudFormation
 func (p *Parser) SupportedTypes() []string {
-	return []string{"CloudFormation", "OpenAPI", "AzureResourceManager", "Terraform"}
+	return []string{"CloudFormation", "OpenAPI", "AzureResourceManager", "Terraform", "AsyncAPI"}
 }
 
 // GetCommentToken return an empty string, since JSON does not have comment token
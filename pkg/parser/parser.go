@@ -171,7 +171,7 @@ func contains(types, supportedTypes []string) (invalidArgsRes []string, contRes,
 }
 
 func (c *Parser) isValidExtension(filePath string) bool {
-	ext := filepath.Ext(filePath)
+	ext := model.FileExtension(filePath)
 	if ext == "" {
 		ext = filepath.Base(filePath)
 	}
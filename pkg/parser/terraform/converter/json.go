@@ -0,0 +1,326 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/pkg/errors"
+)
+
+// jsonCommentKey is the property used for comments in the JSON configuration syntax
+const jsonCommentKey = "//"
+
+// jsonBlockLabels are the number of labels of the block types of the JSON configuration syntax,
+// the other block types have no labels
+var jsonBlockLabels = map[string]int{
+	"resource": 2,
+	"data":     2,
+	"variable": 1,
+	"output":   1,
+	"module":   1,
+	"provider": 1,
+	"check":    1,
+}
+
+// jsonValue is a value of a JSON document with the line where it starts, objects keep the order of their keys
+type jsonValue struct {
+	line     int
+	keyLines map[string]int
+	keys     []string
+	fields   map[string]*jsonValue
+	items    []*jsonValue
+	scalar   interface{}
+	isObject bool
+	isArray  bool
+}
+
+// DefaultConvertedJSON converts a file written in the JSON configuration syntax ('.tf.json') to the same document
+// DefaultConverted returns for the native syntax, variables indicates a variables file ('.tfvars.json') whose
+// properties are all attributes. Strings are handled as templates, as the native syntax expressions are
+func DefaultConvertedJSON(content []byte, variables bool, inputVariables VariableMap) (model.Document, error) {
+	inputVarMap = inputVariables
+	root, err := decodeJSON(content)
+	if err != nil {
+		return nil, err
+	}
+	if !root.isObject {
+		return nil, errors.New("the root of a Terraform JSON configuration must be an object")
+	}
+
+	if variables {
+		return convertJSONBody(root, 0)
+	}
+
+	out := make(model.Document)
+	kicsS := make(map[string]model.LineObject)
+	kicsS["_kics__default"] = model.LineObject{Line: 0}
+	for _, key := range root.keys {
+		if key == jsonCommentKey {
+			continue
+		}
+		kicsS["_kics_"+key] = model.LineObject{Line: root.keyLines[key]}
+		if err := convertJSONBlocks(key, root.fields[key], jsonBlockLabels[key], []string{}, out, root.keyLines[key]); err != nil {
+			return nil, err
+		}
+	}
+	out["_kics_lines"] = kicsS
+	return out, nil
+}
+
+// convertJSONBlocks converts the blocks of the block type, the labels are the keys of the nested objects and
+// the bodies are objects or arrays of objects (several blocks with the same labels)
+func convertJSONBlocks(blockType string, value *jsonValue, labelsLeft int, labels []string, out model.Document, defLine int) error {
+	if labelsLeft > 0 {
+		if !value.isObject {
+			return fmt.Errorf("unable to convert block %s: labels must be object properties", blockType)
+		}
+		for _, label := range value.keys {
+			if label == jsonCommentKey {
+				continue
+			}
+			blockLabels := append(append(make([]string, 0, len(labels)+1), labels...), label)
+			if err := convertJSONBlocks(blockType, value.fields[label], labelsLeft-1, blockLabels, out, value.keyLines[label]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	bodies := []*jsonValue{value}
+	if value.isArray {
+		bodies = value.items
+	}
+	for _, body := range bodies {
+		if !body.isObject {
+			return fmt.Errorf("unable to convert block %s: the body must be an object", blockType)
+		}
+		if err := convertJSONBlock(blockType, labels, body, out, defLine); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// convertJSONBlock adds the block to the document in the same way convertBlock does for the native syntax
+func convertJSONBlock(blockType string, labels []string, body *jsonValue, out model.Document, defLine int) error {
+	value, err := convertJSONBody(body, defLine)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		return nil
+	}
+
+	key := blockType
+	for _, label := range labels {
+		if inner, exists := out[key]; exists {
+			var ok bool
+			out, ok = inner.(model.Document)
+			if !ok {
+				return fmt.Errorf("unable to convert Block to JSON: %v.%v", blockType, strings.Join(labels, "."))
+			}
+		} else {
+			obj := make(model.Document)
+			out[key] = obj
+			out = obj
+		}
+		key = label
+	}
+
+	if current, exists := out[key]; exists {
+		if list, ok := current.([]interface{}); ok {
+			out[key] = append(list, value)
+		} else {
+			out[key] = []interface{}{current, value}
+		}
+	} else {
+		out[key] = value
+	}
+	return nil
+}
+
+// convertJSONBody converts the properties of the object as attributes, nil is returned when 'count' is 0
+func convertJSONBody(body *jsonValue, defLine int) (model.Document, error) {
+	if count, ok := body.fields["count"]; ok {
+		if number, ok := count.scalar.(json.Number); ok && number.String() == "0" {
+			return nil, nil
+		}
+	}
+
+	out := make(model.Document)
+	kicsS := make(map[string]model.LineObject)
+	kicsS["_kics__default"] = model.LineObject{Line: defLine}
+	for _, key := range body.keys {
+		if key == jsonCommentKey {
+			continue
+		}
+		value, err := convertJSONValue(body.fields[key], body.keyLines[key])
+		if err != nil {
+			return nil, err
+		}
+		out[key] = value
+		kicsS["_kics_"+key] = model.LineObject{
+			Line: body.keyLines[key],
+			Arr:  getJSONArrLines(body.fields[key]),
+		}
+	}
+	out["_kics_lines"] = kicsS
+	return out, nil
+}
+
+func convertJSONValue(value *jsonValue, defLine int) (interface{}, error) {
+	switch {
+	case value.isObject:
+		return convertJSONBody(value, defLine)
+	case value.isArray:
+		list := make([]interface{}, 0, len(value.items))
+		for _, item := range value.items {
+			elem, err := convertJSONValue(item, item.line)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, elem)
+		}
+		return list, nil
+	}
+
+	switch scalar := value.scalar.(type) {
+	case string:
+		return convertJSONString(scalar)
+	case json.Number:
+		if integer, err := scalar.Int64(); err == nil {
+			return integer, nil
+		}
+		return scalar.Float64()
+	default:
+		return scalar, nil
+	}
+}
+
+// convertJSONString converts the string as a template of the native syntax, so interpolations are
+// evaluated with the input variables or kept as '${...}'
+func convertJSONString(str string) (interface{}, error) {
+	if !strings.Contains(str, "${") && !strings.Contains(str, "%{") {
+		return str, nil
+	}
+	expr, diagnostics := hclsyntax.ParseTemplate([]byte(str), "", hcl.Pos{Line: 1, Column: 1, Byte: 0})
+	if diagnostics != nil && diagnostics.HasErrors() {
+		return str, nil
+	}
+	c := converter{bytes: []byte(str)}
+	return c.convertExpression(expr)
+}
+
+// getJSONArrLines gets the line information of the array elements, as getArrLines does for the native syntax
+func getJSONArrLines(value *jsonValue) []map[string]model.LineObject {
+	arr := make([]map[string]model.LineObject, 0)
+	if !value.isArray {
+		return arr
+	}
+	for _, item := range value.items {
+		arrEx := make(map[string]model.LineObject)
+		arrEx["_kics__default"] = model.LineObject{Line: item.line}
+		switch {
+		case item.isObject:
+			if len(item.keys) > 0 {
+				arrEx["_kics__default"] = model.LineObject{Line: item.keyLines[item.keys[0]]}
+			}
+			for _, key := range item.keys {
+				arrEx["_kics_"+key] = model.LineObject{Line: item.keyLines[key]}
+			}
+		case item.isArray:
+			arrEx["_kics__default"] = model.LineObject{Arr: getJSONArrLines(item)}
+		}
+		arr = append(arr, arrEx)
+	}
+	return arr
+}
+
+// decodeJSON decodes the JSON content keeping the lines of the values and of the object keys
+func decodeJSON(content []byte) (*jsonValue, error) {
+	newLines := make([]int, 0)
+	for idx, char := range content {
+		if char == '\n' {
+			newLines = append(newLines, idx)
+		}
+	}
+	d := &jsonDecoder{dec: json.NewDecoder(bytes.NewReader(content)), newLines: newLines}
+	d.dec.UseNumber()
+
+	value, err := d.decodeValue()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse Terraform JSON configuration")
+	}
+	if _, err := d.dec.Token(); err != io.EOF {
+		return nil, errors.New("failed to parse Terraform JSON configuration: unexpected content after the root value")
+	}
+	return value, nil
+}
+
+type jsonDecoder struct {
+	dec      *json.Decoder
+	newLines []int
+}
+
+// line returns the line of the last token read
+func (d *jsonDecoder) line() int {
+	offset := int(d.dec.InputOffset()) - 1
+	return sort.SearchInts(d.newLines, offset) + 1
+}
+
+func (d *jsonDecoder) decodeValue() (*jsonValue, error) {
+	token, err := d.dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	value := &jsonValue{line: d.line()}
+
+	switch token {
+	case json.Delim('{'):
+		value.isObject = true
+		value.fields = make(map[string]*jsonValue)
+		value.keyLines = make(map[string]int)
+		for d.dec.More() {
+			keyToken, err := d.dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, ok := keyToken.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid object key %v", keyToken)
+			}
+			keyLine := d.line()
+			field, err := d.decodeValue()
+			if err != nil {
+				return nil, err
+			}
+			if _, exists := value.fields[key]; !exists {
+				value.keys = append(value.keys, key)
+			}
+			value.fields[key] = field
+			value.keyLines[key] = keyLine
+		}
+		_, err = d.dec.Token()
+	case json.Delim('['):
+		value.isArray = true
+		value.items = make([]*jsonValue, 0)
+		for d.dec.More() {
+			item, err := d.decodeValue()
+			if err != nil {
+				return nil, err
+			}
+			value.items = append(value.items, item)
+		}
+		_, err = d.dec.Token()
+	default:
+		value.scalar = token
+	}
+	return value, err
+}
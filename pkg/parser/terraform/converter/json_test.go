@@ -0,0 +1,149 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// TestDefaultConvertedJSON tests the functions [DefaultConvertedJSON] and all the methods called by them
+func TestDefaultConvertedJSON(t *testing.T) {
+	input := `{
+  "resource": {
+    "aws_s3_bucket": {
+      "b": {
+        "//": "comments are ignored",
+        "bucket": "${var.name}-bucket",
+        "acl": "public-read",
+        "tags": {
+          "Name": "My bucket"
+        },
+        "cors_rule": [
+          {
+            "allowed_methods": ["GET"]
+          }
+        ]
+      }
+    }
+  }
+}`
+
+	expected := `{
+	"resource": {
+		"aws_s3_bucket": {
+			"b": {
+				"bucket": "kics-bucket",
+				"acl": "public-read",
+				"tags": {
+					"Name": "My bucket",
+					"_kics_lines": {
+						"_kics__default": {"_kics_line": 8},
+						"_kics_Name": {"_kics_line": 9}
+					}
+				},
+				"cors_rule": [
+					{
+						"allowed_methods": ["GET"],
+						"_kics_lines": {
+							"_kics__default": {"_kics_line": 12},
+							"_kics_allowed_methods": {
+								"_kics_line": 13,
+								"_kics_arr": [{"_kics__default": {"_kics_line": 13}}]
+							}
+						}
+					}
+				],
+				"_kics_lines": {
+					"_kics__default": {"_kics_line": 4},
+					"_kics_bucket": {"_kics_line": 6},
+					"_kics_acl": {"_kics_line": 7},
+					"_kics_tags": {"_kics_line": 8},
+					"_kics_cors_rule": {
+						"_kics_line": 11,
+						"_kics_arr": [{
+							"_kics__default": {"_kics_line": 13},
+							"_kics_allowed_methods": {"_kics_line": 13}
+						}]
+					}
+				}
+			}
+		}
+	},
+	"_kics_lines": {
+		"_kics__default": {"_kics_line": 0},
+		"_kics_resource": {"_kics_line": 2}
+	}
+}`
+
+	body, err := DefaultConvertedJSON([]byte(input), false, VariableMap{
+		"var": cty.ObjectVal(map[string]cty.Value{
+			"name": cty.StringVal("kics"),
+		}),
+	})
+	require.NoError(t, err)
+	compareJSONLine(t, body, expected)
+}
+
+// TestDefaultConvertedJSON_Blocks tests the conversion of repeated blocks, blocks with count 0 and variables files
+func TestDefaultConvertedJSON_Blocks(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		variables bool
+		check     func(t *testing.T, body model.Document)
+		wantErr   bool
+	}{
+		{
+			name:  "should convert an array of bodies to a list of blocks",
+			input: `{"provider": {"aws": [{"region": "us-east-1"}, {"region": "eu-west-1", "alias": "eu"}]}}`,
+			check: func(t *testing.T, body model.Document) {
+				providers, ok := body["provider"].(model.Document)["aws"].([]interface{})
+				require.True(t, ok)
+				require.Len(t, providers, 2)
+				require.Equal(t, "eu", providers[1].(model.Document)["alias"])
+			},
+		},
+		{
+			name:  "should ignore resources with count 0",
+			input: `{"resource": {"aws_instance": {"a": {"count": 0}, "b": {"count": 2, "ami": "ami-1"}}}}`,
+			check: func(t *testing.T, body model.Document) {
+				instances := body["resource"].(model.Document)["aws_instance"].(model.Document)
+				require.NotContains(t, instances, "a")
+				require.Equal(t, int64(2), instances["b"].(model.Document)["count"])
+			},
+		},
+		{
+			name:      "should convert the properties of a variables file as attributes",
+			input:     `{"region": "us-east-1", "zones": ["a", "b"]}`,
+			variables: true,
+			check: func(t *testing.T, body model.Document) {
+				require.Equal(t, "us-east-1", body["region"])
+				require.Equal(t, []interface{}{"a", "b"}, body["zones"])
+			},
+		},
+		{
+			name:    "should fail when the root is not an object",
+			input:   `["resource"]`,
+			wantErr: true,
+		},
+		{
+			name:    "should fail with invalid JSON",
+			input:   `{"resource": `,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := DefaultConvertedJSON([]byte(tt.input), tt.variables, VariableMap{})
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			tt.check(t, body)
+		})
+	}
+}
@@ -18,20 +18,30 @@ import (
 // RetriesDefaultValue is default number of times a parser will retry to execute
 const RetriesDefaultValue = 50
 
+const (
+	tfJSONExtension     = ".tf.json"
+	tfvarsJSONExtension = ".tfvars.json"
+)
+
 // Converter returns content json, error line, error
 type Converter func(file *hcl.File, inputVariables converter.VariableMap) (model.Document, error)
 
+// JSONConverter converts a file written in the JSON configuration syntax, variables indicates a '.tfvars.json' file
+type JSONConverter func(content []byte, variables bool, inputVariables converter.VariableMap) (model.Document, error)
+
 // Parser struct that contains the function to parse file and the number of retries if something goes wrong
 type Parser struct {
-	convertFunc  Converter
-	numOfRetries int
+	convertFunc     Converter
+	convertJSONFunc JSONConverter
+	numOfRetries    int
 }
 
 // NewDefault initializes a parser with Parser default values
 func NewDefault() *Parser {
 	return &Parser{
-		numOfRetries: RetriesDefaultValue,
-		convertFunc:  converter.DefaultConverted,
+		numOfRetries:    RetriesDefaultValue,
+		convertFunc:     converter.DefaultConverted,
+		convertJSONFunc: converter.DefaultConvertedJSON,
 	}
 }
 
@@ -109,6 +119,10 @@ func parseFile(filename string, shouldReplaceDataSource bool) (*hcl.File, error)
 
 // Parse execute parser for the content in a file
 func (p *Parser) Parse(path string, content []byte) ([]model.Document, []int, error) {
+	if ext := model.FileExtension(path); ext == tfJSONExtension || ext == tfvarsJSONExtension {
+		return p.parseJSON(path, content, ext == tfvarsJSONExtension)
+	}
+
 	file, diagnostics := hclsyntax.ParseConfig(content, filepath.Base(path), hcl.Pos{Byte: 0, Line: 1, Column: 1})
 	if diagnostics != nil && diagnostics.HasErrors() && len(diagnostics.Errs()) > 0 {
 		err := diagnostics.Errs()[0]
@@ -131,9 +145,22 @@ func (p *Parser) Parse(path string, content []byte) ([]model.Document, []int, er
 	return json, linesToIgnore, errors.Wrap(parseErr, "failed terraform parse")
 }
 
+// parseJSON parses a file written in the JSON configuration syntax, which has no comments to ignore lines
+func (p *Parser) parseJSON(path string, content []byte, variables bool) ([]model.Document, []int, error) {
+	fc, err := p.convertJSONFunc(content, variables, inputVariableMap)
+	if err != nil {
+		return nil, []int{}, errors.Wrap(err, "failed terraform parse")
+	}
+	json, err := addExtraInfo([]model.Document{fc}, path)
+	if err != nil {
+		return json, []int{}, errors.Wrap(err, "failed terraform parse")
+	}
+	return json, []int{}, nil
+}
+
 // SupportedExtensions returns Terraform extensions
 func (p *Parser) SupportedExtensions() []string {
-	return []string{".tf", ".tfvars"}
+	return []string{".tf", ".tfvars", tfJSONExtension, tfvarsJSONExtension}
 }
 
 // SupportedTypes returns types supported by this parser, which are terraform
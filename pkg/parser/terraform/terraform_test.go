@@ -1,12 +1,16 @@
 package terraform
 
 import (
+	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/Checkmarx/kics/pkg/parser/terraform/converter"
 	"github.com/stretchr/testify/require"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
 )
 
 var (
@@ -67,7 +71,7 @@ func TestParser_SupportedTypes(t *testing.T) {
 // TestParser_SupportedExtensions tests the functions [SupportedExtensions()] and all the methods called by them
 func TestParser_SupportedExtensions(t *testing.T) {
 	p := &Parser{}
-	require.Equal(t, []string{".tf", ".tfvars"}, p.SupportedExtensions())
+	require.Equal(t, []string{".tf", ".tfvars", ".tf.json", ".tfvars.json"}, p.SupportedExtensions())
 }
 
 // Test_Parser tests the functions [Parser()] and all the methods called by them
@@ -82,6 +86,33 @@ func Test_Parser(t *testing.T) {
 	require.Contains(t, document[0]["resource"], "aws_s3_bucket")
 }
 
+// Test_ParserJSON tests the functions [Parser()] and all the methods called by them with the JSON configuration syntax
+func Test_ParserJSON(t *testing.T) {
+	parser := NewDefault()
+	dir := filepath.Join("..", "..", "..", "test", "fixtures", "test_terraform_json_variables")
+	_, err := parser.Resolve([]byte{}, filepath.Join(dir, "main.tf.json"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		inputVariableMap = make(converter.VariableMap)
+	})
+
+	content, err := os.ReadFile(filepath.Join(dir, "main.tf.json"))
+	require.NoError(t, err)
+	document, linesToIgnore, err := parser.Parse(filepath.Join(dir, "main.tf.json"), content)
+	require.NoError(t, err)
+	require.Empty(t, linesToIgnore)
+	require.Len(t, document, 1)
+
+	bucket := document[0]["resource"].(model.Document)["aws_s3_bucket"].(model.Document)["b"].(model.Document)
+	require.Equal(t, "bucket-us-east-1", fmt.Sprint(bucket["bucket"]))
+	acl, ok := bucket["acl"].(ctyjson.SimpleJSONValue)
+	require.True(t, ok)
+	require.Equal(t, "public-read", acl.Value.AsString())
+
+	_, _, err = parser.Parse("invalid.tf.json", []byte(`{"resource": [`))
+	require.Error(t, err)
+}
+
 // Test_Count tests resources with count set to 0
 func Test_Count(t *testing.T) {
 	parser := NewDefault()
@@ -1,15 +1,18 @@
 package terraform
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/Checkmarx/kics/pkg/parser/terraform/converter"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/rs/zerolog/log"
 	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
 )
 
 var inputVariableMap = make(converter.VariableMap)
@@ -21,6 +24,9 @@ func mergeMaps(baseMap, newItems converter.VariableMap) {
 }
 
 func setInputVariablesDefaultValues(filename string) (converter.VariableMap, error) {
+	if strings.HasSuffix(filename, tfJSONExtension) {
+		return setInputVariablesDefaultValuesJSON(filename)
+	}
 	parsedFile, err := parseFile(filename, false)
 	if err != nil || parsedFile == nil {
 		return nil, err
@@ -66,6 +72,9 @@ func checkTfvarsValid(f *hcl.File, filename string) error {
 }
 
 func getInputVariablesFromFile(filename string) (converter.VariableMap, error) {
+	if strings.HasSuffix(filename, tfvarsJSONExtension) {
+		return getInputVariablesFromJSONFile(filename)
+	}
 	parsedFile, err := parseFile(filename, false)
 	if err != nil || parsedFile == nil {
 		return nil, err
@@ -84,13 +93,83 @@ func getInputVariablesFromFile(filename string) (converter.VariableMap, error) {
 	return variables, nil
 }
 
+// setInputVariablesDefaultValuesJSON gets the default values of the variables declared in a '.tf.json' file,
+// a variable is declared by an object or by an array of objects
+func setInputVariablesDefaultValuesJSON(filename string) (converter.VariableMap, error) {
+	content, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, err
+	}
+	var config struct {
+		Variable map[string]json.RawMessage `json:"variable"`
+	}
+	if err = json.Unmarshal(content, &config); err != nil {
+		return nil, err
+	}
+	defaultValuesMap := make(converter.VariableMap)
+	for name, declaration := range config.Variable {
+		var bodies []map[string]json.RawMessage
+		if err := json.Unmarshal(declaration, &bodies); err != nil {
+			body := make(map[string]json.RawMessage)
+			if err := json.Unmarshal(declaration, &body); err != nil {
+				continue
+			}
+			bodies = append(bodies, body)
+		}
+		for _, body := range bodies {
+			if defaultValue, exists := body["default"]; exists {
+				if value, err := jsonToCty(defaultValue); err == nil {
+					defaultValuesMap[name] = value
+				}
+			}
+		}
+	}
+	return defaultValuesMap, nil
+}
+
+// getInputVariablesFromJSONFile gets the values assigned to the variables in a '.tfvars.json' file
+func getInputVariablesFromJSONFile(filename string) (converter.VariableMap, error) {
+	content, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, err
+	}
+	attrs := make(map[string]json.RawMessage)
+	if err = json.Unmarshal(content, &attrs); err != nil {
+		return nil, err
+	}
+	if _, exists := attrs["variable"]; exists {
+		return nil, fmt.Errorf("failed to get variables from %s, .tfvars.json file is used to assing values not to declare new variables", filename)
+	}
+	variables := make(converter.VariableMap)
+	for name, raw := range attrs {
+		value, err := jsonToCty(raw)
+		if err != nil {
+			return nil, err
+		}
+		variables[name] = value
+	}
+	return variables, nil
+}
+
+func jsonToCty(raw json.RawMessage) (cty.Value, error) {
+	valueType, err := ctyjson.ImpliedType(raw)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	return ctyjson.Unmarshal(raw, valueType)
+}
+
 func getInputVariables(currentPath string) {
 	variablesMap := make(converter.VariableMap)
 	tfFiles, err := filepath.Glob(filepath.Join(currentPath, "*.tf"))
 	if err != nil {
 		log.Error().Msg("Error getting .tf files")
 	}
-	for _, tfFile := range tfFiles {
+	tfJSONFiles, err := filepath.Glob(filepath.Join(currentPath, "*"+tfJSONExtension))
+	if err != nil {
+		log.Error().Msg("Error getting .tf.json files")
+	}
+	for _, tfFile := range append(tfFiles, tfJSONFiles...) {
 		variables, errDefaultValues := setInputVariablesDefaultValues(tfFile)
 		if errDefaultValues != nil {
 			log.Error().Msgf("Error getting default values from %s", tfFile)
@@ -103,12 +182,19 @@ func getInputVariables(currentPath string) {
 	if err != nil {
 		log.Error().Msg("Error getting .auto.tfvars files")
 	}
-
-	_, err = os.Stat(filepath.Join(currentPath, "terraform.tfvars"))
+	tfVarsJSONFiles, err := filepath.Glob(filepath.Join(currentPath, "*.auto"+tfvarsJSONExtension))
 	if err != nil {
-		log.Trace().Msgf("terraform.tfvars not found on %s", currentPath)
-	} else {
-		tfVarsFiles = append(tfVarsFiles, filepath.Join(currentPath, "terraform.tfvars"))
+		log.Error().Msg("Error getting .auto.tfvars.json files")
+	}
+	tfVarsFiles = append(tfVarsFiles, tfVarsJSONFiles...)
+
+	for _, name := range []string{"terraform.tfvars", "terraform" + tfvarsJSONExtension} {
+		_, err = os.Stat(filepath.Join(currentPath, name))
+		if err != nil {
+			log.Trace().Msgf("%s not found on %s", name, currentPath)
+		} else {
+			tfVarsFiles = append(tfVarsFiles, filepath.Join(currentPath, name))
+		}
 	}
 
 	for _, tfVarsFile := range tfVarsFiles {
@@ -189,3 +189,46 @@ func TestGetInputVariables(t *testing.T) {
 		inputVariableMap = make(converter.VariableMap)
 	})
 }
+
+func TestGetInputVariablesJSON(t *testing.T) {
+	tests := []inputVarTest{
+		{
+			name:     "Should load input variables from JSON configuration and variables files",
+			filename: filepath.FromSlash("../../../test/fixtures/test_terraform_json_variables"),
+			want: converter.VariableMap{
+				"var": cty.ObjectVal(map[string]cty.Value{
+					"region": cty.StringVal("us-east-1"),
+					"acl":    cty.StringVal("public-read"),
+					"tags": cty.ObjectVal(map[string]cty.Value{
+						"Environment": cty.StringVal("Dev"),
+					}),
+					"zones": cty.TupleVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+				}),
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getInputVariables(tt.filename)
+			require.Equal(t, tt.want, inputVariableMap)
+		})
+	}
+	t.Cleanup(func() {
+		inputVariableMap = make(converter.VariableMap)
+	})
+}
+
+func TestGetInputVariablesFromJSONFile(t *testing.T) {
+	_, err := getInputVariablesFromFile(filepath.Join("..", "..", "..", "test", "fixtures", "test_terraform_json_variables", "invalid.tfvars.json"))
+	require.Error(t, err)
+
+	defaultValues, err := setInputVariablesDefaultValues(
+		filepath.Join("..", "..", "..", "test", "fixtures", "test_terraform_json_variables", "variables.tf.json"))
+	require.NoError(t, err)
+	require.Equal(t, converter.VariableMap{
+		"region": cty.StringVal("us-east-1"),
+		"acl":    cty.StringVal("private"),
+	}, defaultValues)
+}
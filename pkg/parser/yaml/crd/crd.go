@@ -0,0 +1,396 @@
+package crd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ErrorsKey is the key of the document where the errors found validating the custom resource against its
+	// schema are stored
+	ErrorsKey = "_kics_crd_errors"
+
+	crdKind                = "CustomResourceDefinition"
+	crdGroup               = "apiextensions.k8s.io"
+	compositionKind        = "Composition"
+	compositionGroup       = "apiextensions.crossplane.io"
+	typeObject, typeArray  = "object", "array"
+	typeString, typeBool   = "string", "boolean"
+	typeInteger, typeFloat = "integer", "number"
+)
+
+// Registry keeps the schemas (openAPIV3Schema) of the custom resources defined by the CustomResourceDefinitions
+// registered, by API version and kind
+type Registry struct {
+	schemas map[string]*Schema
+}
+
+// Schema is the subset of the OpenAPI v3 schema of a custom resource that is used to type and validate it
+type Schema struct {
+	Type                 string             `yaml:"type"`
+	Properties           map[string]*Schema `yaml:"properties"`
+	Items                *Schema            `yaml:"items"`
+	AdditionalProperties *additional        `yaml:"additionalProperties"`
+	Required             []string           `yaml:"required"`
+	Enum                 []interface{}      `yaml:"enum"`
+	Pattern              string             `yaml:"pattern"`
+	Minimum              *float64           `yaml:"minimum"`
+	Maximum              *float64           `yaml:"maximum"`
+	PreserveUnknown      bool               `yaml:"x-kubernetes-preserve-unknown-fields"`
+	IntOrString          bool               `yaml:"x-kubernetes-int-or-string"`
+	EmbeddedResource     bool               `yaml:"x-kubernetes-embedded-resource"`
+}
+
+// additional is the 'additionalProperties' of a schema, which is either a boolean or a schema
+type additional struct {
+	Allowed bool
+	Schema  *Schema
+}
+
+// UnmarshalYAML decodes 'additionalProperties' as a boolean or as a schema
+func (a *additional) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&a.Allowed)
+	}
+	a.Allowed = true
+	return node.Decode(&a.Schema)
+}
+
+type definition struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Spec       struct {
+		Group string `yaml:"group"`
+		Names struct {
+			Kind string `yaml:"kind"`
+		} `yaml:"names"`
+		Versions []struct {
+			Name   string `yaml:"name"`
+			Schema struct {
+				OpenAPIV3Schema *Schema `yaml:"openAPIV3Schema"`
+			} `yaml:"schema"`
+		} `yaml:"versions"`
+	} `yaml:"spec"`
+}
+
+// Error is an error found validating a custom resource, Path is the path of the field and Message describes the error
+type Error struct {
+	Path    []string
+	Message string
+}
+
+// NewRegistry creates a Registry with the CustomResourceDefinitions found in the files and directories given
+func NewRegistry(paths []string) (*Registry, error) {
+	registry := &Registry{schemas: make(map[string]*Schema)}
+	for _, path := range paths {
+		err := filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || !isManifest(file) {
+				return nil
+			}
+			content, err := os.ReadFile(filepath.Clean(file))
+			if err != nil {
+				return err
+			}
+			registry.Register(file, content)
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read CustomResourceDefinitions from %s", path)
+		}
+	}
+	return registry, nil
+}
+
+// Register registers the CustomResourceDefinitions of the content given, documents of other kinds are ignored
+func (r *Registry) Register(file string, content []byte) {
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var def definition
+		if err := dec.Decode(&def); err != nil {
+			if err.Error() != "EOF" {
+				log.Warn().Msgf("Failed to parse CustomResourceDefinition %s: %s", file, err)
+			}
+			return
+		}
+		if def.Kind != crdKind || !strings.HasPrefix(def.APIVersion, crdGroup+"/") {
+			continue
+		}
+		for _, version := range def.Spec.Versions {
+			if version.Schema.OpenAPIV3Schema == nil {
+				continue
+			}
+			key := schemaKey(def.Spec.Group+"/"+version.Name, def.Spec.Names.Kind)
+			r.schemas[key] = version.Schema.OpenAPIV3Schema
+			log.Debug().Msgf("Registered CustomResourceDefinition %s from %s", key, file)
+		}
+	}
+}
+
+// Len returns the number of API versions and kinds registered
+func (r *Registry) Len() int {
+	return len(r.schemas)
+}
+
+// Apply types the custom resource of the document according to the schema of its API version and kind and stores
+// the errors found validating it in the document. The managed resources defined as bases of the resources of a
+// Crossplane Composition are also typed and validated
+func (r *Registry) Apply(doc map[string]interface{}) {
+	if r == nil {
+		return
+	}
+	validationErrors := r.apply(doc, []string{})
+
+	apiVersion, _ := doc["apiVersion"].(string)
+	if doc["kind"] == compositionKind && strings.HasPrefix(apiVersion, compositionGroup+"/") {
+		spec, _ := doc["spec"].(map[string]interface{})
+		resources, _ := spec["resources"].([]interface{})
+		for idx := range resources {
+			resource, _ := resources[idx].(map[string]interface{})
+			if base, ok := resource["base"].(map[string]interface{}); ok {
+				validationErrors = append(validationErrors,
+					r.apply(base, []string{"spec", "resources", strconv.Itoa(idx), "base"})...)
+			}
+		}
+	}
+
+	if len(validationErrors) == 0 {
+		return
+	}
+	stored := make([]interface{}, 0, len(validationErrors))
+	for _, validationError := range validationErrors {
+		path := make([]interface{}, 0, len(validationError.Path))
+		for _, element := range validationError.Path {
+			path = append(path, element)
+		}
+		stored = append(stored, map[string]interface{}{
+			"path":    path,
+			"field":   fieldName(validationError.Path),
+			"message": validationError.Message,
+		})
+	}
+	doc[ErrorsKey] = stored
+}
+
+func (r *Registry) apply(resource map[string]interface{}, path []string) []Error {
+	apiVersion, _ := resource["apiVersion"].(string)
+	kind, _ := resource["kind"].(string)
+	schema, ok := r.schemas[schemaKey(apiVersion, kind)]
+	if !ok {
+		return nil
+	}
+	v := &validator{errors: make([]Error, 0)}
+	v.validateObject(resource, schema, path, true)
+	return v.errors
+}
+
+type validator struct {
+	errors []Error
+}
+
+func (v *validator) add(path []string, format string, args ...interface{}) {
+	v.errors = append(v.errors, Error{
+		Path:    append(make([]string, 0, len(path)), path...),
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// validate validates the value against the schema and returns the value converted to the type of the schema
+func (v *validator) validate(value interface{}, schema *Schema, path []string) interface{} {
+	if schema == nil || value == nil {
+		return value
+	}
+	value = convert(value, schema)
+
+	switch schema.Type {
+	case typeObject:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			v.add(path, "%s must be of type object", fieldName(path))
+			return value
+		}
+		v.validateObject(object, schema, path, schema.EmbeddedResource)
+	case typeArray:
+		array, ok := value.([]interface{})
+		if !ok {
+			v.add(path, "%s must be of type array", fieldName(path))
+			return value
+		}
+		for idx := range array {
+			array[idx] = v.validate(array[idx], schema.Items, append(path, strconv.Itoa(idx)))
+		}
+	default:
+		v.validateScalar(value, schema, path)
+	}
+	return value
+}
+
+func (v *validator) validateObject(object map[string]interface{}, schema *Schema, path []string, resource bool) {
+	for _, required := range schema.Required {
+		if _, ok := object[required]; !ok {
+			v.add(path, "%s is required", fieldName(append(path, required)))
+		}
+	}
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fieldPath := append(append(make([]string, 0, len(path)+1), path...), key)
+		if property, ok := schema.Properties[key]; ok {
+			object[key] = v.validate(object[key], property, fieldPath)
+			continue
+		}
+		switch {
+		case strings.HasPrefix(key, "_kics"), resource && (key == "apiVersion" || key == "kind" || key == "metadata"):
+		case schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil:
+			object[key] = v.validate(object[key], schema.AdditionalProperties.Schema, fieldPath)
+		case schema.PreserveUnknown, schema.AdditionalProperties != nil && schema.AdditionalProperties.Allowed:
+		case len(schema.Properties) == 0 && schema.AdditionalProperties == nil && !resource:
+		default:
+			v.add(fieldPath, "%s is not defined in the schema", fieldName(fieldPath))
+		}
+	}
+}
+
+func (v *validator) validateScalar(value interface{}, schema *Schema, path []string) {
+	if !schema.IntOrString && schema.Type != "" && scalarType(value) != schema.Type &&
+		!(schema.Type == typeFloat && scalarType(value) == typeInteger) {
+		v.add(path, "%s must be of type %s", fieldName(path), schema.Type)
+		return
+	}
+	if len(schema.Enum) > 0 && !inEnum(value, schema.Enum) {
+		v.add(path, "%s must be one of %v", fieldName(path), schema.Enum)
+	}
+	if str, ok := value.(string); ok && schema.Pattern != "" {
+		if pattern, err := regexp.Compile(schema.Pattern); err == nil && !pattern.MatchString(str) {
+			v.add(path, "%s must match the pattern %s", fieldName(path), schema.Pattern)
+		}
+	}
+	if number, ok := toFloat(value); ok {
+		if schema.Minimum != nil && number < *schema.Minimum {
+			v.add(path, "%s must be greater than or equal to %v", fieldName(path), *schema.Minimum)
+		}
+		if schema.Maximum != nil && number > *schema.Maximum {
+			v.add(path, "%s must be less than or equal to %v", fieldName(path), *schema.Maximum)
+		}
+	}
+}
+
+// convert converts the scalar values written with other type (e.g. quoted booleans and numbers) to the type of
+// the schema, values that can not be converted are kept
+func convert(value interface{}, schema *Schema) interface{} {
+	if schema.IntOrString {
+		return value
+	}
+	str, isString := value.(string)
+	switch schema.Type {
+	case typeBool:
+		if parsed, err := strconv.ParseBool(str); isString && err == nil {
+			return parsed
+		}
+	case typeInteger:
+		if parsed, err := strconv.Atoi(str); isString && err == nil {
+			return parsed
+		}
+		if number, ok := value.(float64); ok && number == float64(int(number)) {
+			return int(number)
+		}
+	case typeFloat:
+		if parsed, err := strconv.ParseFloat(str, 64); isString && err == nil {
+			return parsed
+		}
+	case typeString:
+		switch value.(type) {
+		case bool, int, int64, float64:
+			return fmt.Sprintf("%v", value)
+		}
+	}
+	return value
+}
+
+func scalarType(value interface{}) string {
+	switch value.(type) {
+	case bool:
+		return typeBool
+	case int, int64, uint64:
+		return typeInteger
+	case float64:
+		return typeFloat
+	case string:
+		return typeString
+	default:
+		return ""
+	}
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch number := value.(type) {
+	case int:
+		return float64(number), true
+	case int64:
+		return float64(number), true
+	case uint64:
+		return float64(number), true
+	case float64:
+		return number, true
+	default:
+		return 0, false
+	}
+}
+
+func inEnum(value interface{}, enum []interface{}) bool {
+	for _, allowed := range enum {
+		if fmt.Sprintf("%v", allowed) == fmt.Sprintf("%v", value) {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldName returns the name of the field of the path, with the indexes of the arrays between brackets
+func fieldName(path []string) string {
+	var name strings.Builder
+	for _, element := range path {
+		if _, err := strconv.Atoi(element); err == nil {
+			name.WriteString("[" + element + "]")
+			continue
+		}
+		if name.Len() > 0 {
+			name.WriteString(".")
+		}
+		name.WriteString(element)
+	}
+	if name.Len() == 0 {
+		return "resource"
+	}
+	return name.String()
+}
+
+func schemaKey(apiVersion, kind string) string {
+	return apiVersion + "/" + kind
+}
+
+func isManifest(file string) bool {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	default:
+		return false
+	}
+}
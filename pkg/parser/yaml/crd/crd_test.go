@@ -0,0 +1,149 @@
+package crd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+var crdFixturesDir = filepath.FromSlash("../../../../test/fixtures/crds")
+
+func decodeDocument(t *testing.T, content string) map[string]interface{} {
+	doc := map[string]interface{}{}
+	require.NoError(t, yaml.Unmarshal([]byte(content), &doc))
+	return doc
+}
+
+// TestNewRegistry tests the functions [NewRegistry()] and all the methods called by them
+func TestNewRegistry(t *testing.T) {
+	registry, err := NewRegistry([]string{crdFixturesDir})
+	require.NoError(t, err)
+	require.Equal(t, 2, registry.Len())
+	require.Contains(t, registry.schemas, "s3.aws.crossplane.io/v1beta1/Bucket")
+	require.Contains(t, registry.schemas, "database.aws.crossplane.io/v1beta1/RDSInstance")
+
+	_, err = NewRegistry([]string{filepath.Join(crdFixturesDir, "missing")})
+	require.Error(t, err)
+}
+
+// TestRegistry_Apply tests the functions [Apply()] and all the methods called by them
+func TestRegistry_Apply(t *testing.T) {
+	registry, err := NewRegistry([]string{crdFixturesDir})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+		check   func(t *testing.T, doc map[string]interface{})
+	}{
+		{
+			name: "typed_values",
+			content: `apiVersion: database.aws.crossplane.io/v1beta1
+kind: RDSInstance
+metadata:
+  name: orders
+spec:
+  forProvider:
+    dbInstanceClass: db.t3.small
+    engine: postgres
+    engineVersion: 13
+    allocatedStorage: "50"
+    publiclyAccessible: "false"
+`,
+			check: func(t *testing.T, doc map[string]interface{}) {
+				forProvider := doc["spec"].(map[string]interface{})["forProvider"].(map[string]interface{})
+				require.Equal(t, false, forProvider["publiclyAccessible"])
+				require.Equal(t, 50, forProvider["allocatedStorage"])
+				require.Equal(t, "13", forProvider["engineVersion"])
+			},
+		},
+		{
+			name: "invalid_values",
+			content: `apiVersion: database.aws.crossplane.io/v1beta1
+kind: RDSInstance
+metadata:
+  name: orders
+spec:
+  forProvider:
+    dbInstanceClass: db.t3.small
+    allocatedStorage: 10
+    publiclyAccessible: sometimes
+  deletionPolicy: Keep
+  unknown: true
+`,
+			want: []string{
+				"spec.forProvider.engine is required",
+				"spec.forProvider.allocatedStorage must be greater than or equal to 20",
+				"spec.forProvider.publiclyAccessible must be of type boolean",
+				"spec.deletionPolicy must be one of [Orphan Delete]",
+				"spec.unknown is not defined in the schema",
+			},
+		},
+		{
+			name: "unknown_kind",
+			content: `apiVersion: s3.aws.crossplane.io/v1alpha1
+kind: Bucket
+metadata:
+  name: assets
+spec:
+  unknown: true
+`,
+		},
+		{
+			name: "composition",
+			content: `apiVersion: apiextensions.crossplane.io/v1
+kind: Composition
+metadata:
+  name: site
+spec:
+  resources:
+    - name: bucket
+      base:
+        apiVersion: s3.aws.crossplane.io/v1beta1
+        kind: Bucket
+        spec:
+          forProvider:
+            acl: public
+            locationConstraint: eu-west-1
+`,
+			want: []string{
+				"spec.resources[0].base.spec.forProvider.acl must be one of [private public-read public-read-write authenticated-read]",
+			},
+			check: func(t *testing.T, doc map[string]interface{}) {
+				crdError := doc[ErrorsKey].([]interface{})[0].(map[string]interface{})
+				require.Equal(t, []interface{}{"spec", "resources", "0", "base", "spec", "forProvider", "acl"}, crdError["path"])
+				require.Equal(t, "spec.resources[0].base.spec.forProvider.acl", crdError["field"])
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := decodeDocument(t, tt.content)
+			registry.Apply(doc)
+			if tt.check != nil {
+				tt.check(t, doc)
+			}
+			if len(tt.want) == 0 {
+				require.NotContains(t, doc, ErrorsKey)
+				return
+			}
+			messages := make([]string, 0, len(tt.want))
+			for _, crdError := range doc[ErrorsKey].([]interface{}) {
+				messages = append(messages, crdError.(map[string]interface{})["message"].(string))
+			}
+			require.ElementsMatch(t, tt.want, messages)
+		})
+	}
+}
+
+// TestRegistry_Apply_Nil tests that a nil registry does not change the document
+func TestRegistry_Apply_Nil(t *testing.T) {
+	var registry *Registry
+	doc := decodeDocument(t, "apiVersion: v1\nkind: ConfigMap\n")
+	registry.Apply(doc)
+	require.NotContains(t, doc, ErrorsKey)
+}
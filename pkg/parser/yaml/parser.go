@@ -2,15 +2,28 @@ package json
 
 import (
 	"bytes"
+	"regexp"
+	"strings"
 
 	"github.com/Checkmarx/kics/pkg/model"
 	"github.com/Checkmarx/kics/pkg/parser/utils"
+	"github.com/Checkmarx/kics/pkg/parser/yaml/crd"
+	"github.com/Checkmarx/kics/pkg/resolver/ansible"
+	"github.com/Checkmarx/kics/pkg/resolver/openapi"
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v3"
 )
 
-// Parser defines a parser type
+var vaultTagRegex = regexp.MustCompile(`!vault\s*[|>]?[-+]?\s*$`)
+
+// Parser defines a parser type, Vault decrypts the files encrypted with Ansible Vault and '!vault' strings
+// when it is set and CRDs types and validates the custom resources of the CustomResourceDefinitions registered
+// The '$ref' pointers of OpenAPI documents are only followed to files inside ScanPaths (the directory of the
+// document when empty)
 type Parser struct {
+	Vault     *ansible.Vault
+	CRDs      *crd.Registry
+	ScanPaths []string
 }
 
 // Resolve - replace or modifies in-memory content before parsing
@@ -21,14 +34,31 @@ func (p *Parser) Resolve(fileContent []byte, filename string) (*[]byte, error) {
 // Parse parses yaml/yml file and returns it as a Document
 func (p *Parser) Parse(filePath string, fileContent []byte) ([]model.Document, []int, error) {
 	var documents []model.Document
-	dec := yaml.NewDecoder(bytes.NewReader(fileContent))
 
-	doc := &model.Document{}
-	for dec.Decode(doc) == nil {
+	content, vaultedFile := fileContent, false
+	if p.Vault != nil && ansible.IsVaulted(fileContent) {
+		decrypted, err := p.Vault.Decrypt(fileContent)
+		if err != nil {
+			return nil, []int{}, errors.Wrap(err, "failed to decrypt Ansible Vault file")
+		}
+		content, vaultedFile = decrypted, true
+	}
+
+	vaulted := make([]*model.VaultedData, 0)
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+
+	node := &yaml.Node{}
+	for dec.Decode(node) == nil {
+		vaultedData := p.decrypt(node, string(content), vaultedFile)
+		doc := &model.Document{}
+		if node.Decode(doc) != nil {
+			break
+		}
 		if doc != nil {
 			documents = append(documents, *doc)
+			vaulted = append(vaulted, vaultedData)
 		}
-		doc = &model.Document{}
+		node = &yaml.Node{}
 	}
 
 	if len(documents) == 0 {
@@ -38,7 +68,92 @@ func (p *Parser) Parse(filePath string, fileContent []byte) ([]model.Document, [
 	linesToIgnore := model.NewIgnore.GetLines()
 	model.NewIgnore.Reset()
 
-	return convertKeysToString(addExtraInfo(documents, filePath)), linesToIgnore, nil
+	documents, resolverVaulted := p.resolveAnsible(p.resolveRefs(convertKeysToString(addExtraInfo(documents, filePath)), filePath), filePath)
+	// files read by the resolvers must not add their lines to ignore to the next file
+	model.NewIgnore.Reset()
+
+	for idx := range documents {
+		addVaulted(documents[idx], vaulted[idx], resolverVaulted)
+		p.CRDs.Apply(documents[idx])
+	}
+
+	return documents, linesToIgnore, nil
+}
+
+// decrypt decrypts the '!vault' strings of the YAML document node and returns the decrypted content,
+// nil if the document has no vaulted content. When the whole file is vaulted all the content is decrypted
+func (p *Parser) decrypt(node *yaml.Node, content string, vaultedFile bool) *model.VaultedData {
+	if p.Vault == nil {
+		return nil
+	}
+	if vaultedFile {
+		lines := strings.Split(content, "\n")
+		data := &model.VaultedData{
+			Content: content,
+			Lines:   make([]int, len(lines)),
+			Values:  ansible.MarkVaulted(node),
+			File:    true,
+		}
+		for idx := range data.Lines {
+			data.Lines[idx] = 1
+		}
+		return data
+	}
+
+	values := p.Vault.DecryptValues(node)
+	if len(values) == 0 {
+		return nil
+	}
+	data := &model.VaultedData{
+		Lines:  make([]int, 0),
+		Values: make([]string, 0, len(values)),
+	}
+	lines := make([]string, 0)
+	source := strings.Split(content, "\n")
+	for _, value := range values {
+		if ansible.IsRedactable(value.Value) {
+			data.Values = append(data.Values, value.Value)
+		}
+		valueLines := strings.Split(value.Value, "\n")
+		// each line of the value is checked for secrets with its key, in the line of the key
+		for _, line := range valueLines {
+			if value.Key != "" {
+				line = value.Key + ": " + line
+			}
+			lines = append(lines, line)
+			data.Lines = append(data.Lines, value.Line)
+		}
+		if value.Line > 0 && value.Line <= len(source) {
+			source[value.Line-1] = vaultTagRegex.ReplaceAllLiteralString(source[value.Line-1], valueLines[0])
+		}
+	}
+	data.Content = strings.Join(lines, "\n")
+	data.Source = strings.Join(source, "\n")
+	return data
+}
+
+// addVaulted stores the vaulted content of the document, and the values decrypted by the resolvers, in the document
+func addVaulted(doc model.Document, vaulted *model.VaultedData, resolverVaulted []string) {
+	if vaulted == nil && len(resolverVaulted) == 0 {
+		return
+	}
+	if vaulted == nil {
+		vaulted = &model.VaultedData{}
+	}
+	values := append(append(make([]string, 0), vaulted.Values...), resolverVaulted...)
+	vaulted.Values = make([]string, 0, len(values))
+	for _, value := range values {
+		vaulted.Values = append(vaulted.Values, value)
+		// the lines of multiline values can be found in the results on their own
+		if strings.Contains(value, "\n") {
+			for _, line := range strings.Split(value, "\n") {
+				if line = strings.TrimSpace(line); ansible.IsRedactable(line) {
+					vaulted.Values = append(vaulted.Values, line)
+				}
+			}
+		}
+	}
+	doc[model.VaultedKey] = vaulted
 }
 
 // convertKeysToString goes through every document to convert map[interface{}]interface{}
@@ -85,7 +200,7 @@ func (p *Parser) SupportedExtensions() []string {
 
 // SupportedTypes returns types supported by this parser, which are ansible, cloudFormation, k8s
 func (p *Parser) SupportedTypes() []string {
-	return []string{"Ansible", "CloudFormation", "Kubernetes", "OpenAPI"}
+	return []string{"Ansible", "CloudFormation", "Kubernetes", "OpenAPI", "AsyncAPI", "GraphQL", "DockerCompose", "CICD", "Pulumi"}
 }
 
 // GetKind returns YAML constant kind
@@ -136,6 +251,25 @@ func addExtraInfo(documents []model.Document, filePath string) []model.Document
 	return documents
 }
 
+// resolveRefs dereferences the '$ref' pointers of OpenAPI documents
+func (p *Parser) resolveRefs(documents []model.Document, filePath string) []model.Document {
+	resolver := openapi.NewResolver(p.ScanPaths...)
+	for idx := range documents {
+		documents[idx] = resolver.Resolve(documents[idx], filePath)
+	}
+	return documents
+}
+
+// resolveAnsible adds the tasks of the roles and included files to Ansible playbooks and renders their variables,
+// the values decrypted from the files read are returned
+func (p *Parser) resolveAnsible(documents []model.Document, filePath string) ([]model.Document, []string) {
+	resolver := ansible.NewResolver(p.Vault)
+	for idx := range documents {
+		documents[idx] = resolver.Resolve(documents[idx], filePath)
+	}
+	return documents, resolver.Vaulted()
+}
+
 // GetCommentToken return the comment token of YAML - #
 func (p *Parser) GetCommentToken() string {
 	return "#"
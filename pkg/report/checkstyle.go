@@ -0,0 +1,50 @@
+package report
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+
+	reportModel "github.com/Checkmarx/kics/pkg/report/model"
+)
+
+const xmlExtension = ".xml"
+
+// PrintCheckstyleReport creates a report file on the Checkstyle XML format
+func PrintCheckstyleReport(path, filename string, body interface{}) error {
+	filename = strings.ReplaceAll(filename, ".checkstyle", "")
+	if !strings.HasSuffix(filename, xmlExtension) {
+		filename += xmlExtension
+	}
+	if !strings.HasPrefix(filename, "checkstyle-") {
+		filename = "checkstyle-" + filename
+	}
+	checkstyleReport := reportModel.NewCheckstyleReport()
+	if body != "" {
+		summary, err := getSummary(body)
+		if err != nil {
+			return err
+		}
+
+		for idxQuery := range summary.Queries {
+			for idxFile := range summary.Queries[idxQuery].Files {
+				checkstyleReport.BuildCheckstyleError(&summary.Queries[idxQuery], &summary.Queries[idxQuery].Files[idxFile])
+			}
+		}
+	}
+	content, err := xml.MarshalIndent(checkstyleReport, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	fullPath := filepath.Join(path, filename)
+	f, err := os.OpenFile(filepath.Clean(fullPath), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer closeFile(fullPath, filename, f)
+
+	_, err = f.WriteString(xml.Header + string(content) + "\n")
+	return err
+}
@@ -0,0 +1,15 @@
+package report
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestPrintCheckstyleReport tests the functions [PrintCheckstyleReport()] and all the methods called by them
+func TestPrintCheckstyleReport(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, PrintCheckstyleReport(dir, "results", goldenSummaryMock))
+	requireGolden(t, "checkstyle-results.xml", filepath.Join(dir, "checkstyle-results.xml"))
+}
@@ -114,3 +114,16 @@ func getSummary(body interface{}) (sum model.Summary, err error) {
 
 	return summary, nil
 }
+
+// getSummaryWithLines returns the summary of the body keeping the code lines of the results, which are not part
+// of the JSON of the summary, the summary may be the body itself so it must not be modified
+func getSummaryWithLines(body interface{}) (model.Summary, error) {
+	switch summary := body.(type) {
+	case *model.Summary:
+		return *summary, nil
+	case model.Summary:
+		return summary, nil
+	default:
+		return getSummary(body)
+	}
+}
@@ -0,0 +1,158 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	htmlTmpl "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	textTmpl "text/template"
+)
+
+const customReportExtension = ".tmpl"
+
+var customReportNameRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// CustomReport is a report format defined by a Go template, rendered with the summary of the scan
+// The template is rendered with html/template when its file is an HTML template (e.g. 'page.html.tmpl'),
+// otherwise with text/template
+type CustomReport struct {
+	Name      string
+	Path      string
+	extension string
+	execute   func(w io.Writer, data interface{}) error
+}
+
+// NewCustomReport loads the template of a report defined as 'name=path', the file of the report is named after
+// the output name and the name of the report, with the extension of the template without '.tmpl'
+func NewCustomReport(definition string) (*CustomReport, error) {
+	idx := strings.Index(definition, "=")
+	if idx <= 0 || idx == len(definition)-1 {
+		return nil, fmt.Errorf("invalid report template (--report-template) %s, expected <name>=<path>", definition)
+	}
+	report := &CustomReport{
+		Name: definition[:idx],
+		Path: definition[idx+1:],
+	}
+	if !customReportNameRegex.MatchString(report.Name) {
+		return nil, fmt.Errorf("invalid report template name %s, only letters, digits, '-' and '_' are allowed", report.Name)
+	}
+
+	content, err := os.ReadFile(report.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report template %s: %w", report.Path, err)
+	}
+	report.extension = filepath.Ext(strings.TrimSuffix(filepath.Base(report.Path), customReportExtension))
+
+	name := filepath.Base(report.Path)
+	if ext := strings.ToLower(report.extension); ext == ".html" || ext == ".htm" {
+		t, err := htmlTmpl.New(name).Funcs(htmlTmpl.FuncMap(customReportFuncs())).Parse(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse report template %s: %w", report.Path, err)
+		}
+		report.execute = t.Execute
+	} else {
+		t, err := textTmpl.New(name).Funcs(textTmpl.FuncMap(customReportFuncs())).Parse(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse report template %s: %w", report.Path, err)
+		}
+		report.execute = t.Execute
+	}
+	return report, nil
+}
+
+// NewCustomReports loads the templates of the reports, their names must be unique
+func NewCustomReports(definitions []string) ([]*CustomReport, error) {
+	reports := make([]*CustomReport, 0, len(definitions))
+	names := make(map[string]bool, len(definitions))
+	for _, definition := range definitions {
+		report, err := NewCustomReport(definition)
+		if err != nil {
+			return nil, err
+		}
+		if names[report.Name] {
+			return nil, fmt.Errorf("duplicated report template name %s", report.Name)
+		}
+		names[report.Name] = true
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// Print renders the summary with the template on the file '<filename>-<name><extension>' of the path
+func (r *CustomReport) Print(path, filename string, body interface{}) error {
+	summary, err := getSummaryWithLines(body)
+	if err != nil {
+		return err
+	}
+
+	var buffer bytes.Buffer
+	if err := r.execute(&buffer, summary); err != nil {
+		return fmt.Errorf("failed to render report template %s: %w", r.Path, err)
+	}
+
+	filename = filename + "-" + r.Name + r.extension
+	fullPath := filepath.Join(path, filename)
+	f, err := os.OpenFile(filepath.Clean(fullPath), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer closeFile(fullPath, filename, f)
+
+	_, err = f.Write(buffer.Bytes())
+	return err
+}
+
+// customReportFuncs returns the functions of the custom templates: the functions of the HTML report that do not
+// include its assets, and the functions escaping the values of JSON, CSV and XML documents. The functions are
+// built for each report, since the HTML report adds its functions to the shared ones
+func customReportFuncs() map[string]interface{} {
+	return map[string]interface{}{
+		"lower":          strings.ToLower,
+		"sprintf":        fmt.Sprintf,
+		"severity":       getSeverities,
+		"getCurrentTime": getCurrentTime,
+		"trimSpaces":     trimSpaces,
+		"toString":       toString,
+		"getPaths":       getPaths,
+		"getPlatforms":   getPlatforms,
+		"getVersion":     getVersion,
+		"toJSON":         toJSON,
+		"csvEscape":      csvEscape,
+		"xmlEscape":      xmlEscape,
+	}
+}
+
+// toJSON returns the JSON of the value
+func toJSON(value interface{}) (string, error) {
+	content, err := json.Marshal(value)
+	return string(content), err
+}
+
+// csvEscape returns the values as a CSV record, quoted when needed and without the line break
+func csvEscape(values ...interface{}) (string, error) {
+	record := make([]string, 0, len(values))
+	for _, value := range values {
+		record = append(record, toString(value))
+	}
+	var buffer bytes.Buffer
+	w := csv.NewWriter(&buffer)
+	if err := w.Write(record); err != nil {
+		return "", err
+	}
+	w.Flush()
+	return strings.TrimSuffix(buffer.String(), "\n"), w.Error()
+}
+
+// xmlEscape returns the value escaped as the text or the attribute of an XML element
+func xmlEscape(value interface{}) (string, error) {
+	var buffer bytes.Buffer
+	err := xml.EscapeText(&buffer, []byte(toString(value)))
+	return buffer.String(), err
+}
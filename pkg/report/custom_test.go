@@ -0,0 +1,113 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Checkmarx/kics/test"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCustomReport tests the functions [NewCustomReport()], [Print()] and all the methods called by them
+func TestCustomReport(t *testing.T) {
+	dir := t.TempDir()
+	templates := map[string]string{
+		"export.csv.tmpl": "query,severity,file,line\n" +
+			"{{range .Queries}}{{$q := .}}{{range .Files}}{{csvEscape $q.QueryName $q.Severity .FileName .Line}}\n{{end}}{{end}}",
+		"page.html.tmpl": "<h1>{{.TotalCounter}} results</h1>{{range .Queries}}<p>{{printf \"<%s>\" (lower (toString .Severity))}} {{.QueryName}}</p>{{end}}",
+		"ticket.tmpl":    `{"summary": {{toJSON (index .Queries 0).QueryName}}, "total": {{.TotalCounter}}}`,
+		"issues.xml.tmpl": `<issues>{{range .Queries}}<issue name="{{xmlEscape .QueryName}}" ` +
+			`severity="{{.Severity}}"/>{{end}}</issues>`,
+	}
+	for name, content := range templates {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+
+	reports, err := NewCustomReports([]string{
+		"export=" + filepath.Join(dir, "export.csv.tmpl"),
+		"confluence=" + filepath.Join(dir, "page.html.tmpl"),
+		"ticket=" + filepath.Join(dir, "ticket.tmpl"),
+		"issues=" + filepath.Join(dir, "issues.xml.tmpl"),
+	})
+	require.NoError(t, err)
+	for _, report := range reports {
+		require.NoError(t, report.Print(dir, "results", test.ComplexSummaryMock))
+	}
+
+	want := map[string]string{
+		"results-export.csv": "query,severity,file,line\n" +
+			"ALB protocol is HTTP,HIGH,positive.tf,25\nALB protocol is HTTP,HIGH,positive.tf,19\n" +
+			"AmazonMQ Broker Encryption Disabled,MEDIUM,positive.tf,1\n",
+		"results-confluence.html": "<h1>3 results</h1><p>&lt;high&gt; ALB protocol is HTTP</p>" +
+			"<p>&lt;medium&gt; AmazonMQ Broker Encryption Disabled</p>",
+		"results-ticket":     `{"summary": "ALB protocol is HTTP", "total": 3}`,
+		"results-issues.xml": `<issues><issue name="ALB protocol is HTTP" severity="HIGH"/><issue name="AmazonMQ Broker Encryption Disabled" severity="MEDIUM"/></issues>`,
+	}
+	for name, content := range want {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		require.Equal(t, content, string(got), name)
+	}
+}
+
+// TestNewCustomReports_Invalid tests the errors of the definitions and the templates of the reports
+func TestNewCustomReports_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.tmpl")
+	invalid := filepath.Join(dir, "invalid.tmpl")
+	require.NoError(t, os.WriteFile(valid, []byte("{{.TotalCounter}}"), 0600))
+	require.NoError(t, os.WriteFile(invalid, []byte("{{.TotalCounter"), 0600))
+
+	tests := []struct {
+		name        string
+		definitions []string
+		wantErr     string
+	}{
+		{name: "no path", definitions: []string{"report"}, wantErr: "expected <name>=<path>"},
+		{name: "empty name", definitions: []string{"=" + valid}, wantErr: "expected <name>=<path>"},
+		{name: "invalid name", definitions: []string{"my/report=" + valid}, wantErr: "invalid report template name"},
+		{name: "missing file", definitions: []string{"report=" + filepath.Join(dir, "missing.tmpl")}, wantErr: "failed to read"},
+		{name: "invalid template", definitions: []string{"report=" + invalid}, wantErr: "failed to parse"},
+		{name: "duplicated name", definitions: []string{"report=" + valid, "report=" + valid}, wantErr: "duplicated"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCustomReports(tt.definitions)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+// Test_customReportEscaping tests the escaping functions of the custom templates
+func Test_customReportEscaping(t *testing.T) {
+	record, err := csvEscape("a,b", `say "hi"`, "line\nbreak", 3)
+	require.NoError(t, err)
+	require.Equal(t, "\"a,b\",\"say \"\"hi\"\"\",\"line\nbreak\",3", record)
+
+	text, err := xmlEscape(`<a href="x">&'</a>`)
+	require.NoError(t, err)
+	require.Equal(t, "&lt;a href=&#34;x&#34;&gt;&amp;&#39;&lt;/a&gt;", text)
+
+	content, err := toJSON(map[string]string{"key": "a \"quoted\" value"})
+	require.NoError(t, err)
+	require.Equal(t, `{"key":"a \"quoted\" value"}`, content)
+}
+
+// Test_customReportFuncs tests that the functions of the custom templates do not depend on the functions the
+// HTML report adds to the shared ones
+func Test_customReportFuncs(t *testing.T) {
+	funcs := customReportFuncs()
+	for name := range templateFuncs {
+		if name != "includeSVG" && name != "includeCSS" && name != "includeJS" {
+			require.Contains(t, funcs, name)
+		}
+	}
+
+	templateFuncs["includeSVG"] = includeSVG
+	t.Cleanup(func() {
+		delete(templateFuncs, "includeSVG")
+	})
+	require.NotContains(t, customReportFuncs(), "includeSVG")
+}
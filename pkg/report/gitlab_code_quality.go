@@ -0,0 +1,34 @@
+package report
+
+import (
+	"strings"
+
+	reportModel "github.com/Checkmarx/kics/pkg/report/model"
+)
+
+// PrintGitlabCodeQualityReport creates a report file on the Gitlab Code Quality format (Code Climate issues)
+func PrintGitlabCodeQualityReport(path, filename string, body interface{}) error {
+	filename = strings.ReplaceAll(filename, ".glcodequality", "")
+	if !strings.HasSuffix(filename, jsonExtension) {
+		filename += jsonExtension
+	}
+	if !strings.HasPrefix(filename, "gl-code-quality-") {
+		filename = "gl-code-quality-" + filename
+	}
+	if body != "" {
+		summary, err := getSummary(body)
+		if err != nil {
+			return err
+		}
+
+		codeQualityReport := reportModel.NewGitlabCodeQualityReport()
+		for idxQuery := range summary.Queries {
+			for idxFile := range summary.Queries[idxQuery].Files {
+				codeQualityReport.BuildGitlabCodeQualityIssue(&summary.Queries[idxQuery], &summary.Queries[idxQuery].Files[idxFile])
+			}
+		}
+		body = codeQualityReport
+	}
+
+	return ExportJSONReport(path, filename, body)
+}
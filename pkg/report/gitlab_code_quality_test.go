@@ -0,0 +1,15 @@
+package report
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestPrintGitlabCodeQualityReport tests the functions [PrintGitlabCodeQualityReport()] and all the methods called by them
+func TestPrintGitlabCodeQualityReport(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, PrintGitlabCodeQualityReport(dir, "results", goldenSummaryMock))
+	requireGolden(t, "gl-code-quality-results.json", filepath.Join(dir, "gl-code-quality-results.json"))
+}
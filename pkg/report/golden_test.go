@@ -0,0 +1,96 @@
+package report
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files of the reports")
+
+// goldenSummaryMock has results of every severity, on several files, with and without similarity ID
+var goldenSummaryMock = model.Summary{
+	Queries: model.QueryResultSlice{
+		{
+			QueryName:   "ALB protocol is HTTP",
+			QueryID:     "de7f5e83-da88-4046-871f-ea18504b1d43",
+			Description: "ALB Listener should use HTTPS",
+			Severity:    model.SeverityHigh,
+			Platform:    "Terraform",
+			Files: []model.VulnerableFile{
+				{
+					FileName:       filepath.FromSlash("modules/alb/main.tf"),
+					SimilarityID:   "6bc8f7a6a6f4b1b8f7d4e1b5b02e35ad4d7c8ba6fde4c3b1fb5ba18a6b2c0b21",
+					Line:           19,
+					KeyActualValue: "'default_action.redirect.protocol' is equal 'HTTP'",
+				},
+				{
+					FileName:       "main.tf",
+					SimilarityID:   "0d9f6a3c0a9b3c1e4a0c5b3e8f1d2a7c6b5e4d3c2b1a0f9e8d7c6b5a4f3e2d1c",
+					Line:           4,
+					KeyActualValue: "'default_action.redirect.protocol' is missing",
+				},
+			},
+		},
+		{
+			QueryName: "AmazonMQ Broker Encryption Disabled",
+			QueryID:   "3db3f534-e3a3-487f-88c7-0a9fbf64b702",
+			Severity:  model.SeverityMedium,
+			Platform:  "Terraform",
+			Files: []model.VulnerableFile{
+				{
+					FileName:       filepath.FromSlash("modules/alb/main.tf"),
+					SimilarityID:   "a1f0e2d3c4b5a6978877665544332211ffeeddccbbaa99887766554433221100",
+					Line:           1,
+					KeyActualValue: `resource.aws_mq_broker["<broker> & co"].encryption_options is undefined`,
+				},
+			},
+		},
+		{
+			QueryName:   "Security Group Rule Without Description",
+			QueryID:     "68eb4bf3-f9bf-463d-b5cf-e029bb446d2e",
+			Description: "It's considered a best practice for Security Group Rules to have a description",
+			Severity:    model.SeverityLow,
+			Platform:    "Terraform",
+			Files: []model.VulnerableFile{
+				{
+					FileName:  "main.tf",
+					Line:      0,
+					SearchKey: "aws_security_group[allow_tls].ingress",
+				},
+			},
+		},
+		{
+			QueryName: "Resource Not Using Tags",
+			QueryID:   "e38a8e0a-b88b-4902-b3fe-b0fcb17d5c10",
+			Severity:  model.SeverityInfo,
+			Platform:  "Terraform",
+			Files: []model.VulnerableFile{
+				{
+					FileName:       "main.tf",
+					SimilarityID:   "f00dbabe0123456789abcdef0123456789abcdef0123456789abcdef01234567",
+					Line:           12,
+					KeyActualValue: "aws_mq_broker.tags is undefined",
+				},
+			},
+		},
+	},
+}
+
+// requireGolden compares the report with its golden file of testdata, the golden files are updated
+// when the tests run with '-update'
+func requireGolden(t *testing.T, golden, report string) {
+	got, err := os.ReadFile(report)
+	require.NoError(t, err)
+	goldenPath := filepath.Join("testdata", golden)
+	if *updateGolden {
+		require.NoError(t, os.WriteFile(goldenPath, got, 0600))
+	}
+	want, err := os.ReadFile(goldenPath)
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(got))
+}
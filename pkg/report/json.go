@@ -1,6 +1,14 @@
 package report
 
-import "github.com/Checkmarx/kics/internal/constants"
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Checkmarx/kics/internal/constants"
+	"github.com/Checkmarx/kics/pkg/model"
+)
 
 const jsonExtension = ".json"
 
@@ -24,3 +32,27 @@ func PrintJSONReport(path, filename string, body interface{}) error {
 
 	return ExportJSONReport(path, filename, body)
 }
+
+// ReadJSONReport reads the summary of a JSON report generated by KICS
+func ReadJSONReport(path string) (model.Summary, error) {
+	var summary model.Summary
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return summary, fmt.Errorf("failed to read report %s: %w", path, err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(content, &fields); err != nil {
+		return summary, fmt.Errorf("failed to parse report %s: %w", path, err)
+	}
+	for _, field := range []string{"queries", "severity_counters"} {
+		if _, ok := fields[field]; !ok {
+			return summary, fmt.Errorf("%s is not a KICS JSON report, '%s' is missing", path, field)
+		}
+	}
+
+	if err := json.Unmarshal(content, &summary); err != nil {
+		return summary, fmt.Errorf("failed to parse report %s: %w", path, err)
+	}
+	return summary, nil
+}
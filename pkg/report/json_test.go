@@ -55,3 +55,26 @@ func TestPrintJSONReport(t *testing.T) {
 		})
 	}
 }
+
+// TestReadJSONReport tests the function [ReadJSONReport()], the reports must be JSON reports generated by KICS
+func TestReadJSONReport(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, PrintJSONReport(dir, "results", test.SummaryMock))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other.json"), []byte(`{"runs": []}`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "invalid.json"), []byte(`{"queries": [`), 0600))
+
+	summary, err := ReadJSONReport(filepath.Join(dir, "results.json"))
+	require.NoError(t, err)
+	expected := test.SummaryMock
+	expected.Version = "development"
+	require.Equal(t, expected, summary)
+
+	_, err = ReadJSONReport(filepath.Join(dir, "other.json"))
+	require.EqualError(t, err, filepath.Join(dir, "other.json")+" is not a KICS JSON report, 'queries' is missing")
+
+	_, err = ReadJSONReport(filepath.Join(dir, "invalid.json"))
+	require.Contains(t, err.Error(), "failed to parse report")
+
+	_, err = ReadJSONReport(filepath.Join(dir, "missing.json"))
+	require.Contains(t, err.Error(), "failed to read report")
+}
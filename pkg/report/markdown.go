@@ -0,0 +1,292 @@
+package report
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Checkmarx/kics/internal/constants"
+	"github.com/Checkmarx/kics/pkg/model"
+)
+
+const (
+	markdownExtension = ".md"
+	// DefaultMarkdownMaxSize keeps the Markdown report under the size of a pull request comment
+	DefaultMarkdownMaxSize = 65000
+	// markdownMaxValueLength is the maximum number of characters of a value shown in a cell of the report
+	markdownMaxValueLength = 200
+	// markdownFooterSize is the size reserved to the note of the omitted results
+	markdownFooterSize = 256
+)
+
+// MarkdownOptions configures the Markdown report
+// LinkTemplate is the URL of the line of a file, where {repo}, {sha}, {path} and {line} are replaced
+// (e.g. 'https://github.com/{repo}/blob/{sha}/{path}#L{line}'), the files are not linked when it is empty
+// Repository and Commit are the values of {repo} and {sha}
+// RootPath is the path the paths of the links are relative to, usually the root of the repository
+// MaxSize is the maximum size of the report in bytes, the results exceeding it are omitted
+type MarkdownOptions struct {
+	LinkTemplate string
+	Repository   string
+	Commit       string
+	RootPath     string
+	MaxSize      int
+}
+
+// markdownGroup is a group of results of the report, by their state against a baseline
+type markdownGroup struct {
+	title   string
+	states  []string
+	queries model.QueryResultSlice
+}
+
+// markdownWriter writes the report, keeping the size of the results under the budget
+type markdownWriter struct {
+	builder strings.Builder
+	budget  int
+	omitted int
+	full    bool
+}
+
+// PrintMarkdownReport prints on a Markdown file the summary results, to be posted as a pull request comment,
+// the report is limited to DefaultMarkdownMaxSize when the options have no size
+func PrintMarkdownReport(path, filename string, body interface{}, opts MarkdownOptions) error {
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = DefaultMarkdownMaxSize
+	}
+	if !strings.HasSuffix(filename, markdownExtension) {
+		filename += markdownExtension
+	}
+	summary, err := getSummary(body)
+	if err != nil {
+		return err
+	}
+
+	fullPath := filepath.Join(path, filename)
+	f, err := os.OpenFile(filepath.Clean(fullPath), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer closeFile(fullPath, filename, f)
+
+	_, err = f.WriteString(renderMarkdown(&summary, opts))
+	return err
+}
+
+// renderMarkdown renders the summary: a table of the results by severity and a collapsible section per query,
+// grouped by their state against a baseline when the results have one. The results exceeding the size of the
+// options are omitted, the most severe results are kept
+func renderMarkdown(summary *model.Summary, opts MarkdownOptions) string {
+	var header strings.Builder
+	header.WriteString("## KICS Scan Results\n\n")
+	header.WriteString("| Severity | Results |\n| --- | ---: |\n")
+	for _, severity := range []model.Severity{model.SeverityHigh, model.SeverityMedium, model.SeverityLow, model.SeverityInfo} {
+		fmt.Fprintf(&header, "| %s | %d |\n", severity, summary.SeverityCounters[severity])
+	}
+	fmt.Fprintf(&header, "| **TOTAL** | **%d** |\n\n", summary.TotalCounter)
+	fmt.Fprintf(&header, "Files scanned: %d · Parsed files: %d · Queries loaded: %d · Queries failed to execute: %d · KICS %s\n\n",
+		summary.ScannedFiles, summary.ParsedFiles, summary.TotalQueries, summary.FailedToExecuteQueries, constants.Version)
+
+	w := &markdownWriter{budget: opts.MaxSize - header.Len() - markdownFooterSize}
+	w.builder.WriteString(header.String())
+
+	groups := markdownGroups(summary.Queries)
+	if len(groups) == 0 {
+		w.builder.WriteString("No results found.\n")
+	}
+	for _, group := range groups {
+		if group.title != "" {
+			w.write(fmt.Sprintf("### %s (%d)\n\n", group.title, countResults(group.queries)))
+		}
+		for i := range group.queries {
+			w.writeQuery(&group.queries[i], opts)
+		}
+	}
+
+	if w.omitted > 0 {
+		fmt.Fprintf(&w.builder, "> **Note:** %d results were omitted to keep this report under %d bytes, "+
+			"see the full report for all the results.\n", w.omitted, opts.MaxSize)
+	}
+	return w.builder.String()
+}
+
+// markdownGroups returns the queries of the results sorted by severity, grouped by the baseline state of
+// their results when any result has one
+func markdownGroups(queries model.QueryResultSlice) []markdownGroup {
+	sorted := make(model.QueryResultSlice, 0, len(queries))
+	hasBaseline := false
+	for i := range queries {
+		if queries[i].Severity == model.SeverityTrace || len(queries[i].Files) == 0 {
+			continue
+		}
+		sorted = append(sorted, queries[i])
+		for j := range queries[i].Files {
+			hasBaseline = hasBaseline || queries[i].Files[j].BaselineState != ""
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return severityRank(sorted[i].Severity) < severityRank(sorted[j].Severity)
+	})
+	if len(sorted) == 0 {
+		return []markdownGroup{}
+	}
+	if !hasBaseline {
+		return []markdownGroup{{queries: sorted}}
+	}
+
+	groups := []markdownGroup{
+		{title: "New Results", states: []string{model.BaselineStateNew, ""}},
+		{title: "Existing Results", states: []string{model.BaselineStateUnchanged, model.BaselineStateUpdated}},
+		{title: "Fixed Results", states: []string{model.BaselineStateAbsent}},
+	}
+	filtered := make([]markdownGroup, 0, len(groups))
+	for _, group := range groups {
+		for i := range sorted {
+			query := sorted[i]
+			query.Files = make([]model.VulnerableFile, 0, len(sorted[i].Files))
+			for j := range sorted[i].Files {
+				if containsString(group.states, sorted[i].Files[j].BaselineState) {
+					query.Files = append(query.Files, sorted[i].Files[j])
+				}
+			}
+			if len(query.Files) > 0 {
+				group.queries = append(group.queries, query)
+			}
+		}
+		if len(group.queries) > 0 {
+			filtered = append(filtered, group)
+		}
+	}
+	return filtered
+}
+
+// writeQuery writes the collapsible section of the query, with a row per result, the rows exceeding the
+// budget are omitted
+func (w *markdownWriter) writeQuery(query *model.QueryResult, opts MarkdownOptions) {
+	const closing = "\n</details>\n\n"
+	var opening strings.Builder
+	fmt.Fprintf(&opening, "<details>\n<summary><b>%s</b> %s (%d)</summary>\n\n",
+		query.Severity, markdownText(query.QueryName), len(query.Files))
+	if description := markdownDescription(query); description != "" {
+		fmt.Fprintf(&opening, "%s\n\n", description)
+	}
+	opening.WriteString("| File | Line | Expected | Actual |\n| --- | ---: | --- | --- |\n")
+
+	if !w.reserve(opening.Len() + len(closing)) {
+		w.omitted += len(query.Files)
+		return
+	}
+	w.builder.WriteString(opening.String())
+	for i := range query.Files {
+		file := &query.Files[i]
+		row := fmt.Sprintf("| %s | %d | %s | %s |\n",
+			markdownLink(file, opts), file.Line, markdownCell(file.KeyExpectedValue), markdownCell(file.KeyActualValue))
+		if !w.reserve(len(row)) {
+			w.omitted += len(query.Files) - i
+			break
+		}
+		w.builder.WriteString(row)
+	}
+	w.builder.WriteString(closing)
+}
+
+// write writes the text when it fits in the budget
+func (w *markdownWriter) write(text string) {
+	if w.reserve(len(text)) {
+		w.builder.WriteString(text)
+	}
+}
+
+// reserve takes the size from the budget, once a text does not fit no other text is written, so the
+// omitted results are the least severe ones
+func (w *markdownWriter) reserve(size int) bool {
+	if w.full || size > w.budget {
+		w.full = true
+		return false
+	}
+	w.budget -= size
+	return true
+}
+
+func markdownDescription(query *model.QueryResult) string {
+	if query.CISDescriptionTextFormatted != "" {
+		return markdownText(query.CISDescriptionTextFormatted)
+	}
+	return markdownText(query.Description)
+}
+
+// markdownLink returns the path of the file, linked to its line when the options have a link template and the
+// file is in the root path. The files of archives and remote sources are not linked
+func markdownLink(file *model.VulnerableFile, opts MarkdownOptions) string {
+	name := markdownCell(file.FileName)
+	if opts.LinkTemplate == "" || strings.Contains(file.FileName, "!") || strings.Contains(file.FileName, "://") {
+		return name
+	}
+	filePath := file.FileName
+	if opts.RootPath != "" {
+		absPath, err := filepath.Abs(filePath)
+		if err != nil {
+			return name
+		}
+		rel, err := filepath.Rel(opts.RootPath, absPath)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return name
+		}
+		filePath = rel
+	}
+	segments := strings.Split(filepath.ToSlash(filepath.Clean(filePath)), "/")
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	link := strings.NewReplacer(
+		"{repo}", opts.Repository,
+		"{sha}", opts.Commit,
+		"{path}", strings.Join(segments, "/"),
+		"{line}", strconv.Itoa(file.Line),
+	).Replace(opts.LinkTemplate)
+	return fmt.Sprintf("[%s](%s)", name, link)
+}
+
+// markdownCell returns the value shown in a cell of a table: on a single line, shortened and escaped
+func markdownCell(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+	if runes := []rune(value); len(runes) > markdownMaxValueLength {
+		value = string(runes[:markdownMaxValueLength]) + "…"
+	}
+	return strings.ReplaceAll(markdownText(value), "|", "\\|")
+}
+
+// markdownText escapes the HTML of the text, which would be rendered by the Markdown viewers
+func markdownText(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+func severityRank(severity model.Severity) int {
+	for i := range model.AllSeverities {
+		if model.AllSeverities[i] == severity {
+			return i
+		}
+	}
+	return len(model.AllSeverities)
+}
+
+func countResults(queries model.QueryResultSlice) int {
+	count := 0
+	for i := range queries {
+		count += len(queries[i].Files)
+	}
+	return count
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
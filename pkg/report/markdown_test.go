@@ -0,0 +1,87 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/Checkmarx/kics/test"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPrintMarkdownReport tests the functions [PrintMarkdownReport()] and all the methods called by them
+func TestPrintMarkdownReport(t *testing.T) {
+	dir := t.TempDir()
+	err := PrintMarkdownReport(dir, "testout", test.ComplexSummaryMock, MarkdownOptions{})
+	require.NoError(t, err)
+	content, err := os.ReadFile(filepath.Join(dir, "testout.md"))
+	require.NoError(t, err)
+
+	markdown := string(content)
+	require.Contains(t, markdown, "| HIGH | 2 |\n| MEDIUM | 1 |\n| LOW | 0 |\n| INFO | 0 |\n| **TOTAL** | **3** |\n")
+	require.Contains(t, markdown, "<summary><b>HIGH</b> ALB protocol is HTTP (2)</summary>")
+	require.Contains(t, markdown, "<summary><b>MEDIUM</b> AmazonMQ Broker Encryption Disabled (1)</summary>")
+	require.Less(t, strings.Index(markdown, "ALB protocol is HTTP"), strings.Index(markdown, "AmazonMQ Broker"),
+		"the most severe queries are shown first")
+	require.NotContains(t, markdown, "### ")
+	require.NotContains(t, markdown, "omitted")
+}
+
+// Test_renderMarkdown tests the links, the escaping, the truncation and the baseline groups of the report
+func Test_renderMarkdown(t *testing.T) {
+	root := t.TempDir()
+	summary := model.Summary{
+		SeveritySummary: model.SeveritySummary{
+			SeverityCounters: map[model.Severity]int{model.SeverityHigh: 2, model.SeverityLow: 1},
+			TotalCounter:     3,
+		},
+		Queries: model.QueryResultSlice{
+			{
+				QueryName: "Low Query",
+				Severity:  model.SeverityLow,
+				Files: []model.VulnerableFile{
+					{FileName: filepath.Join(root, "app", "main.tf"), Line: 3, KeyActualValue: "value", BaselineState: "unchanged"},
+				},
+			},
+			{
+				QueryName:   "High <Query>",
+				Severity:    model.SeverityHigh,
+				Description: "Description",
+				Files: []model.VulnerableFile{
+					{FileName: filepath.Join(root, "my dir", "main.tf"), Line: 7, KeyActualValue: "a | b\nc", BaselineState: "new"},
+					{FileName: filepath.Join(root, "bundle.zip") + "!/main.tf", Line: 1, BaselineState: "absent"},
+				},
+			},
+		},
+	}
+	opts := MarkdownOptions{
+		LinkTemplate: "https://github.com/{repo}/blob/{sha}/{path}#L{line}",
+		Repository:   "org/repo",
+		Commit:       "0123abc",
+		RootPath:     root,
+		MaxSize:      DefaultMarkdownMaxSize,
+	}
+
+	markdown := renderMarkdown(&summary, opts)
+	for _, want := range []string{
+		"### New Results (1)",
+		"### Existing Results (1)",
+		"### Fixed Results (1)",
+		"<summary><b>HIGH</b> High &lt;Query&gt; (1)</summary>",
+		"https://github.com/org/repo/blob/0123abc/my%20dir/main.tf#L7) | 7 |  | a \\| b c |",
+		"https://github.com/org/repo/blob/0123abc/app/main.tf#L3",
+		"| " + filepath.Join(root, "bundle.zip") + "!/main.tf | 1 |",
+	} {
+		require.Contains(t, markdown, want)
+	}
+	require.Less(t, strings.Index(markdown, "### New Results"), strings.Index(markdown, "### Existing Results"))
+
+	opts.MaxSize = len(strings.SplitAfter(markdown, "### Existing Results")[0]) + markdownFooterSize
+	truncated := renderMarkdown(&summary, opts)
+	require.LessOrEqual(t, len(truncated), opts.MaxSize)
+	require.Contains(t, truncated, "### New Results (1)")
+	require.NotContains(t, truncated, "Low Query")
+	require.Contains(t, truncated, "**Note:** 2 results were omitted")
+}
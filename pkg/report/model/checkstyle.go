@@ -0,0 +1,74 @@
+package model
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+
+	"github.com/Checkmarx/kics/pkg/model"
+)
+
+const (
+	checkstyleVersion      = "4.3"
+	checkstyleSourcePrefix = "kics."
+)
+
+// checkstyleSeverities maps the severities of KICS to the severities of the Checkstyle errors
+var checkstyleSeverities = map[model.Severity]string{
+	model.SeverityHigh:   "error",
+	model.SeverityMedium: "warning",
+	model.SeverityLow:    "info",
+	model.SeverityInfo:   "info",
+	model.SeverityTrace:  "info",
+}
+
+type checkstyleReport struct {
+	XMLName xml.Name          `xml:"checkstyle"`
+	Version string            `xml:"version,attr"`
+	Files   []*checkstyleFile `xml:"file"`
+	files   map[string]*checkstyleFile
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// CheckstyleReport represents a usable checkstyle report reference
+type CheckstyleReport interface {
+	BuildCheckstyleError(issue *model.QueryResult, file *model.VulnerableFile)
+}
+
+// NewCheckstyleReport initializes a new instance of CheckstyleReport
+func NewCheckstyleReport() CheckstyleReport {
+	return &checkstyleReport{
+		Version: checkstyleVersion,
+		Files:   make([]*checkstyleFile, 0),
+		files:   make(map[string]*checkstyleFile),
+	}
+}
+
+// BuildCheckstyleError adds a new error to the file of the result, the files are listed in the order of their
+// first result. Checkstyle errors have no fingerprint, so the similarity ID is part of the message
+func (r *checkstyleReport) BuildCheckstyleError(issue *model.QueryResult, file *model.VulnerableFile) {
+	fileName := filepath.ToSlash(file.FileName)
+	entry, ok := r.files[fileName]
+	if !ok {
+		entry = &checkstyleFile{Name: fileName}
+		r.files[fileName] = entry
+		r.Files = append(r.Files, entry)
+	}
+	entry.Errors = append(entry.Errors, checkstyleError{
+		Line:     issueLine(file),
+		Severity: checkstyleSeverities[issue.Severity],
+		Message:  fmt.Sprintf("%s (similarity ID: %s)", issueMessage(issue, file), issueFingerprint(issue, file)),
+		Source:   checkstyleSourcePrefix + issue.QueryID,
+	})
+}
@@ -0,0 +1,104 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+
+	"github.com/Checkmarx/kics/pkg/model"
+)
+
+// codeQualitySeverities maps the severities of KICS to the severities of the Code Climate issues
+var codeQualitySeverities = map[model.Severity]string{
+	model.SeverityHigh:   "critical",
+	model.SeverityMedium: "major",
+	model.SeverityLow:    "minor",
+	model.SeverityInfo:   "info",
+	model.SeverityTrace:  "info",
+}
+
+type codeQualityIssue struct {
+	Type        string              `json:"type"`
+	CheckName   string              `json:"check_name"`
+	Description string              `json:"description"`
+	Content     *codeQualityContent `json:"content,omitempty"`
+	Categories  []string            `json:"categories"`
+	Severity    string              `json:"severity"`
+	Fingerprint string              `json:"fingerprint"`
+	Location    codeQualityLocation `json:"location"`
+}
+
+type codeQualityContent struct {
+	Body string `json:"body"`
+}
+
+type codeQualityLocation struct {
+	Path  string           `json:"path"`
+	Lines codeQualityLines `json:"lines"`
+}
+
+type codeQualityLines struct {
+	Begin int `json:"begin"`
+}
+
+// GitlabCodeQualityReport represents a usable gitlab code quality report reference
+type GitlabCodeQualityReport interface {
+	BuildGitlabCodeQualityIssue(issue *model.QueryResult, file *model.VulnerableFile)
+}
+
+type gitlabCodeQualityReport []codeQualityIssue
+
+// NewGitlabCodeQualityReport initializes a new instance of GitlabCodeQualityReport, a subset of the Code Climate
+// issues read by the code quality widget of the merge requests
+func NewGitlabCodeQualityReport() GitlabCodeQualityReport {
+	report := make(gitlabCodeQualityReport, 0)
+	return &report
+}
+
+// BuildGitlabCodeQualityIssue adds a new issue to the report, the similarity ID of the result is its fingerprint
+func (r *gitlabCodeQualityReport) BuildGitlabCodeQualityIssue(issue *model.QueryResult, file *model.VulnerableFile) {
+	qualityIssue := codeQualityIssue{
+		Type:        "issue",
+		CheckName:   issue.QueryID,
+		Description: issueMessage(issue, file),
+		Categories:  []string{"Security"},
+		Severity:    codeQualitySeverities[issue.Severity],
+		Fingerprint: issueFingerprint(issue, file),
+		Location: codeQualityLocation{
+			Path:  filepath.ToSlash(file.FileName),
+			Lines: codeQualityLines{Begin: issueLine(file)},
+		},
+	}
+	if issue.Description != "" {
+		qualityIssue.Content = &codeQualityContent{Body: issue.Description}
+	}
+	*r = append(*r, qualityIssue)
+}
+
+// issueMessage returns the name of the query followed by the actual value of the result
+func issueMessage(issue *model.QueryResult, file *model.VulnerableFile) string {
+	if file.KeyActualValue == "" {
+		return issue.QueryName
+	}
+	return fmt.Sprintf("%s: %s", issue.QueryName, file.KeyActualValue)
+}
+
+// issueFingerprint returns the similarity ID of the result, or a hash of its query, file, line and search key
+// when the similarity ID could not be computed
+func issueFingerprint(issue *model.QueryResult, file *model.VulnerableFile) string {
+	if file.SimilarityID != "" {
+		return file.SimilarityID
+	}
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s:%s:%d:%s",
+		issue.QueryID, filepath.ToSlash(file.FileName), file.Line, file.SearchKey)))
+	return hex.EncodeToString(hash[:])
+}
+
+// issueLine returns the line of the result, the results without a line are reported on the first line of the file
+func issueLine(file *model.VulnerableFile) int {
+	if file.Line < 1 {
+		return 1
+	}
+	return file.Line
+}
@@ -1,6 +1,11 @@
 package model
 
 import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+
 	"github.com/Checkmarx/kics/internal/constants"
 	"github.com/Checkmarx/kics/pkg/model"
 	"github.com/rs/zerolog/log"
@@ -8,6 +13,10 @@ import (
 
 var categoriesNotFound = make(map[string]bool)
 
+// similarityFingerprint is the key of the partial fingerprint of the results, their similarity ID, which does not
+// change when the lines of the file shift
+const similarityFingerprint = "similarityId/v1"
+
 var severityLevelEquivalence = map[model.Severity]string{
 	"INFO":   "none",
 	"LOW":    "note",
@@ -87,8 +96,16 @@ type sarifTool struct {
 	Driver sarifDriver `json:"driver"`
 }
 
+type sarifArtifactContent struct {
+	Text string `json:"text"`
+}
+
 type sarifRegion struct {
-	StartLine int `json:"startLine"`
+	StartLine   int                   `json:"startLine"`
+	StartColumn int                   `json:"startColumn,omitempty"`
+	EndLine     int                   `json:"endLine,omitempty"`
+	EndColumn   int                   `json:"endColumn,omitempty"`
+	Snippet     *sarifArtifactContent `json:"snippet,omitempty"`
 }
 
 type sarifArtifactLocation struct {
@@ -98,18 +115,52 @@ type sarifArtifactLocation struct {
 type sarifPhysicalLocation struct {
 	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
 	Region           sarifRegion           `json:"region"`
+	ContextRegion    *sarifRegion          `json:"contextRegion,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
 }
 
 type sarifLocation struct {
-	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+	Message          *sarifMessage          `json:"message,omitempty"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Status        string `json:"status"`
+	Justification string `json:"justification,omitempty"`
+}
+
+type sarifReplacement struct {
+	DeletedRegion   sarifRegion           `json:"deletedRegion"`
+	InsertedContent *sarifArtifactContent `json:"insertedContent,omitempty"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Replacements     []sarifReplacement    `json:"replacements"`
+}
+
+type sarifFix struct {
+	Description     sarifMessage          `json:"description"`
+	ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
 }
 
 type sarifResult struct {
-	ResultRuleID    string          `json:"ruleId"`
-	ResultRuleIndex int             `json:"ruleIndex"`
-	ResultKind      string          `json:"kind"`
-	ResultMessage   sarifMessage    `json:"message"`
-	ResultLocations []sarifLocation `json:"locations"`
+	ResultRuleID              string             `json:"ruleId"`
+	ResultRuleIndex           int                `json:"ruleIndex"`
+	ResultKind                string             `json:"kind"`
+	ResultMessage             sarifMessage       `json:"message"`
+	ResultLocations           []sarifLocation    `json:"locations"`
+	ResultPartialFingerprints map[string]string  `json:"partialFingerprints,omitempty"`
+	ResultBaselineState       string             `json:"baselineState,omitempty"`
+	ResultRelatedLocations    []sarifLocation    `json:"relatedLocations,omitempty"`
+	ResultSuppressions        []sarifSuppression `json:"suppressions,omitempty"`
+	ResultFixes               []sarifFix         `json:"fixes,omitempty"`
 }
 
 type sarifTaxanomyDefinition struct {
@@ -286,25 +337,165 @@ func (sr *sarifReport) BuildSarifIssue(issue *model.QueryResult) {
 			kind = "informational"
 		}
 		for idx := range issue.Files {
-			line := issue.Files[idx].Line
-			if line < 1 {
-				line = 1
-			}
-			result := sarifResult{
-				ResultRuleID:    issue.QueryID,
-				ResultRuleIndex: ruleIndex,
-				ResultKind:      kind,
-				ResultMessage:   sarifMessage{Text: issue.Files[idx].KeyActualValue},
-				ResultLocations: []sarifLocation{
-					{
-						PhysicalLocation: sarifPhysicalLocation{
-							ArtifactLocation: sarifArtifactLocation{ArtifactURI: issue.Files[idx].FileName},
-							Region:           sarifRegion{StartLine: line},
+			sr.Runs[0].Results = append(sr.Runs[0].Results, buildSarifResult(issue, &issue.Files[idx], ruleIndex, kind))
+		}
+	}
+}
+
+// buildSarifResult creates the result of the file, identified by its similarity ID, with its suppression and
+// the remediation of the query as its fix
+func buildSarifResult(issue *model.QueryResult, file *model.VulnerableFile, ruleIndex int, kind string) sarifResult {
+	line := file.Line
+	if line < 1 {
+		line = 1
+	}
+	region, contextRegion := buildSarifRegions(file.VulnLines, line)
+	artifactLocation := sarifArtifactLocation{ArtifactURI: file.FileName}
+
+	message := file.KeyActualValue
+	if message == "" {
+		message = issue.QueryName
+	}
+	result := sarifResult{
+		ResultRuleID:    issue.QueryID,
+		ResultRuleIndex: ruleIndex,
+		ResultKind:      kind,
+		ResultMessage:   sarifMessage{Text: message},
+		ResultLocations: []sarifLocation{
+			{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: artifactLocation,
+					Region:           region,
+					ContextRegion:    contextRegion,
+				},
+			},
+		},
+		ResultBaselineState:    file.BaselineState,
+		ResultRelatedLocations: buildSarifCommitLocations(file),
+	}
+	// findings of templates synthesized by AWS CDK are also located in the construct that defines the resource
+	if constructPath := file.ConstructPath; constructPath != "" {
+		result.ResultLocations[0].LogicalLocations = []sarifLogicalLocation{
+			{FullyQualifiedName: constructPath, Kind: "resource"},
+		}
+	}
+	if file.SimilarityID != "" {
+		result.ResultPartialFingerprints = map[string]string{similarityFingerprint: file.SimilarityID}
+	}
+	if file.Suppression != nil {
+		result.ResultSuppressions = []sarifSuppression{
+			{Kind: file.Suppression.Kind, Status: "accepted", Justification: file.Suppression.Justification},
+		}
+	}
+	if fix := buildSarifFix(file, artifactLocation, line); fix != nil {
+		result.ResultFixes = []sarifFix{*fix}
+	}
+	return result
+}
+
+// buildSarifFix returns the fix of the result, replacing the text of its line given by the remediation of the
+// query, results without a remediation, or whose line does not contain the text to replace, have no fix
+func buildSarifFix(file *model.VulnerableFile, artifactLocation sarifArtifactLocation, line int) *sarifFix {
+	if file.Remediation == nil {
+		return nil
+	}
+	for i := range file.VulnLines {
+		if file.VulnLines[i].Position != line {
+			continue
+		}
+		text := file.VulnLines[i].Line
+		idx := strings.Index(text, file.Remediation.Before)
+		if idx < 0 {
+			return nil
+		}
+		startColumn := utf16Length(text[:idx]) + 1
+		description := file.KeyExpectedValue
+		if description == "" {
+			description = fmt.Sprintf("Replace '%s' with '%s'", file.Remediation.Before, file.Remediation.After)
+		}
+		return &sarifFix{
+			Description: sarifMessage{Text: description},
+			ArtifactChanges: []sarifArtifactChange{
+				{
+					ArtifactLocation: artifactLocation,
+					Replacements: []sarifReplacement{
+						{
+							DeletedRegion: sarifRegion{
+								StartLine:   line,
+								StartColumn: startColumn,
+								EndColumn:   startColumn + utf16Length(file.Remediation.Before),
+							},
+							InsertedContent: &sarifArtifactContent{Text: file.Remediation.After},
 						},
 					},
 				},
-			}
-			sr.Runs[0].Results = append(sr.Runs[0].Results, result)
+			},
+		}
+	}
+	return nil
+}
+
+// buildSarifRegions returns the region of the line of the result, with the columns of its text without the
+// indentation, and the region of the lines around it, when the code lines of the result are known
+func buildSarifRegions(vulnLines []model.CodeLine, line int) (region sarifRegion, contextRegion *sarifRegion) {
+	region = sarifRegion{StartLine: line}
+	for i := range vulnLines {
+		if vulnLines[i].Position != line {
+			continue
+		}
+		text := strings.TrimRight(vulnLines[i].Line, " \t\r")
+		if trimmed := strings.TrimLeft(text, " \t"); trimmed != "" {
+			region.EndLine = line
+			region.StartColumn = utf16Length(text[:len(text)-len(trimmed)]) + 1
+			region.EndColumn = utf16Length(text) + 1
+			region.Snippet = &sarifArtifactContent{Text: trimmed}
 		}
 	}
+	if len(vulnLines) < 2 {
+		return region, nil
+	}
+
+	lines := make([]string, 0, len(vulnLines))
+	for i := range vulnLines {
+		lines = append(lines, vulnLines[i].Line)
+	}
+	contextRegion = &sarifRegion{
+		StartLine: vulnLines[0].Position,
+		EndLine:   vulnLines[len(vulnLines)-1].Position,
+		Snippet:   &sarifArtifactContent{Text: strings.Join(lines, "\n")},
+	}
+	return region, contextRegion
+}
+
+// buildSarifCommitLocations returns the locations of the other commits that added the secret of the result
+// found in the history of a repository, the result is located in the first commit
+func buildSarifCommitLocations(file *model.VulnerableFile) []sarifLocation {
+	if len(file.Commits) < 2 {
+		return nil
+	}
+	// the paths of the commits are relative to the root of the repository
+	root := ""
+	if fileName := filepath.ToSlash(file.FileName); strings.HasSuffix(fileName, file.Commits[0].File) {
+		root = strings.TrimSuffix(fileName, file.Commits[0].File)
+	}
+	locations := make([]sarifLocation, 0, len(file.Commits)-1)
+	for _, commit := range file.Commits[1:] {
+		line := commit.Line
+		if line < 1 {
+			line = 1
+		}
+		locations = append(locations, sarifLocation{
+			PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{ArtifactURI: filepath.FromSlash(root + commit.File)},
+				Region:           sarifRegion{StartLine: line},
+			},
+			Message: &sarifMessage{Text: fmt.Sprintf("Also added in commit %s", commit.SHA)},
+		})
+	}
+	return locations
+}
+
+// utf16Length returns the length of the text in UTF-16 code units, the default unit of the SARIF columns
+func utf16Length(text string) int {
+	return len(utf16.Encode([]rune(text)))
 }
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/Checkmarx/kics/internal/constants"
@@ -104,6 +105,71 @@ var sarifTests = []sarifTest{
 			},
 		},
 	},
+	{
+		name: "Should locate the occurrence in its CDK construct",
+		vq: []model.QueryResult{
+			{
+				QueryName:   "test",
+				QueryID:     "1",
+				Description: "test description",
+				QueryURI:    "https://www.test.com",
+				Severity:    model.SeverityHigh,
+				Files: []model.VulnerableFile{
+					{KeyActualValue: "test", FileName: "cdk.out/App.template.json", Line: 3, ConstructPath: "App/Bucket/Resource"},
+				},
+			},
+		},
+		want: sarifReport{
+			Runs: []SarifRun{
+				{
+					Tool: sarifTool{
+						Driver: sarifDriver{
+							Rules: []sarifRule{
+								{
+									RuleID:               "1",
+									RuleName:             "test",
+									RuleShortDescription: sarifMessage{Text: "test"},
+									RuleFullDescription:  sarifMessage{Text: "test description"},
+									DefaultConfiguration: sarifConfiguration{
+										Level: "error",
+									},
+									HelpURI: "https://www.test.com",
+									RuleRelationships: []sarifDescriptorRelationship{
+										{
+											Target: sarifDescriptorReference{
+												ReferenceID:    "CAT000",
+												ReferenceIndex: 0,
+												ToolComponent:  targetTemplate.ToolComponent,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+					Results: []sarifResult{
+						{
+							ResultRuleID:    "1",
+							ResultRuleIndex: 0,
+							ResultKind:      "fail",
+							ResultMessage:   sarifMessage{Text: "test"},
+							ResultLocations: []sarifLocation{
+								{
+									PhysicalLocation: sarifPhysicalLocation{
+										ArtifactLocation: sarifArtifactLocation{ArtifactURI: "cdk.out/App.template.json"},
+										Region:           sarifRegion{StartLine: 3},
+									},
+									LogicalLocations: []sarifLogicalLocation{
+										{FullyQualifiedName: "App/Bucket/Resource", Kind: "resource"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	},
 	{
 		name: "Should create multiple occurrence",
 		vq: []model.QueryResult{
@@ -254,3 +320,67 @@ func TestBuildSarifIssue(t *testing.T) {
 		})
 	}
 }
+
+// Test_buildSarifResult tests the fingerprint, the regions, the suppression, the fix and the related locations of a result
+func Test_buildSarifResult(t *testing.T) {
+	issue := model.QueryResult{QueryName: "test", QueryID: "1", Severity: model.SeverityHigh}
+	file := model.VulnerableFile{
+		FileName:     filepath.FromSlash("repo/app/main.tf"),
+		SimilarityID: "abc123",
+		Line:         2,
+		VulnLines: []model.CodeLine{
+			{Position: 1, Line: `resource "aws_db_instance" "db" {`},
+			{Position: 2, Line: "  password = \"é***d\"  "},
+			{Position: 3, Line: "}"},
+		},
+		KeyExpectedValue: "Hardcoded secret key should not appear in source",
+		KeyActualValue:   "contains a secret",
+		BaselineState:    model.BaselineStateNew,
+		Suppression:      &model.Suppression{Kind: model.SuppressionExternal, Justification: "excluded by its similarity ID"},
+		Remediation:      &model.Remediation{Before: "\"é***d\"", After: "var.password"},
+		Commits: []model.Commit{
+			{SHA: "1111", File: "app/main.tf", Line: 2},
+			{SHA: "2222", File: "scripts/deploy.sh", Line: 4},
+		},
+	}
+
+	result := buildSarifResult(&issue, &file, 0, "fail")
+	region := sarifRegion{StartLine: 2, StartColumn: 3, EndLine: 2, EndColumn: 21,
+		Snippet: &sarifArtifactContent{Text: "password = \"é***d\""}}
+	require.Equal(t, map[string]string{"similarityId/v1": "abc123"}, result.ResultPartialFingerprints)
+	require.Equal(t, region, result.ResultLocations[0].PhysicalLocation.Region)
+	require.Equal(t, &sarifRegion{StartLine: 1, EndLine: 3, Snippet: &sarifArtifactContent{
+		Text: "resource \"aws_db_instance\" \"db\" {\n  password = \"é***d\"  \n}"}},
+		result.ResultLocations[0].PhysicalLocation.ContextRegion)
+	require.Equal(t, "new", result.ResultBaselineState)
+	require.Equal(t, []sarifSuppression{{Kind: "external", Status: "accepted", Justification: "excluded by its similarity ID"}},
+		result.ResultSuppressions)
+	require.Equal(t, []sarifFix{{
+		Description: sarifMessage{Text: "Hardcoded secret key should not appear in source"},
+		ArtifactChanges: []sarifArtifactChange{{
+			ArtifactLocation: sarifArtifactLocation{ArtifactURI: file.FileName},
+			Replacements: []sarifReplacement{{
+				DeletedRegion:   sarifRegion{StartLine: 2, StartColumn: 14, EndColumn: 21},
+				InsertedContent: &sarifArtifactContent{Text: "var.password"},
+			}},
+		}},
+	}}, result.ResultFixes)
+	require.Equal(t, []sarifLocation{{
+		PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{ArtifactURI: filepath.FromSlash("repo/scripts/deploy.sh")},
+			Region:           sarifRegion{StartLine: 4},
+		},
+		Message: &sarifMessage{Text: "Also added in commit 2222"},
+	}}, result.ResultRelatedLocations)
+
+	file.VulnLines = nil
+	file.KeyActualValue = ""
+	result = buildSarifResult(&issue, &file, 0, "fail")
+	require.Equal(t, sarifRegion{StartLine: 2}, result.ResultLocations[0].PhysicalLocation.Region)
+	require.Nil(t, result.ResultLocations[0].PhysicalLocation.ContextRegion)
+	require.Equal(t, "test", result.ResultMessage.Text)
+	require.Nil(t, result.ResultFixes, "the line of the remediation is unknown")
+
+	file.Remediation = nil
+	require.Nil(t, buildSarifResult(&issue, &file, 0, "fail").ResultFixes)
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/Checkmarx/kics/pkg/model"
+)
+
+const sonarQubeEngineID = "KICS"
+
+// sonarQubeSeverities maps the severities of KICS to the severities of the SonarQube issues
+var sonarQubeSeverities = map[model.Severity]string{
+	model.SeverityHigh:   "CRITICAL",
+	model.SeverityMedium: "MAJOR",
+	model.SeverityLow:    "MINOR",
+	model.SeverityInfo:   "INFO",
+	model.SeverityTrace:  "INFO",
+}
+
+type sonarQubeReport struct {
+	Issues []sonarQubeIssue `json:"issues"`
+}
+
+type sonarQubeIssue struct {
+	EngineID        string            `json:"engineId"`
+	RuleID          string            `json:"ruleId"`
+	Severity        string            `json:"severity"`
+	Type            string            `json:"type"`
+	PrimaryLocation sonarQubeLocation `json:"primaryLocation"`
+}
+
+type sonarQubeLocation struct {
+	Message   string             `json:"message"`
+	FilePath  string             `json:"filePath"`
+	TextRange sonarQubeTextRange `json:"textRange"`
+}
+
+type sonarQubeTextRange struct {
+	StartLine int `json:"startLine"`
+}
+
+// SonarQubeReport represents a usable sonarqube report reference
+type SonarQubeReport interface {
+	BuildSonarQubeIssue(issue *model.QueryResult, file *model.VulnerableFile)
+}
+
+// NewSonarQubeReport initializes a new instance of SonarQubeReport, on the generic issue import format
+func NewSonarQubeReport() SonarQubeReport {
+	return &sonarQubeReport{
+		Issues: make([]sonarQubeIssue, 0),
+	}
+}
+
+// BuildSonarQubeIssue adds a new issue to the report, the issues of INFO queries are code smells and the others
+// vulnerabilities. The generic issues have no fingerprint, so the similarity ID is part of the message, it can
+// be used to exclude the result with '--exclude-results'
+func (r *sonarQubeReport) BuildSonarQubeIssue(issue *model.QueryResult, file *model.VulnerableFile) {
+	issueType := "VULNERABILITY"
+	if issue.Severity == model.SeverityInfo || issue.Severity == model.SeverityTrace {
+		issueType = "CODE_SMELL"
+	}
+	r.Issues = append(r.Issues, sonarQubeIssue{
+		EngineID: sonarQubeEngineID,
+		RuleID:   issue.QueryID,
+		Severity: sonarQubeSeverities[issue.Severity],
+		Type:     issueType,
+		PrimaryLocation: sonarQubeLocation{
+			Message:   fmt.Sprintf("%s (similarity ID: %s)", issueMessage(issue, file), issueFingerprint(issue, file)),
+			FilePath:  filepath.ToSlash(file.FileName),
+			TextRange: sonarQubeTextRange{StartLine: issueLine(file)},
+		},
+	})
+}
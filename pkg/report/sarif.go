@@ -12,7 +12,7 @@ func PrintSarifReport(path, filename string, body interface{}) error {
 		filename += ".sarif"
 	}
 	if body != "" {
-		summary, err := getSummary(body)
+		summary, err := getSummaryWithLines(body)
 		if err != nil {
 			return err
 		}
@@ -21,6 +21,10 @@ func PrintSarifReport(path, filename string, body interface{}) error {
 		for idx := range summary.Queries {
 			sarifReport.BuildSarifIssue(&summary.Queries[idx])
 		}
+		// the suppressed results are reported with their suppression, so they are not shown as open alerts
+		for idx := range summary.Suppressed {
+			sarifReport.BuildSarifIssue(&summary.Suppressed[idx])
+		}
 		body = sarifReport
 	}
 
@@ -0,0 +1,34 @@
+package report
+
+import (
+	"strings"
+
+	reportModel "github.com/Checkmarx/kics/pkg/report/model"
+)
+
+// PrintSonarQubeReport creates a report file on the SonarQube generic issue import format
+func PrintSonarQubeReport(path, filename string, body interface{}) error {
+	filename = strings.ReplaceAll(filename, ".sonarqube", "")
+	if !strings.HasSuffix(filename, jsonExtension) {
+		filename += jsonExtension
+	}
+	if !strings.HasPrefix(filename, "sonarqube-") {
+		filename = "sonarqube-" + filename
+	}
+	if body != "" {
+		summary, err := getSummary(body)
+		if err != nil {
+			return err
+		}
+
+		sonarQubeReport := reportModel.NewSonarQubeReport()
+		for idxQuery := range summary.Queries {
+			for idxFile := range summary.Queries[idxQuery].Files {
+				sonarQubeReport.BuildSonarQubeIssue(&summary.Queries[idxQuery], &summary.Queries[idxQuery].Files[idxFile])
+			}
+		}
+		body = sonarQubeReport
+	}
+
+	return ExportJSONReport(path, filename, body)
+}
@@ -0,0 +1,15 @@
+package report
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestPrintSonarQubeReport tests the functions [PrintSonarQubeReport()] and all the methods called by them
+func TestPrintSonarQubeReport(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, PrintSonarQubeReport(dir, "results", goldenSummaryMock))
+	requireGolden(t, "sonarqube-results.json", filepath.Join(dir, "sonarqube-results.json"))
+}
@@ -0,0 +1,624 @@
+package ansible
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	linesKey    = "_kics_lines"
+	defaultKey  = "_kics__default"
+	arrKey      = "_kics_arr"
+	lineKey     = "_kics_line"
+	originKey   = "_kics_ref"
+	playbookKey = "playbooks"
+	maxDepth    = 16
+)
+
+var (
+	includeTasksModules = []string{
+		"include_tasks", "import_tasks", "include",
+		"ansible.builtin.include_tasks", "ansible.builtin.import_tasks", "ansible.builtin.include",
+	}
+	includeRoleModules = []string{
+		"include_role", "import_role", "ansible.builtin.include_role", "ansible.builtin.import_role",
+	}
+	yamlExtensions = []string{"", ".yml", ".yaml"}
+)
+
+// Resolver assembles Ansible playbooks with the tasks of their roles (and role dependencies) and of
+// the task files they include, and renders the Jinja variables of the tasks with the variables
+// of the roles (defaults and vars), the inventory (group_vars and host_vars) and the play (vars and vars_files).
+// Tasks read from other files keep the line information of their file, which is stored in the '_kics_ref' key.
+// Task files of roles scanned on their own are rendered with the variables of their role.
+// Files encrypted with Ansible Vault and '!vault' strings are decrypted when a vault is given
+type Resolver struct {
+	files   map[string]model.Document
+	vault   *Vault
+	vaulted []string
+}
+
+// role is a role used by a play, with the parameters set where it is used
+type role struct {
+	dir    string
+	params map[string]interface{}
+}
+
+// NewResolver creates a new Resolver's reference, vault may be nil
+func NewResolver(vault *Vault) *Resolver {
+	return &Resolver{
+		files:   make(map[string]model.Document),
+		vault:   vault,
+		vaulted: make([]string, 0),
+	}
+}
+
+// Vaulted returns the values decrypted from the files read by the resolver
+func (r *Resolver) Vaulted() []string {
+	return r.vaulted
+}
+
+// Resolve resolves the roles, included tasks and variables of the playbook or role task file placed in filePath
+// other documents are returned untouched
+func (r *Resolver) Resolve(doc model.Document, filePath string) model.Document {
+	items, ok := doc[playbookKey].([]interface{})
+	if !ok {
+		return doc
+	}
+	// the line information of the plays (or tasks) is placed in the line information of the document
+	lines, _ := doc[linesKey].(map[string]interface{})
+	itemsArr := getArr(lines, defaultKey)
+
+	if isPlaybook(items) {
+		for idx, item := range items {
+			play, isPlay := item.(map[string]interface{})
+			if !isPlay || idx >= len(itemsArr) {
+				continue
+			}
+			if playLines, ok := itemsArr[idx].(map[string]interface{}); ok {
+				r.resolvePlay(play, playLines, filepath.Dir(filePath))
+			}
+		}
+		return doc
+	}
+
+	roleDir, ok := roleOfTaskFile(filePath)
+	if !ok {
+		return doc
+	}
+	vars := variables{}
+	r.loadVars(vars, filepath.Join(roleDir, "defaults", "main"))
+	r.loadVars(vars, filepath.Join(roleDir, "vars", "main"))
+
+	tasks, arr := r.expandTasks(items, itemsArr, filepath.Dir(filePath),
+		[]string{filepath.Dir(roleDir)}, vars, []string{filePath})
+	doc[playbookKey] = vars.renderTasks(tasks)
+	setArr(lines, defaultKey, arr)
+	return doc
+}
+
+// resolvePlay adds the tasks of the roles of the play and of the included files to the play tasks
+// and renders them with the variables available to the play, lines is the line information of the play
+func (r *Resolver) resolvePlay(play, lines map[string]interface{}, dir string) {
+	searchDirs := []string{filepath.Join(dir, "roles"), dir}
+	roles := r.playRoles(play, searchDirs)
+
+	vars := variables{}
+	for _, rl := range roles {
+		r.loadVars(vars, filepath.Join(rl.dir, "defaults", "main"))
+	}
+	r.loadInventoryVars(vars, dir, hostPatterns(play["hosts"]))
+	if playVars, ok := stripLines(play["vars"]).(map[string]interface{}); ok {
+		for key, value := range playVars {
+			vars[key] = value
+		}
+	}
+	for _, varsFile := range toSlice(play["vars_files"]) {
+		if name, ok := vars.render(firstString(varsFile), 0).(string); ok && name != "" {
+			r.loadVars(vars, resolvePath(dir, name))
+		}
+	}
+	for _, rl := range roles {
+		r.loadVars(vars, filepath.Join(rl.dir, "vars", "main"))
+		for key, value := range rl.params {
+			vars[key] = value
+		}
+	}
+
+	tasks := make([]interface{}, 0)
+	arr := make([]interface{}, 0)
+	for _, rl := range roles {
+		roleTasks, roleArr := r.roleTasks(rl.dir, "main", searchDirs, vars, []string{})
+		tasks = append(tasks, roleTasks...)
+		arr = append(arr, roleArr...)
+	}
+
+	if playTasks, ok := play["tasks"].([]interface{}); ok {
+		expanded, expandedArr := r.expandTasks(playTasks, getArr(lines, "_kics_tasks"), dir, searchDirs, vars, []string{})
+		tasks = append(tasks, expanded...)
+		arr = append(arr, expandedArr...)
+	}
+	if len(tasks) == 0 {
+		return
+	}
+
+	play["tasks"] = vars.renderTasks(tasks)
+	if _, ok := lines["_kics_tasks"]; !ok {
+		// plays that only use roles have their tasks placed where the roles are listed
+		lines["_kics_tasks"] = map[string]interface{}{lineKey: getLine(lines, "_kics_roles")}
+	}
+	setArr(lines, "_kics_tasks", arr)
+}
+
+// playRoles returns the roles of the play, preceded by their dependencies
+func (r *Resolver) playRoles(play map[string]interface{}, searchDirs []string) []role {
+	roles := make([]role, 0)
+	seen := make(map[string]bool)
+
+	var addRole func(entry interface{}, dirs []string, depth int)
+	addRole = func(entry interface{}, dirs []string, depth int) {
+		name, params := roleEntry(entry)
+		dir, ok := findRole(name, dirs)
+		if !ok || seen[dir] || depth > maxDepth {
+			return
+		}
+		seen[dir] = true
+
+		meta := r.readVars(filepath.Join(dir, "meta", "main"))
+		for _, dependency := range toSlice(meta["dependencies"]) {
+			addRole(dependency, append([]string{filepath.Dir(dir)}, dirs...), depth+1)
+		}
+		roles = append(roles, role{dir: dir, params: params})
+	}
+
+	for _, entry := range toSlice(play["roles"]) {
+		addRole(entry, searchDirs, 0)
+	}
+	return roles
+}
+
+// roleTasks returns the tasks of the tasks file of the role (tasks/main.yml by default)
+func (r *Resolver) roleTasks(roleDir, tasksFrom string, searchDirs []string, vars variables,
+	stack []string) (tasks, arr []interface{}) {
+	path, ok := findFile(filepath.Join(roleDir, "tasks", tasksFrom))
+	if !ok {
+		return []interface{}{}, []interface{}{}
+	}
+	return r.includeTasks(path, append([]string{filepath.Dir(roleDir)}, searchDirs...), vars, stack)
+}
+
+// includeTasks returns the tasks of the file, with their included tasks, marked with the file they were read from
+func (r *Resolver) includeTasks(path string, searchDirs []string, vars variables, stack []string) (tasks, arr []interface{}) {
+	if len(stack) >= maxDepth || contains(stack, path) {
+		log.Debug().Msgf("skipping circular include of %s", path)
+		return []interface{}{}, []interface{}{}
+	}
+
+	doc := r.getFile(path)
+	items, ok := doc[playbookKey].([]interface{})
+	if !ok {
+		return []interface{}{}, []interface{}{}
+	}
+	lines, _ := doc[linesKey].(map[string]interface{})
+	itemsArr := getArr(lines, defaultKey)
+
+	for idx, item := range items {
+		if task, ok := item.(map[string]interface{}); ok {
+			task[originKey] = path
+			if idx < len(itemsArr) {
+				task[linesKey] = itemsArr[idx]
+			}
+		}
+	}
+	return r.expandTasks(items, itemsArr, filepath.Dir(path), searchDirs, vars, append(stack, path))
+}
+
+// expandTasks adds the included tasks (include_tasks, import_tasks, include_role and import_role)
+// after the task that includes them, arr is the line information of the tasks
+func (r *Resolver) expandTasks(tasks, arr []interface{}, dir string, searchDirs []string, vars variables,
+	stack []string) (expanded, expandedArr []interface{}) {
+	expanded = make([]interface{}, 0, len(tasks))
+	expandedArr = make([]interface{}, 0, len(tasks))
+
+	for idx, item := range tasks {
+		expanded = append(expanded, item)
+		if idx < len(arr) {
+			expandedArr = append(expandedArr, arr[idx])
+		} else {
+			expandedArr = append(expandedArr, map[string]interface{}{})
+		}
+
+		task, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		var included, includedArr []interface{}
+		if file, ok := includedFile(task, vars); ok {
+			if path, found := findFile(resolvePath(dir, file)); found {
+				included, includedArr = r.includeTasks(path, searchDirs, vars, stack)
+			}
+		} else if name, tasksFrom, ok := includedRole(task, vars); ok {
+			if roleDir, found := findRole(name, searchDirs); found {
+				included, includedArr = r.roleTasks(roleDir, tasksFrom, searchDirs, vars, stack)
+			}
+		}
+		expanded = append(expanded, included...)
+		expandedArr = append(expandedArr, includedArr...)
+	}
+	return expanded, expandedArr
+}
+
+// getFile returns the content of the tasks file with line information, the content is copied since
+// the same file can be included several times
+func (r *Resolver) getFile(path string) model.Document {
+	if _, ok := r.files[path]; !ok {
+		r.files[path] = model.Document{}
+		content, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			log.Debug().Msgf("failed to read included file %s: %s", path, err)
+			return model.Document{}
+		}
+		doc := model.Document{}
+		if err := r.decode(content, &doc); err != nil {
+			log.Debug().Msgf("failed to parse included file %s: %s", path, err)
+			return model.Document{}
+		}
+		r.files[path] = doc
+	}
+	return model.Document(deepCopy(map[string]interface{}(r.files[path])).(map[string]interface{}))
+}
+
+// loadVars adds the variables defined in the file (or in the files of the directory) to vars
+func (r *Resolver) loadVars(vars variables, path string) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				r.loadVars(vars, filepath.Join(path, entry.Name()))
+			}
+		}
+		return
+	}
+	for key, value := range r.readVars(path) {
+		vars[key] = value
+	}
+}
+
+// loadInventoryVars adds the variables of group_vars and host_vars placed next to the playbook
+func (r *Resolver) loadInventoryVars(vars variables, dir string, patterns []string) {
+	r.loadVars(vars, filepath.Join(dir, "group_vars", "all"))
+	for _, pattern := range patterns {
+		if pattern != "all" {
+			r.loadVars(vars, filepath.Join(dir, "group_vars", pattern))
+		}
+	}
+	for _, pattern := range patterns {
+		r.loadVars(vars, filepath.Join(dir, "host_vars", pattern))
+	}
+}
+
+// readVars reads a YAML file with variables, the extension of the file may be omitted
+func (r *Resolver) readVars(path string) map[string]interface{} {
+	path, ok := findFile(path)
+	if !ok {
+		return map[string]interface{}{}
+	}
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return map[string]interface{}{}
+	}
+	vars := make(map[string]interface{})
+	if err := r.decode(content, &vars); err != nil {
+		log.Debug().Msgf("failed to parse variables file %s: %s", path, err)
+		return map[string]interface{}{}
+	}
+	return vars
+}
+
+// decode decodes the YAML content into out, decrypting the content or its '!vault' strings when a vault is set
+func (r *Resolver) decode(content []byte, out interface{}) error {
+	if r.vault == nil {
+		return yaml.Unmarshal(content, out)
+	}
+	vaulted := IsVaulted(content)
+	if vaulted {
+		decrypted, err := r.vault.Decrypt(content)
+		if err != nil {
+			return err
+		}
+		content = decrypted
+	}
+
+	node := yaml.Node{}
+	if err := yaml.Unmarshal(content, &node); err != nil || node.Kind == 0 {
+		return err
+	}
+	if vaulted {
+		r.vaulted = append(r.vaulted, MarkVaulted(&node)...)
+	}
+	for _, value := range r.vault.DecryptValues(&node) {
+		r.vaulted = append(r.vaulted, value.Value)
+	}
+	return node.Decode(out)
+}
+
+// renderTasks renders the Jinja variables of the tasks (and of the tasks of their blocks), except their names,
+// which are used to find the tasks
+func (v variables) renderTasks(tasks []interface{}) []interface{} {
+	for _, item := range tasks {
+		task, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key, value := range task {
+			switch {
+			case key == "name" || strings.HasPrefix(key, "_kics_"):
+			case key == "block" || key == "rescue" || key == "always":
+				if nested, ok := value.([]interface{}); ok {
+					task[key] = v.renderTasks(nested)
+				}
+			default:
+				task[key] = v.renderValue(value)
+			}
+		}
+	}
+	return tasks
+}
+
+func (v variables) renderValue(value interface{}) interface{} {
+	switch val := value.(type) {
+	case string:
+		return v.render(val, 0)
+	case map[string]interface{}:
+		for key, child := range val {
+			if !strings.HasPrefix(key, "_kics_") {
+				val[key] = v.renderValue(child)
+			}
+		}
+	case []interface{}:
+		for idx, child := range val {
+			val[idx] = v.renderValue(child)
+		}
+	}
+	return value
+}
+
+// isPlaybook returns true if the items are plays and not tasks
+func isPlaybook(items []interface{}) bool {
+	for _, item := range items {
+		if play, ok := item.(map[string]interface{}); ok {
+			if _, ok := play["hosts"]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// roleOfTaskFile returns the directory of the role when the file is placed in the tasks directory of a role
+func roleOfTaskFile(filePath string) (string, bool) {
+	roleDir := filepath.Dir(filepath.Dir(filePath))
+	if filepath.Base(filepath.Dir(filePath)) != "tasks" || filepath.Base(filepath.Dir(roleDir)) != "roles" {
+		return "", false
+	}
+	return roleDir, true
+}
+
+// roleEntry returns the name and the parameters of a role listed in 'roles' or in 'dependencies'
+func roleEntry(entry interface{}) (name string, params map[string]interface{}) {
+	params = make(map[string]interface{})
+	switch e := entry.(type) {
+	case string:
+		return e, params
+	case map[string]interface{}:
+		for key, value := range e {
+			switch key {
+			case "role", "name":
+				name, _ = value.(string)
+			case "vars":
+				if roleVars, ok := stripLines(value).(map[string]interface{}); ok {
+					for k, v := range roleVars {
+						params[k] = v
+					}
+				}
+			case "when", "tags", "become", "become_user", "delegate_to", linesKey, originKey:
+			default:
+				params[key] = stripLines(value)
+			}
+		}
+	}
+	return name, params
+}
+
+// findRole returns the directory of the role, roles of collections (namespace.collection.role) are not resolved
+func findRole(name string, searchDirs []string) (string, bool) {
+	if name == "" || strings.Contains(name, "{{") {
+		return "", false
+	}
+	if filepath.IsAbs(name) {
+		return name, isDir(name)
+	}
+	for _, dir := range searchDirs {
+		if candidate := filepath.Join(dir, filepath.FromSlash(name)); isDir(candidate) {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// includedFile returns the tasks file included by the task
+func includedFile(task map[string]interface{}, vars variables) (string, bool) {
+	for _, module := range includeTasksModules {
+		value, ok := task[module]
+		if !ok {
+			continue
+		}
+		if args, ok := value.(map[string]interface{}); ok {
+			value = args["file"]
+		}
+		if file, ok := vars.render(firstString(value), 0).(string); ok && file != "" && !strings.Contains(file, "{{") {
+			return file, true
+		}
+	}
+	return "", false
+}
+
+// includedRole returns the role (and its tasks file) included by the task
+func includedRole(task map[string]interface{}, vars variables) (name, tasksFrom string, ok bool) {
+	for _, module := range includeRoleModules {
+		args, isMap := task[module].(map[string]interface{})
+		if !isMap {
+			continue
+		}
+		name, _ = vars.render(firstString(args["name"]), 0).(string)
+		tasksFrom = "main"
+		if from, ok := vars.render(firstString(args["tasks_from"]), 0).(string); ok && from != "" {
+			tasksFrom = from
+		}
+		return name, tasksFrom, name != ""
+	}
+	return "", "", false
+}
+
+// hostPatterns returns the groups and hosts targeted by the play
+func hostPatterns(hosts interface{}) []string {
+	patterns := make([]string, 0)
+	for _, entry := range toSlice(hosts) {
+		str, ok := entry.(string)
+		if !ok {
+			continue
+		}
+		for _, pattern := range strings.FieldsFunc(str, func(r rune) bool { return r == ',' || r == ':' }) {
+			pattern = strings.TrimLeft(strings.TrimSpace(pattern), "!&")
+			if pattern != "" && !strings.ContainsAny(pattern, "*{[") {
+				patterns = append(patterns, pattern)
+			}
+		}
+	}
+	return patterns
+}
+
+// findFile returns the path of the YAML file, whose extension may be omitted
+func findFile(path string) (string, bool) {
+	for _, ext := range yamlExtensions {
+		if info, err := os.Stat(path + ext); err == nil && !info.IsDir() {
+			return path + ext, true
+		}
+	}
+	return "", false
+}
+
+func resolvePath(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(dir, filepath.FromSlash(path))
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func toSlice(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		return v
+	case nil:
+		return []interface{}{}
+	default:
+		return []interface{}{v}
+	}
+}
+
+// firstString returns the value if it is a string or the first string of a list
+// (vars_files entries may list alternative files)
+func firstString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []interface{}:
+		for _, item := range v {
+			if str, ok := item.(string); ok {
+				return str
+			}
+		}
+	}
+	return ""
+}
+
+func contains(list []string, str string) bool {
+	for _, item := range list {
+		if item == str {
+			return true
+		}
+	}
+	return false
+}
+
+func getArr(lines map[string]interface{}, key string) []interface{} {
+	entry, _ := lines[key].(map[string]interface{})
+	arr, _ := entry[arrKey].([]interface{})
+	return arr
+}
+
+func setArr(lines map[string]interface{}, key string, arr []interface{}) {
+	if entry, ok := lines[key].(map[string]interface{}); ok {
+		entry[arrKey] = arr
+	}
+}
+
+func getLine(lines map[string]interface{}, key string) interface{} {
+	if entry, ok := lines[key].(map[string]interface{}); ok {
+		return entry[lineKey]
+	}
+	return 0
+}
+
+// stripLines returns a copy of the value without line information
+func stripLines(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		cp := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			if key != linesKey {
+				cp[key] = stripLines(child)
+			}
+		}
+		return cp
+	case []interface{}:
+		cp := make([]interface{}, len(v))
+		for idx, child := range v {
+			cp[idx] = stripLines(child)
+		}
+		return cp
+	}
+	return value
+}
+
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		cp := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			cp[key] = deepCopy(child)
+		}
+		return cp
+	case []interface{}:
+		cp := make([]interface{}, len(v))
+		for idx, child := range v {
+			cp[idx] = deepCopy(child)
+		}
+		return cp
+	}
+	return value
+}
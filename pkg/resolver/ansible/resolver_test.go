@@ -0,0 +1,148 @@
+package ansible
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+var fixturesDir = filepath.FromSlash("../../../test/fixtures/ansible_roles")
+
+func loadFixture(t *testing.T, name string) model.Document {
+	content, err := os.ReadFile(filepath.Join(fixturesDir, filepath.FromSlash(name)))
+	require.NoError(t, err)
+	doc := model.Document{}
+	require.NoError(t, yaml.Unmarshal(content, &doc))
+	return doc
+}
+
+func getTasks(t *testing.T, doc model.Document) []interface{} {
+	plays, ok := doc[playbookKey].([]interface{})
+	require.True(t, ok)
+	play, ok := plays[0].(map[string]interface{})
+	require.True(t, ok)
+	tasks, ok := play["tasks"].([]interface{})
+	require.True(t, ok)
+	return tasks
+}
+
+func getTask(t *testing.T, tasks []interface{}, name string) map[string]interface{} {
+	for _, item := range tasks {
+		if task, ok := item.(map[string]interface{}); ok && task["name"] == name {
+			return task
+		}
+	}
+	require.Failf(t, "task not found", "task %s not found", name)
+	return nil
+}
+
+func getModule(t *testing.T, task map[string]interface{}, module string) map[string]interface{} {
+	args, ok := task[module].(map[string]interface{})
+	require.True(t, ok, "module %s not found", module)
+	return args
+}
+
+// TestResolver_Resolve tests the functions [Resolve()] and all the methods called by them
+func TestResolver_Resolve(t *testing.T) {
+	filePath := filepath.Join(fixturesDir, "playbook.yml")
+	tasks := getTasks(t, NewResolver(nil).Resolve(loadFixture(t, "playbook.yml"), filePath))
+
+	t.Run("tasks_order", func(t *testing.T) {
+		names := make([]interface{}, 0, len(tasks))
+		for _, task := range tasks {
+			names = append(names, task.(map[string]interface{})["name"])
+		}
+		require.Equal(t, []interface{}{
+			"Print environment",
+			"Create bucket",
+			"Bucket policy",
+			"Set bucket policy",
+			"Include extra tasks",
+			"Create queue",
+			"Include itself",
+			"Create log group",
+		}, names)
+	})
+
+	t.Run("role_variables", func(t *testing.T) {
+		bucket := getModule(t, getTask(t, tasks, "Create bucket"), "amazon.aws.s3_bucket")
+		require.Equal(t, "kics-bucket", bucket["name"])
+		require.Equal(t, "none", bucket["encryption"])
+		require.Equal(t, false, bucket["versioning"])
+		require.Equal(t, "us-east-1", bucket["region"])
+	})
+
+	t.Run("inventory_and_vars_files", func(t *testing.T) {
+		queue := getModule(t, getTask(t, tasks, "Create queue"), "community.aws.sqs_queue")
+		require.Equal(t, "kics-bucket-queue", queue["name"])
+		require.Equal(t, 30, queue["message_retention_period"])
+
+		logGroup := getModule(t, getTask(t, tasks, "Create log group"), "community.aws.cloudwatchlogs_log_group")
+		require.Equal(t, "app-logs", logGroup["log_group_name"])
+		require.Equal(t, "alias/kics", logGroup["kms_key_id"])
+	})
+
+	t.Run("unresolved_expressions", func(t *testing.T) {
+		policy := getModule(t, getTask(t, tasks, "Set bucket policy"), "amazon.aws.s3_bucket")
+		require.Equal(t, "{{ lookup('file', 'policy.json') }}", policy["policy"])
+	})
+
+	t.Run("origin", func(t *testing.T) {
+		bucket := getTask(t, tasks, "Create bucket")
+		require.Equal(t, filepath.Join(fixturesDir, "roles", "s3", "tasks", "main.yml"), bucket[originKey])
+		require.Equal(t, filepath.Join(fixturesDir, "tasks", "extra.yml"), getTask(t, tasks, "Create queue")[originKey])
+		require.NotContains(t, getTask(t, tasks, "Create log group"), originKey)
+	})
+}
+
+// TestResolver_Resolve_Lines tests that the line information of the tasks points to the files where they are defined
+func TestResolver_Resolve_Lines(t *testing.T) {
+	doc := NewResolver(nil).Resolve(loadFixture(t, "playbook.yml"), filepath.Join(fixturesDir, "playbook.yml"))
+	playLines := getArr(doc[linesKey].(map[string]interface{}), defaultKey)[0].(map[string]interface{})
+	arr := getArr(playLines, "_kics_tasks")
+	require.Len(t, arr, len(getTasks(t, doc)))
+
+	lineOf := func(idx int, key string) interface{} {
+		return arr[idx].(map[string]interface{})["_kics_"+key].(map[string]interface{})[lineKey]
+	}
+	// 'Set bucket policy' is the first task of policy.yml and 'Create log group' the second task of the play
+	require.Equal(t, float64(1), lineOf(3, "name"))
+	require.Equal(t, float64(13), lineOf(7, "name"))
+
+	bucket := getTask(t, getTasks(t, doc), "Create bucket")
+	encryption := bucket["amazon.aws.s3_bucket"].(map[string]interface{})[linesKey].(map[string]interface{})
+	require.Equal(t, float64(4), encryption["_kics_encryption"].(map[string]interface{})[lineKey])
+}
+
+// TestResolver_Resolve_RoleTasks tests that task files of roles are rendered with the variables of the role
+func TestResolver_Resolve_RoleTasks(t *testing.T) {
+	name := "roles/s3/tasks/main.yml"
+	doc := NewResolver(nil).Resolve(loadFixture(t, name), filepath.Join(fixturesDir, filepath.FromSlash(name)))
+
+	tasks := doc[playbookKey].([]interface{})
+	require.Len(t, tasks, 3)
+
+	bucket := getModule(t, getTask(t, tasks, "Create bucket"), "amazon.aws.s3_bucket")
+	require.Equal(t, "AES256", bucket["encryption"])
+	require.Equal(t, "{{ bucket_name }}", bucket["name"])
+}
+
+// TestResolver_Resolve_NotPlaybook tests that other documents are not changed
+func TestResolver_Resolve_NotPlaybook(t *testing.T) {
+	name := "tasks/extra.yml"
+	doc := NewResolver(nil).Resolve(loadFixture(t, name), filepath.Join(fixturesDir, filepath.FromSlash(name)))
+	tasks := doc[playbookKey].([]interface{})
+	require.Len(t, tasks, 2)
+	require.Equal(t, "{{ bucket_name }}-queue", getModule(t, getTask(t, tasks, "Create queue"), "community.aws.sqs_queue")["name"])
+}
+
+// Test_hostPatterns tests the functions [hostPatterns()] and all the methods called by them
+func Test_hostPatterns(t *testing.T) {
+	require.Equal(t, []string{"web", "db", "staging"}, hostPatterns("web:db,!staging"))
+	require.Equal(t, []string{"web"}, hostPatterns([]interface{}{"web", "app*"}))
+	require.Equal(t, []string{}, hostPatterns(nil))
+}
@@ -0,0 +1,218 @@
+package ansible
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const maxRenderDepth = 10
+
+var (
+	expressionRegex = regexp.MustCompile(`\{\{-?\s*(.*?)\s*-?\}\}`)
+	variableRegex   = regexp.MustCompile(`^[A-Za-z_]\w*((\.\w+)|(\[\s*('[^']*'|"[^"]*"|\d+)\s*\]))*$`)
+	segmentRegex    = regexp.MustCompile(`\.(\w+)|\[\s*'([^']*)'\s*\]|\[\s*"([^"]*)"\s*\]|\[\s*(\d+)\s*\]`)
+	filterRegex     = regexp.MustCompile(`^(\w+)\s*(?:\((.*)\))?$`)
+)
+
+// variables holds the variables available to the tasks of a play, by name
+type variables map[string]interface{}
+
+// render replaces the Jinja expressions of value that only use variables and simple filters
+// (default, bool, int, string, lower, upper and trim), the expressions that can not be evaluated are kept.
+// When the whole value is a single expression the value of the variable keeps its type, as Ansible does
+func (v variables) render(value string, depth int) interface{} {
+	if depth > maxRenderDepth || !strings.Contains(value, "{{") {
+		return value
+	}
+
+	if match := expressionRegex.FindStringSubmatch(value); match != nil && strings.TrimSpace(value) == match[0] {
+		if result, ok := v.evaluate(match[1], depth); ok {
+			return result
+		}
+		return value
+	}
+
+	return expressionRegex.ReplaceAllStringFunc(value, func(expression string) string {
+		result, ok := v.evaluate(expressionRegex.FindStringSubmatch(expression)[1], depth)
+		if !ok {
+			return expression
+		}
+		str, ok := toString(result)
+		if !ok {
+			return expression
+		}
+		return str
+	})
+}
+
+// evaluate returns the value of an expression made of a variable or a literal followed by filters
+func (v variables) evaluate(expression string, depth int) (interface{}, bool) {
+	parts := splitFilters(expression)
+	value, defined := v.operand(parts[0], depth)
+
+	for _, filter := range parts[1:] {
+		match := filterRegex.FindStringSubmatch(filter)
+		if match == nil {
+			return nil, false
+		}
+		var ok bool
+		if value, defined, ok = v.applyFilter(match[1], match[2], value, defined, depth); !ok {
+			return nil, false
+		}
+	}
+	return value, defined
+}
+
+// operand returns the value of a literal or a variable and whether it is defined
+func (v variables) operand(operand string, depth int) (interface{}, bool) {
+	if literal, ok := parseLiteral(operand); ok {
+		return literal, true
+	}
+	if !variableRegex.MatchString(operand) {
+		return nil, false
+	}
+
+	name := operand
+	if idx := strings.IndexAny(operand, ".["); idx >= 0 {
+		name = operand[:idx]
+	}
+	current, ok := v[name]
+	if !ok {
+		return nil, false
+	}
+
+	for _, segment := range segmentRegex.FindAllStringSubmatch(operand[len(name):], -1) {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			key := segment[1] + segment[2] + segment[3] + segment[4]
+			if current, ok = node[key]; !ok {
+				return nil, false
+			}
+		case []interface{}:
+			idx, err := strconv.Atoi(segment[4])
+			if err != nil || idx >= len(node) {
+				return nil, false
+			}
+			current = node[idx]
+		default:
+			return nil, false
+		}
+	}
+
+	if str, ok := current.(string); ok {
+		return v.render(str, depth+1), true
+	}
+	return current, true
+}
+
+func (v variables) applyFilter(name, args string, value interface{}, defined bool, depth int) (result interface{}, isDefined, ok bool) {
+	if name == "default" || name == "d" {
+		if defined {
+			return value, true, true
+		}
+		arg := strings.TrimSpace(strings.SplitN(args, ",", 2)[0]) //nolint:gomnd
+		value, defined = v.operand(arg, depth)
+		return value, defined, defined
+	}
+	if !defined {
+		return nil, false, false
+	}
+
+	switch name {
+	case "bool":
+		str, _ := toString(value)
+		switch strings.ToLower(str) {
+		case "true", "yes", "on", "1":
+			return true, true, true
+		}
+		return false, true, true
+	case "int":
+		str, _ := toString(value)
+		n, err := strconv.Atoi(strings.TrimSpace(str))
+		if err != nil {
+			n = 0
+		}
+		return n, true, true
+	case "string":
+		str, ok := toString(value)
+		return str, true, ok
+	case "lower", "upper", "trim":
+		str, ok := value.(string)
+		if !ok {
+			return nil, false, false
+		}
+		return map[string]func(string) string{
+			"lower": strings.ToLower,
+			"upper": strings.ToUpper,
+			"trim":  strings.TrimSpace,
+		}[name](str), true, true
+	}
+	return nil, false, false
+}
+
+// splitFilters splits the expression by the filter separator ('|') outside strings and parentheses
+func splitFilters(expression string) []string {
+	parts := make([]string, 0)
+	var quote rune
+	depth, start := 0, 0
+	for idx, c := range expression {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == '|' && depth == 0:
+			parts = append(parts, strings.TrimSpace(expression[start:idx]))
+			start = idx + 1
+		}
+	}
+	return append(parts, strings.TrimSpace(expression[start:]))
+}
+
+// parseLiteral parses strings, numbers and booleans literals
+func parseLiteral(literal string) (interface{}, bool) {
+	if len(literal) >= 2 && (literal[0] == '\'' || literal[0] == '"') && literal[len(literal)-1] == literal[0] {
+		return literal[1 : len(literal)-1], true
+	}
+	switch literal {
+	case "true", "True":
+		return true, true
+	case "false", "False":
+		return false, true
+	}
+	if n, err := strconv.Atoi(literal); err == nil {
+		return n, true
+	}
+	if f, err := strconv.ParseFloat(literal, 64); err == nil {
+		return f, true
+	}
+	return nil, false
+}
+
+// toString returns the value as Jinja prints it, maps and lists are not converted
+func toString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case bool:
+		if v {
+			return "True", true
+		}
+		return "False", true
+	case int:
+		return strconv.Itoa(v), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case nil:
+		return "", true
+	}
+	return fmt.Sprint(value), false
+}
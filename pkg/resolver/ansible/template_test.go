@@ -0,0 +1,60 @@
+package ansible
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test_variables_render tests the functions [render()] and all the methods called by them
+func Test_variables_render(t *testing.T) {
+	vars := variables{
+		"name":     "kics",
+		"enabled":  "yes",
+		"port":     8080,
+		"tags":     map[string]interface{}{"env": "prod", "owner": "{{ name }}"},
+		"subnets":  []interface{}{"10.0.0.0/24", "10.0.1.0/24"},
+		"loop":     "{{ loop }}",
+		"empty":    "",
+		"upper_id": "ABC",
+	}
+
+	tests := []struct {
+		name  string
+		value string
+		want  interface{}
+	}{
+		{name: "no_expression", value: "plain", want: "plain"},
+		{name: "variable", value: "{{ name }}", want: "kics"},
+		{name: "typed_variable", value: "{{ port }}", want: 8080},
+		{name: "interpolation", value: "{{ name }}-bucket:{{ port }}", want: "kics-bucket:8080"},
+		{name: "attribute", value: "{{ tags.env }}", want: "prod"},
+		{name: "item", value: "{{ tags['owner'] }}", want: "kics"},
+		{name: "index", value: "{{ subnets[1] }}", want: "10.0.1.0/24"},
+		{name: "whitespace_control", value: "{{- name -}}", want: "kics"},
+		{name: "bool_filter", value: "{{ enabled | bool }}", want: true},
+		{name: "int_filter", value: "{{ '42' | int }}", want: 42},
+		{name: "lower_filter", value: "{{ upper_id | lower }}", want: "abc"},
+		{name: "default_filter", value: "{{ missing | default('none') }}", want: "none"},
+		{name: "default_alias", value: "{{ missing | d(name) }}", want: "kics"},
+		{name: "defined_with_default", value: "{{ empty | default('x') }}", want: ""},
+		{name: "undefined", value: "{{ missing }}", want: "{{ missing }}"},
+		{name: "undefined_interpolation", value: "{{ name }}-{{ missing }}", want: "kics-{{ missing }}"},
+		{name: "unknown_filter", value: "{{ name | to_json }}", want: "{{ name | to_json }}"},
+		{name: "function", value: "{{ lookup('env', 'HOME') }}", want: "{{ lookup('env', 'HOME') }}"},
+		{name: "list_in_string", value: "subnets: {{ subnets }}", want: "subnets: {{ subnets }}"},
+		{name: "recursive_variable", value: "{{ loop }}", want: "{{ loop }}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, vars.render(tt.value, 0))
+		})
+	}
+}
+
+// Test_splitFilters tests the functions [splitFilters()] and all the methods called by them
+func Test_splitFilters(t *testing.T) {
+	require.Equal(t, []string{"name", "default('a|b')", "lower"}, splitFilters("name | default('a|b') | lower"))
+	require.Equal(t, []string{"name"}, splitFilters("name"))
+}
@@ -0,0 +1,258 @@
+package ansible
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/pbkdf2"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	vaultHeader      = "$ANSIBLE_VAULT"
+	vaultTag         = "!vault"
+	vaultCipher      = "AES256"
+	vaultIterations  = 10000
+	vaultKeyLength   = 32
+	vaultIVLength    = 16
+	defaultVaultID   = "default"
+	vaultHeaderParts = 3
+	// minVaultedValueLength is the length of the shortest decrypted value redacted, shorter values are too common
+	minVaultedValueLength = 4
+)
+
+var vaultRegex = regexp.MustCompile(`^\s*\$ANSIBLE_VAULT;`)
+
+// Vault decrypts the content encrypted with Ansible Vault (AES256) with the passwords of the vault ids.
+// Decrypted content is only kept in memory
+type Vault struct {
+	ids       []string
+	passwords map[string][]byte
+}
+
+// VaultedValue is a value decrypted from an inline '!vault' string, Key is the key of the value
+// and Line the line of the key in the vaulted source
+type VaultedValue struct {
+	Key   string
+	Value string
+	Line  int
+}
+
+// NewVault creates a Vault with the password files given, each password file may be prefixed by its vault id
+// ('<vault-id>@<path>'), otherwise the password is used for the default vault id
+func NewVault(passwordFiles []string) (*Vault, error) {
+	vault := &Vault{
+		ids:       make([]string, 0, len(passwordFiles)),
+		passwords: make(map[string][]byte, len(passwordFiles)),
+	}
+	for _, passwordFile := range passwordFiles {
+		id, path := defaultVaultID, passwordFile
+		if idx := strings.Index(passwordFile, "@"); idx > 0 {
+			id, path = passwordFile[:idx], passwordFile[idx+1:]
+		}
+		password, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read Ansible Vault password file %s", path)
+		}
+		password = bytes.TrimSpace(password)
+		if len(password) == 0 {
+			return nil, fmt.Errorf("ansible Vault password file %s is empty", path)
+		}
+		if _, ok := vault.passwords[id]; !ok {
+			vault.ids = append(vault.ids, id)
+		}
+		vault.passwords[id] = password
+	}
+	return vault, nil
+}
+
+// IsVaulted verifies if the content is encrypted with Ansible Vault
+func IsVaulted(content []byte) bool {
+	return vaultRegex.Match(content)
+}
+
+// Decrypt decrypts the content encrypted with Ansible Vault, the password of the vault id of the content is tried first,
+// followed by the other passwords, as ansible-vault does
+func (v *Vault) Decrypt(content []byte) ([]byte, error) {
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	header := strings.Split(strings.TrimSpace(lines[0]), ";")
+	if len(header) < vaultHeaderParts || header[0] != vaultHeader {
+		return nil, errors.New("invalid Ansible Vault header")
+	}
+	if header[2] != vaultCipher {
+		return nil, fmt.Errorf("unsupported Ansible Vault cipher %s", header[2])
+	}
+
+	salt, mac, ciphertext, err := decodeVaultBody(lines[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	ids := v.ids
+	if len(header) > vaultHeaderParts {
+		ids = append([]string{header[3]}, v.ids...)
+	}
+	for _, id := range ids {
+		password, ok := v.passwords[id]
+		if !ok {
+			continue
+		}
+		if plaintext, ok := decryptAES256(password, salt, mac, ciphertext); ok {
+			return plaintext, nil
+		}
+	}
+	return nil, errors.New("no Ansible Vault password was able to decrypt the content")
+}
+
+// DecryptValues decrypts in place the '!vault' strings of the YAML node and returns the decrypted values,
+// the strings that can not be decrypted are kept
+func (v *Vault) DecryptValues(node *yaml.Node) []VaultedValue {
+	values := make([]VaultedValue, 0)
+	var visit func(node, key *yaml.Node)
+	visit = func(node, key *yaml.Node) {
+		switch node.Kind {
+		case yaml.DocumentNode, yaml.SequenceNode:
+			for _, child := range node.Content {
+				visit(child, key)
+			}
+		case yaml.MappingNode:
+			for idx := 0; idx+1 < len(node.Content); idx += 2 {
+				visit(node.Content[idx+1], node.Content[idx])
+			}
+		case yaml.ScalarNode:
+			if node.Tag != vaultTag {
+				return
+			}
+			plaintext, err := v.Decrypt([]byte(node.Value))
+			if err != nil {
+				log.Warn().Msgf("failed to decrypt vaulted value in line %d: %s", node.Line, err)
+				return
+			}
+			value := VaultedValue{Value: string(plaintext), Line: node.Line}
+			if key != nil {
+				value.Key, value.Line = key.Value, key.Line
+			}
+			values = append(values, value)
+			node.Tag, node.Value, node.Style = "!!str", string(plaintext), 0
+		}
+	}
+	visit(node, nil)
+	return values
+}
+
+// MarkVaulted sets the lines of the YAML node decrypted from a vaulted file to the line of the vault header,
+// since the lines of the decrypted content do not exist in the file, and returns its values
+func MarkVaulted(node *yaml.Node) []string {
+	var visit func(node *yaml.Node)
+	visit = func(node *yaml.Node) {
+		node.Line = 1
+		for _, child := range node.Content {
+			visit(child)
+		}
+	}
+	visit(node)
+	return VaultedStrings(node)
+}
+
+// decodeVaultBody decodes the hexadecimal body of a vault, made of the salt, the HMAC and the ciphertext
+func decodeVaultBody(lines []string) (salt, mac, ciphertext []byte, err error) {
+	body, err := hex.DecodeString(strings.Join(strings.Fields(strings.Join(lines, "")), ""))
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "invalid Ansible Vault content")
+	}
+	parts := strings.Split(string(body), "\n")
+	if len(parts) != vaultHeaderParts {
+		return nil, nil, nil, errors.New("invalid Ansible Vault content")
+	}
+	decoded := make([][]byte, 0, len(parts))
+	for _, part := range parts {
+		value, err := hex.DecodeString(strings.TrimSpace(part))
+		if err != nil {
+			return nil, nil, nil, errors.Wrap(err, "invalid Ansible Vault content")
+		}
+		decoded = append(decoded, value)
+	}
+	return decoded[0], decoded[1], decoded[2], nil
+}
+
+// decryptAES256 decrypts the ciphertext with the keys derived from the password, returns false when the HMAC does not match
+func decryptAES256(password, salt, mac, ciphertext []byte) ([]byte, bool) {
+	key := pbkdf2.Key(password, salt, vaultIterations, 2*vaultKeyLength+vaultIVLength, sha256.New)
+	cipherKey, hmacKey, iv := key[:vaultKeyLength], key[vaultKeyLength:2*vaultKeyLength], key[2*vaultKeyLength:]
+
+	hash := hmac.New(sha256.New, hmacKey)
+	hash.Write(ciphertext) //nolint:errcheck
+	if !hmac.Equal(hash.Sum(nil), mac) {
+		return nil, false
+	}
+
+	block, err := aes.NewCipher(cipherKey)
+	if err != nil {
+		return nil, false
+	}
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCTR(block, iv).XORKeyStream(plaintext, ciphertext)
+
+	// the plaintext is padded with PKCS#7
+	if len(plaintext) == 0 {
+		return plaintext, true
+	}
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > aes.BlockSize || padding > len(plaintext) {
+		return nil, false
+	}
+	return plaintext[:len(plaintext)-padding], true
+}
+
+// IsRedactable returns true if the decrypted value is redacted from the results, only the strings with at least
+// minVaultedValueLength characters are, booleans, numbers and short strings would replace unrelated values
+func IsRedactable(value string) bool {
+	return isRedactableNode(&yaml.Node{Kind: yaml.ScalarNode, Value: value})
+}
+
+func isRedactableNode(node *yaml.Node) bool {
+	return len(node.Value) >= minVaultedValueLength && node.ShortTag() == "!!str"
+}
+
+// VaultedStrings returns the string values (not the keys) of the YAML node that are redacted from the results,
+// which are all vaulted when the whole file is vaulted
+func VaultedStrings(node *yaml.Node) []string {
+	values := make(map[string]bool)
+	var visit func(node *yaml.Node)
+	visit = func(node *yaml.Node) {
+		switch node.Kind {
+		case yaml.ScalarNode:
+			if isRedactableNode(node) {
+				values[node.Value] = true
+			}
+		case yaml.MappingNode:
+			for idx := 1; idx < len(node.Content); idx += 2 {
+				visit(node.Content[idx])
+			}
+		default:
+			for _, child := range node.Content {
+				visit(child)
+			}
+		}
+	}
+	visit(node)
+
+	result := make([]string, 0, len(values))
+	for value := range values {
+		result = append(result, value)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,320 @@
+package openapi
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	refKey    = "$ref"
+	linesKey  = "_kics_lines"
+	originKey = "_kics_ref"
+	maxDepth  = 32
+)
+
+// Resolver dereferences the '$ref' pointers of an OpenAPI or AsyncAPI document
+// Internal pointers (#/components/...) and file relative pointers (schemas.yaml#/Pet) are followed
+// and the referenced content is placed next to the '$ref' key, so queries can still check the pointer itself.
+// References to the object where they are declared or to one of its parents are circular and are not resolved.
+// Every resolved object keeps the line information of its definition and the file where it was defined
+// is stored in the '_kics_ref' key. Pointers declared alongside other properties are not resolved, since
+// those properties make the reference object invalid
+// Only the files inside the roots are read, the pointers to other files (e.g. '/etc/passwd') are not followed
+type Resolver struct {
+	files map[string]map[string]interface{}
+	roots []string
+}
+
+// NewResolver creates a new Resolver's reference, roots are the scanned paths, the directory of a scanned file is
+// its root. The root of a document is its directory when there are no roots
+func NewResolver(roots ...string) *Resolver {
+	absRoots := make([]string, 0, len(roots))
+	for _, root := range roots {
+		absRoot, err := filepath.Abs(root)
+		if err != nil {
+			continue
+		}
+		if info, err := os.Stat(absRoot); err == nil && !info.IsDir() {
+			absRoot = filepath.Dir(absRoot)
+		}
+		absRoots = append(absRoots, absRoot)
+	}
+	return &Resolver{
+		files: make(map[string]map[string]interface{}),
+		roots: absRoots,
+	}
+}
+
+// IsOpenAPI returns true if the document is an OpenAPI (or Swagger) specification
+func IsOpenAPI(doc map[string]interface{}) bool {
+	if _, ok := doc["openapi"].(string); ok {
+		return true
+	}
+	_, ok := doc["swagger"].(string)
+	return ok
+}
+
+// IsAsyncAPI returns true if the document is an AsyncAPI specification
+func IsAsyncAPI(doc map[string]interface{}) bool {
+	_, ok := doc["asyncapi"].(string)
+	return ok
+}
+
+// Resolve dereferences all '$ref' pointers of the document placed in filePath
+// documents that are not OpenAPI or AsyncAPI specifications are returned untouched
+func (r *Resolver) Resolve(doc model.Document, filePath string) model.Document {
+	if !IsOpenAPI(doc) && !IsAsyncAPI(doc) {
+		return doc
+	}
+	// pointers are always resolved against the original content of the file
+	r.files[filePath] = deepCopy(map[string]interface{}(doc)).(map[string]interface{})
+
+	for key, value := range doc {
+		if key == linesKey {
+			continue
+		}
+		doc[key] = r.resolveValue(value, filePath, "/"+escapePointer(key), []string{})
+	}
+	return doc
+}
+
+// resolveValue resolves the references found in value
+// location is the JSON pointer of value inside filePath and stack holds the references being resolved
+func (r *Resolver) resolveValue(value interface{}, filePath, location string, stack []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if ref, ok := v[refKey].(string); ok && isReferenceObject(v) {
+			return r.resolveRef(v, ref, filePath, location, stack)
+		}
+		for key, child := range v {
+			if key == linesKey {
+				continue
+			}
+			v[key] = r.resolveValue(child, filePath, location+"/"+escapePointer(key), stack)
+		}
+	case []interface{}:
+		for idx, child := range v {
+			v[idx] = r.resolveValue(child, filePath, location+"/"+strconv.Itoa(idx), stack)
+		}
+	}
+	return value
+}
+
+// resolveRef returns the content pointed by ref merged with the object holding the pointer
+func (r *Resolver) resolveRef(obj map[string]interface{}, ref, filePath, location string, stack []string) interface{} {
+	targetFile, pointer := splitRef(ref, filePath)
+	if targetFile == "" {
+		return obj
+	}
+	if targetFile != filePath && !r.inRoots(targetFile, filePath) {
+		log.Warn().Msgf("Reference '%s' in file %s not resolved, it points outside the scanned paths", ref, filePath)
+		return obj
+	}
+
+	id := targetFile + "#" + pointer
+	if len(stack) >= maxDepth || contains(stack, id) || (targetFile == filePath && isAncestor(pointer, location)) {
+		log.Debug().Msgf("skipping circular reference '%s' in file %s", ref, filePath)
+		return obj
+	}
+
+	root := r.getFile(targetFile)
+	if root == nil {
+		return obj
+	}
+
+	target, ok := lookup(root, pointer).(map[string]interface{})
+	if !ok {
+		log.Debug().Msgf("failed to resolve reference '%s' in file %s", ref, filePath)
+		return obj
+	}
+
+	resolved := r.resolveValue(deepCopy(target), targetFile, pointer, append(stack, id)).(map[string]interface{})
+	resolvedLines := resolved[linesKey]
+	for key, value := range obj {
+		resolved[key] = value
+	}
+	resolved[linesKey] = mergeLines(resolvedLines, obj[linesKey])
+	resolved[originKey] = targetFile
+
+	return resolved
+}
+
+// inRoots returns true if the file is inside one of the roots, or inside the directory of the file holding the
+// pointer when there are no roots
+func (r *Resolver) inRoots(targetFile, filePath string) bool {
+	roots := r.roots
+	if len(roots) == 0 {
+		root, err := filepath.Abs(filepath.Dir(filePath))
+		if err != nil {
+			return false
+		}
+		roots = []string{root}
+	}
+	absTarget, err := filepath.Abs(targetFile)
+	if err != nil {
+		return false
+	}
+	for _, root := range roots {
+		rel, err := filepath.Rel(root, absTarget)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isReferenceObject returns true if the object only declares the '$ref' property
+func isReferenceObject(obj map[string]interface{}) bool {
+	for key := range obj {
+		if key != refKey && key != linesKey {
+			return false
+		}
+	}
+	return true
+}
+
+// getFile returns the content of the file with line information, caching it for the next references
+func (r *Resolver) getFile(filePath string) map[string]interface{} {
+	if content, ok := r.files[filePath]; ok {
+		return content
+	}
+
+	r.files[filePath] = nil
+	content, err := os.ReadFile(filepath.Clean(filePath))
+	if err != nil {
+		log.Debug().Msgf("failed to read referenced file %s: %s", filePath, err)
+		return nil
+	}
+
+	// JSON is a subset of YAML, so the YAML decoder sets line information on both
+	doc := model.Document{}
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		log.Debug().Msgf("failed to parse referenced file %s: %s", filePath, err)
+		return nil
+	}
+
+	r.files[filePath] = doc
+	return r.files[filePath]
+}
+
+// splitRef splits the reference into the file it points to and the JSON pointer inside that file
+// remote references are not followed and return an empty file
+func splitRef(ref, filePath string) (targetFile, pointer string) {
+	parts := strings.SplitN(ref, "#", 2)
+	if len(parts) == 2 && parts[1] != "" {
+		pointer = "/" + strings.TrimPrefix(parts[1], "/")
+	}
+
+	if parts[0] == "" {
+		return filePath, pointer
+	}
+	if u, err := url.Parse(parts[0]); err != nil || u.Scheme != "" || u.Host != "" {
+		return "", ""
+	}
+	if filepath.IsAbs(parts[0]) {
+		return filepath.Clean(parts[0]), pointer
+	}
+	return filepath.Join(filepath.Dir(filePath), filepath.FromSlash(parts[0])), pointer
+}
+
+// isAncestor returns true if the JSON pointer points to location or to one of its parents
+func isAncestor(pointer, location string) bool {
+	return pointer == "" || location == pointer || strings.HasPrefix(location, pointer+"/")
+}
+
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+// lookup returns the value identified by the JSON pointer
+func lookup(root interface{}, pointer string) interface{} {
+	current := root
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		if token == "" {
+			continue
+		}
+		if unescaped, err := url.PathUnescape(token); err == nil {
+			token = unescaped
+		}
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+
+		switch v := current.(type) {
+		case map[string]interface{}:
+			current = v[token]
+		case []interface{}:
+			idx, err := strconv.Atoi(token)
+			if err != nil || idx < 0 || idx >= len(v) {
+				return nil
+			}
+			current = v[idx]
+		default:
+			return nil
+		}
+	}
+	return current
+}
+
+// mergeLines adds the line information of the object holding the pointer to the line information
+// of the resolved content, so both the '$ref' key and the resolved keys keep their lines
+func mergeLines(resolved, obj interface{}) map[string]interface{} {
+	lines := make(map[string]interface{})
+	copyLines(lines, resolved)
+	copyLines(lines, obj)
+	return lines
+}
+
+func copyLines(dst map[string]interface{}, lines interface{}) {
+	switch v := lines.(type) {
+	case map[string]model.LineObject:
+		for key, value := range v {
+			dst[key] = value
+		}
+	case map[string]interface{}:
+		for key, value := range v {
+			dst[key] = value
+		}
+	}
+}
+
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		cp := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			cp[key] = deepCopy(child)
+		}
+		return cp
+	case model.Document:
+		return deepCopy(map[string]interface{}(v))
+	case []interface{}:
+		cp := make([]interface{}, len(v))
+		for idx, child := range v {
+			cp[idx] = deepCopy(child)
+		}
+		return cp
+	case map[string]model.LineObject:
+		cp := make(map[string]model.LineObject, len(v))
+		for key, child := range v {
+			cp[key] = child
+		}
+		return cp
+	default:
+		return value
+	}
+}
+
+func contains(stack []string, id string) bool {
+	for _, s := range stack {
+		if s == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,260 @@
+package openapi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+var fixturesDir = filepath.FromSlash("../../../test/fixtures/openapi_ref")
+
+func loadFixture(t *testing.T, name string) model.Document {
+	content, err := os.ReadFile(filepath.Join(fixturesDir, name))
+	require.NoError(t, err)
+	doc := model.Document{}
+	require.NoError(t, yaml.Unmarshal(content, &doc))
+	return doc
+}
+
+func getMap(t *testing.T, value interface{}, keys ...string) map[string]interface{} {
+	current := value
+	for _, key := range keys {
+		m, ok := current.(map[string]interface{})
+		if doc, isDoc := current.(model.Document); isDoc {
+			m, ok = map[string]interface{}(doc), true
+		}
+		require.True(t, ok, "key %s is not an object", key)
+		current = m[key]
+	}
+	m, ok := current.(map[string]interface{})
+	require.True(t, ok)
+	return m
+}
+
+// TestResolver_Resolve tests the functions [Resolve()] and all the methods called by them
+func TestResolver_Resolve(t *testing.T) {
+	filePath := filepath.Join(fixturesDir, "openapi.yaml")
+	doc := NewResolver().Resolve(loadFixture(t, "openapi.yaml"), filePath)
+
+	responses := []string{"paths", "/pets", "get", "responses"}
+
+	t.Run("internal_reference", func(t *testing.T) {
+		schema := getMap(t, doc, append(responses, "200", "content", "application/json", "schema")...)
+		require.Equal(t, "#/components/schemas/Pet", schema["$ref"])
+		require.Equal(t, "object", schema["type"])
+		require.Equal(t, filePath, schema["_kics_ref"])
+
+		name := getMap(t, schema, "properties", "name")
+		require.Equal(t, "string", name["type"])
+	})
+
+	t.Run("circular_reference", func(t *testing.T) {
+		schema := getMap(t, doc, append(responses, "200", "content", "application/json", "schema")...)
+		items := getMap(t, schema, "properties", "children", "items")
+		require.Equal(t, "#/components/schemas/Pet", items["$ref"])
+		require.NotContains(t, items, "type")
+	})
+
+	t.Run("file_reference", func(t *testing.T) {
+		schema := getMap(t, doc, append(responses, "default", "content", "application/json", "schema")...)
+		require.Equal(t, "schemas.yaml#/Error", schema["$ref"])
+		require.Equal(t, filepath.Join(fixturesDir, "schemas.yaml"), schema["_kics_ref"])
+
+		code := getMap(t, schema, "properties", "code")
+		require.Equal(t, "int32", code["format"])
+
+		// local references inside the referenced file point to the referenced file
+		message := getMap(t, schema, "properties", "message")
+		require.Equal(t, 100.0, message["maxLength"])
+	})
+
+	t.Run("components_keep_pointers", func(t *testing.T) {
+		pet := getMap(t, doc, "components", "schemas", "Pet")
+		require.NotContains(t, pet, "_kics_ref")
+	})
+}
+
+// TestResolver_Resolve_Lines tests that resolved content keeps the lines where it was defined
+func TestResolver_Resolve_Lines(t *testing.T) {
+	filePath := filepath.Join(fixturesDir, "openapi.yaml")
+	doc := NewResolver().Resolve(loadFixture(t, "openapi.yaml"), filePath)
+
+	schema := getMap(t, doc, "paths", "/pets", "get", "responses", "default", "content", "application/json", "schema")
+	lines := getMap(t, schema, "_kics_lines")
+	require.Contains(t, lines, "_kics_$ref")
+	require.Contains(t, lines, "_kics_properties")
+
+	codeLines := getMap(t, schema, "properties", "code", "_kics_lines")
+	require.Equal(t, 6.0, getMap(t, codeLines, "_kics_format")["_kics_line"])
+}
+
+// TestResolver_Resolve_NotOpenAPI tests that documents that are not OpenAPI specifications are not changed
+func TestResolver_Resolve_NotOpenAPI(t *testing.T) {
+	doc := model.Document{
+		"Resources": map[string]interface{}{
+			"Bucket": map[string]interface{}{
+				"$ref": "#/Other",
+			},
+		},
+		"Other": map[string]interface{}{
+			"Type": "AWS::S3::Bucket",
+		},
+	}
+	got := NewResolver().Resolve(doc, "template.json")
+	require.NotContains(t, getMap(t, got, "Resources", "Bucket"), "Type")
+}
+
+// TestResolver_Resolve_AsyncAPI tests that pointers of AsyncAPI documents are resolved
+func TestResolver_Resolve_AsyncAPI(t *testing.T) {
+	doc := model.Document{
+		"asyncapi": "2.6.0",
+		"channels": map[string]interface{}{
+			"user/signedup": map[string]interface{}{
+				"subscribe": map[string]interface{}{
+					"message": map[string]interface{}{
+						"$ref": "#/components/messages/UserSignedUp",
+					},
+				},
+			},
+		},
+		"components": map[string]interface{}{
+			"messages": map[string]interface{}{
+				"UserSignedUp": map[string]interface{}{
+					"payload": map[string]interface{}{
+						"type": "object",
+					},
+				},
+			},
+		},
+	}
+	got := NewResolver().Resolve(doc, "asyncapi.yaml")
+	require.Contains(t, getMap(t, got, "channels", "user/signedup", "subscribe", "message"), "payload")
+}
+
+// TestResolver_Resolve_Siblings tests that pointers declared alongside other properties are not resolved
+func TestResolver_Resolve_Siblings(t *testing.T) {
+	doc := model.Document{
+		"openapi": "3.0.0",
+		"paths": map[string]interface{}{
+			"/pets": map[string]interface{}{
+				"$ref":        "#/components/pathItems/Pets",
+				"description": "pets",
+			},
+		},
+		"components": map[string]interface{}{
+			"pathItems": map[string]interface{}{
+				"Pets": map[string]interface{}{
+					"summary": "pets",
+				},
+			},
+		},
+	}
+	got := NewResolver().Resolve(doc, "openapi.yaml")
+	require.NotContains(t, getMap(t, got, "paths", "/pets"), "summary")
+}
+
+// TestResolver_Resolve_Roots tests that the pointers to files outside the roots are not followed
+func TestResolver_Resolve_Roots(t *testing.T) {
+	root := t.TempDir()
+	specs := filepath.Join(root, "specs")
+	require.NoError(t, os.MkdirAll(specs, os.ModePerm))
+	outside := filepath.Join(t.TempDir(), "secret.yaml")
+	require.NoError(t, os.WriteFile(outside, []byte("Secret:\n  type: string\n"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "common.yaml"), []byte("Pet:\n  type: object\n"), os.ModePerm))
+
+	newDoc := func() model.Document {
+		return model.Document{
+			"openapi": "3.0.0",
+			"components": map[string]interface{}{
+				"schemas": map[string]interface{}{
+					"Absolute": map[string]interface{}{"$ref": filepath.ToSlash(outside) + "#/Secret"},
+					"Parent":   map[string]interface{}{"$ref": "../common.yaml#/Pet"},
+				},
+			},
+		}
+	}
+	filePath := filepath.Join(specs, "openapi.yaml")
+
+	tests := []struct {
+		name       string
+		roots      []string
+		wantParent bool
+	}{
+		{name: "document_directory", roots: nil, wantParent: false},
+		{name: "scanned_file", roots: []string{filePath}, wantParent: false},
+		{name: "scanned_directory", roots: []string{root}, wantParent: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := NewResolver(tt.roots...).Resolve(newDoc(), filePath)
+			require.NotContains(t, getMap(t, doc, "components", "schemas", "Absolute"), "type")
+			parent := getMap(t, doc, "components", "schemas", "Parent")
+			if tt.wantParent {
+				require.Equal(t, "object", parent["type"])
+			} else {
+				require.NotContains(t, parent, "type")
+			}
+		})
+	}
+}
+
+// Test_splitRef tests the functions [splitRef()] and all the methods called by them
+func Test_splitRef(t *testing.T) {
+	tests := []struct {
+		name        string
+		ref         string
+		wantFile    string
+		wantPointer string
+	}{
+		{
+			name:        "internal",
+			ref:         "#/components/schemas/Pet",
+			wantFile:    filepath.FromSlash("specs/openapi.yaml"),
+			wantPointer: "/components/schemas/Pet",
+		},
+		{
+			name:        "relative_file",
+			ref:         "../common/schemas.yaml#/Error",
+			wantFile:    filepath.FromSlash("common/schemas.yaml"),
+			wantPointer: "/Error",
+		},
+		{
+			name:        "whole_file",
+			ref:         "pet.json",
+			wantFile:    filepath.FromSlash("specs/pet.json"),
+			wantPointer: "",
+		},
+		{
+			name:        "remote",
+			ref:         "https://example.com/schemas.yaml#/Error",
+			wantFile:    "",
+			wantPointer: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFile, gotPointer := splitRef(tt.ref, filepath.FromSlash("specs/openapi.yaml"))
+			require.Equal(t, tt.wantFile, gotFile)
+			require.Equal(t, tt.wantPointer, gotPointer)
+		})
+	}
+}
+
+// Test_lookup tests the functions [lookup()] and all the methods called by them
+func Test_lookup(t *testing.T) {
+	root := map[string]interface{}{
+		"paths": map[string]interface{}{
+			"/pets/{id}": map[string]interface{}{
+				"parameters": []interface{}{"first", "second"},
+			},
+		},
+	}
+	require.Equal(t, "second", lookup(root, "/paths/~1pets~1%7Bid%7D/parameters/1"))
+	require.Nil(t, lookup(root, "/paths/missing/key"))
+	require.Equal(t, root, lookup(root, ""))
+}
@@ -8,7 +8,9 @@ import (
 	"github.com/Checkmarx/kics/internal/storage"
 	"github.com/Checkmarx/kics/internal/tracker"
 	"github.com/Checkmarx/kics/pkg/descriptions"
+	"github.com/Checkmarx/kics/pkg/engine/provider"
 	"github.com/Checkmarx/kics/pkg/progress"
+	"github.com/Checkmarx/kics/pkg/report"
 	"github.com/rs/zerolog/log"
 )
 
@@ -24,6 +26,8 @@ type Parameters struct {
 	ExcludeSeverities           []string
 	IncludeQueries              []string
 	InputData                   string
+	MarkdownLinkTemplate        string
+	MarkdownMaxSize             int
 	OutputName                  string
 	OutputPath                  string
 	Path                        []string
@@ -32,11 +36,34 @@ type Parameters struct {
 	QueriesPath                 string
 	LibrariesPath               string
 	ReportFormats               []string
+	ReportTemplates             []string
 	Platform                    []string
 	QueryExecTimeout            int
 	LineInfoPayload             bool
 	DisableSecrets              bool
 	SecretsRegexesPath          string
+	ShowSecrets                 bool
+	SecretsAllFiles             bool
+	SecretsDecodingDepth        int
+	SecretsIncludePaths         []string
+	SecretsExcludePaths         []string
+	SecretsHistory              bool
+	SecretsHistoryRange         string
+	SecretsHistorySince         string
+	AnsibleVaultPasswordFiles   []string
+	CRDPaths                    []string
+	DockerBuildArgs             []string
+	StdinType                   string
+	StdinFilename               string
+	StdinMaxSize                int
+	InsecureFetch               bool
+	Offline                     bool
+	FetchCachePath              string
+	FetchMirrors                []string
+	FetchRequireChecksum        bool
+	ArchiveDepth                int
+	ArchiveMaxFiles             int
+	ArchiveMaxSize              int
 	ChangedDefaultQueryPath     bool
 	ChangedDefaultLibrariesPath bool
 	ScanID                      string
@@ -50,8 +77,11 @@ type Client struct {
 	Tracker           *tracker.CITracker
 	Storage           *storage.MemoryStorage
 	ExcludeResultsMap map[string]bool
+	CustomReports     []*report.CustomReport
 	Printer           *consoleHelpers.Printer
 	ProBarBuilder     *progress.PbBuilder
+	// stdin is the provider of the content of stdin, read before the scan to choose its virtual filename
+	stdin *provider.StdinSourceProvider
 }
 
 // NewClient initializes the client with all the required parameters
@@ -70,12 +100,19 @@ func NewClient(params *Parameters, proBarBuilder *progress.PbBuilder, printer *c
 
 	excludeResultsMap := getExcludeResultsMap(params.ExcludeResults)
 
+	// the templates are loaded before the scan, so their errors do not wait for the results
+	customReports, err := report.NewCustomReports(params.ReportTemplates)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Client{
 		ScanParams:        params,
 		Tracker:           t,
 		ProBarBuilder:     proBarBuilder,
 		Storage:           store,
 		ExcludeResultsMap: excludeResultsMap,
+		CustomReports:     customReports,
 		Printer:           printer,
 	}, nil
 }
@@ -0,0 +1,83 @@
+package scan
+
+import (
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/Checkmarx/kics/pkg/report"
+)
+
+var (
+	// repositoryEnvs are the variables of the CI pipelines (GitHub Actions, GitLab CI, Azure Pipelines) with the name
+	// of the repository
+	repositoryEnvs = []string{"GITHUB_REPOSITORY", "CI_PROJECT_PATH", "BUILD_REPOSITORY_NAME"}
+	// commitEnvs are the variables of the CI pipelines with the SHA of the commit
+	commitEnvs = []string{"GITHUB_SHA", "CI_COMMIT_SHA", "BUILD_SOURCEVERSION"}
+)
+
+// markdownOptions returns the options of the Markdown report. The repository and the commit of the links are read
+// from the variables of the CI pipeline, or from the git repository of the first scanned path, which root is
+// the path the files of the links are relative to
+func (c *Client) markdownOptions() report.MarkdownOptions {
+	opts := report.MarkdownOptions{
+		LinkTemplate: c.ScanParams.MarkdownLinkTemplate,
+		MaxSize:      c.ScanParams.MarkdownMaxSize,
+	}
+	if opts.LinkTemplate == "" {
+		return opts
+	}
+
+	dir := "."
+	if len(c.ScanParams.Path) > 0 {
+		dir = c.ScanParams.Path[0]
+	}
+	if info, err := os.Stat(dir); err != nil {
+		dir = "."
+	} else if !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+
+	opts.RootPath = gitOutput(dir, "rev-parse", "--show-toplevel")
+	opts.Repository = firstEnv(repositoryEnvs)
+	if opts.Repository == "" {
+		opts.Repository = repositoryName(gitOutput(dir, "remote", "get-url", "origin"))
+	}
+	opts.Commit = firstEnv(commitEnvs)
+	if opts.Commit == "" {
+		opts.Commit = gitOutput(dir, "rev-parse", "HEAD")
+	}
+	return opts
+}
+
+func firstEnv(names []string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// gitOutput returns the output of the git command run in the directory, or an empty string when it fails
+func gitOutput(dir string, args ...string) string {
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).Output() //nolint:gosec
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// repositoryName returns the path of the repository of a remote URL (e.g. 'owner/name'), without its credentials
+func repositoryName(remote string) string {
+	name := remote
+	if parsed, err := url.Parse(remote); err == nil && parsed.Host != "" {
+		name = parsed.Path
+	} else if idx := strings.Index(remote, ":"); idx >= 0 {
+		// scp-like syntax: git@github.com:owner/name.git
+		name = remote[idx+1:]
+	}
+	return strings.TrimSuffix(strings.Trim(name, "/"), ".git")
+}
@@ -1,32 +1,78 @@
 package scan
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 
 	consoleHelpers "github.com/Checkmarx/kics/internal/console/helpers"
 	"github.com/Checkmarx/kics/pkg/analyzer"
 	"github.com/Checkmarx/kics/pkg/engine/provider"
+	"github.com/Checkmarx/kics/pkg/model"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 )
 
+const bytesPerMegabyte = 1 << 20
+
+// stdinFilenames are the default virtual filenames of the content of stdin by platform type
+var stdinFilenames = map[string]string{
+	"ansible":              "stdin.yaml",
+	"asyncapi":             "stdin.yaml",
+	"azureresourcemanager": "stdin.json",
+	"bicep":                "stdin.bicep",
+	"cicd":                 "stdin.yaml",
+	"cloudformation":       "stdin.yaml",
+	"dockercompose":        "stdin.yaml",
+	"dockerfile":           "Dockerfile",
+	"graphql":              "stdin.graphql",
+	"kubernetes":           "stdin.yaml",
+	"openapi":              "stdin.yaml",
+	"pulumi":               "stdin.yaml",
+	"terraform":            "stdin.tf",
+}
+
+const (
+	// terraformPlanStdinFilename is the virtual filename of the Terraform plans in JSON ('terraform show -json'),
+	// parsed as plans by the JSON parser
+	terraformPlanStdinFilename = "stdin.json"
+	// terraformJSONStdinFilename is the virtual filename of the Terraform configurations in the JSON syntax
+	terraformJSONStdinFilename = "stdin.tf.json"
+)
+
 func (c *Client) prepareAndAnalyzePaths() (extractedPaths provider.ExtractedPath, err error) {
 	err = c.preparePaths()
 	if err != nil {
 		return extractedPaths, err
 	}
 
-	extractedPaths, err = provider.GetSources(c.ScanParams.Path)
+	if provider.IsStdin(c.ScanParams.Path) {
+		return c.prepareStdin()
+	}
+
+	fetchOpts, err := c.getFetchOptions()
 	if err != nil {
 		return extractedPaths, err
 	}
 
+	extractedPaths, err = provider.GetSources(c.ScanParams.Path, fetchOpts)
+	if err != nil {
+		return extractedPaths, err
+	}
+
+	if c.ScanParams.ArchiveDepth > 0 {
+		if err = c.extractArchives(&extractedPaths); err != nil {
+			return extractedPaths, err
+		}
+	}
+
 	newTypeFlagValue, newExcludePathsFlagValue, errAnalyze :=
 		analyzePaths(
 			extractedPaths.Path,
 			c.ScanParams.Platform,
 			c.ScanParams.ExcludePaths,
+			len(c.ScanParams.AnsibleVaultPasswordFiles) > 0,
 		)
 	if errAnalyze != nil {
 		return extractedPaths, errAnalyze
@@ -38,6 +84,84 @@ func (c *Client) prepareAndAnalyzePaths() (extractedPaths provider.ExtractedPath
 	return extractedPaths, nil
 }
 
+// extractArchives extracts the nested archives found in the paths to scan, so their files are also analyzed and scanned
+func (c *Client) extractArchives(extractedPaths *provider.ExtractedPath) error {
+	fsProvider, err := provider.NewFileSystemSourceProvider(extractedPaths.Path, c.ScanParams.ExcludePaths)
+	if err != nil {
+		return err
+	}
+	archives := fsProvider.ExtractArchives(c.archiveLimits())
+	extractedPaths.MarkArchives()
+	for dir, archive := range archives {
+		extractedPaths.ExtractionMap[dir] = archive
+		extractedPaths.RemoveTmp = append(extractedPaths.RemoveTmp, dir)
+	}
+	extractedPaths.Path = fsProvider.GetBasePaths()
+	return nil
+}
+
+// archiveLimits returns the limits of the extraction of the nested archives and of the layers of the container images
+func (c *Client) archiveLimits() provider.ArchiveLimits {
+	return provider.ArchiveLimits{
+		Depth:    c.ScanParams.ArchiveDepth,
+		MaxSize:  int64(c.ScanParams.ArchiveMaxSize) * bytesPerMegabyte,
+		MaxFiles: c.ScanParams.ArchiveMaxFiles,
+	}
+}
+
+// prepareStdin prepares the scan of the content of stdin, the types of the queries to load are given by
+// the '--stdin-type' flag since there are no files to analyze
+func (c *Client) prepareStdin() (provider.ExtractedPath, error) {
+	if len(c.ScanParams.Path) > 1 {
+		return provider.ExtractedPath{}, errors.New("stdin ('-') can not be scanned together with other paths")
+	}
+
+	content, err := provider.ReadStdin(os.Stdin, int64(c.ScanParams.StdinMaxSize)*bytesPerMegabyte)
+	if err != nil {
+		return provider.ExtractedPath{}, err
+	}
+
+	filename := c.ScanParams.StdinFilename
+	if filename == "" {
+		var ok bool
+		if filename, ok = stdinFilename(c.ScanParams.StdinType, content); !ok {
+			return provider.ExtractedPath{}, errors.New("--stdin-type or --stdin-filename is required to scan stdin")
+		}
+	}
+	c.stdin = provider.NewStdinSourceProvider(content, filename)
+
+	if c.ScanParams.StdinType != "" && (len(c.ScanParams.Platform) == 0 || c.ScanParams.Platform[0] == "") {
+		c.ScanParams.Platform = []string{c.ScanParams.StdinType}
+	}
+	log.Info().Msgf("Scanning stdin as %s", filename)
+
+	return provider.ExtractedPath{
+		Path: []string{filename},
+		ExtractionMap: map[string]model.ExtractedPathObject{
+			filename: {Path: filename, LocalPath: true},
+		},
+	}, nil
+}
+
+// stdinFilename returns the default virtual filename of the content of stdin for the platform type, the JSON
+// content of the Terraform type is a plan ('terraform show -json') or a configuration in the JSON syntax
+func stdinFilename(platformType string, content []byte) (string, bool) {
+	filename, ok := stdinFilenames[strings.ToLower(platformType)]
+	if !ok || filename != stdinFilenames["terraform"] {
+		return filename, ok
+	}
+	var plan struct {
+		PlannedValues json.RawMessage `json:"planned_values"`
+	}
+	if err := json.Unmarshal(content, &plan); err != nil {
+		return filename, ok
+	}
+	if plan.PlannedValues != nil {
+		return terraformPlanStdinFilename, ok
+	}
+	return terraformJSONStdinFilename, ok
+}
+
 func (c *Client) preparePaths() error {
 	var err error
 	err = c.getQueryPath()
@@ -51,9 +175,35 @@ func (c *Client) preparePaths() error {
 	return nil
 }
 
+// getFetchOptions returns the options of the download of the remote paths
+func (c *Client) getFetchOptions() (provider.FetchOptions, error) {
+	mirrors := make(map[string]string, len(c.ScanParams.FetchMirrors))
+	for _, mirror := range c.ScanParams.FetchMirrors {
+		idx := strings.LastIndex(mirror, "=")
+		if idx <= 0 || idx == len(mirror)-1 {
+			return provider.FetchOptions{}, fmt.Errorf("invalid mirror (--fetch-mirror) %s, expected <prefix>=<directory>", mirror)
+		}
+		mirrors[mirror[:idx]] = mirror[idx+1:]
+	}
+
+	cachePath := c.ScanParams.FetchCachePath
+	if cachePath == "" {
+		cachePath = provider.DefaultFetchCachePath()
+	}
+
+	return provider.FetchOptions{
+		Insecure:        c.ScanParams.InsecureFetch,
+		Offline:         c.ScanParams.Offline,
+		RequireChecksum: c.ScanParams.FetchRequireChecksum,
+		CachePath:       cachePath,
+		Mirrors:         mirrors,
+		ImageLimits:     c.archiveLimits(),
+	}, nil
+}
+
 func (c *Client) getQueryPath() error {
 	if c.ScanParams.ChangedDefaultQueryPath {
-		extractedQueriesPath, errExtractQueries := resolvePath(c.ScanParams.QueriesPath, "queries-path")
+		extractedQueriesPath, errExtractQueries := c.resolvePath(c.ScanParams.QueriesPath, "queries-path")
 		if errExtractQueries != nil {
 			return errExtractQueries
 		}
@@ -72,7 +222,7 @@ func (c *Client) getQueryPath() error {
 
 func (c *Client) getLibraryPath() error {
 	if c.ScanParams.ChangedDefaultLibrariesPath {
-		extractedLibrariesPath, errExtractLibraries := resolvePath(c.ScanParams.LibrariesPath, "libraries-path")
+		extractedLibrariesPath, errExtractLibraries := c.resolvePath(c.ScanParams.LibrariesPath, "libraries-path")
 		if errExtractLibraries != nil {
 			return errExtractLibraries
 		}
@@ -82,8 +232,12 @@ func (c *Client) getLibraryPath() error {
 	return nil
 }
 
-func resolvePath(flagContent, flagName string) (string, error) {
-	extractedPath, errExtractPath := provider.GetSources([]string{flagContent})
+func (c *Client) resolvePath(flagContent, flagName string) (string, error) {
+	fetchOpts, err := c.getFetchOptions()
+	if err != nil {
+		return "", err
+	}
+	extractedPath, errExtractPath := provider.GetSources([]string{flagContent}, fetchOpts)
 	if errExtractPath != nil {
 		return "", errExtractPath
 	}
@@ -96,12 +250,12 @@ func resolvePath(flagContent, flagName string) (string, error) {
 
 // analyzePaths will analyze the paths to scan to determine which type of queries to load
 // and which files should be ignored, it then updates the types and exclude flags variables
-// with the results found
-func analyzePaths(paths, types, exclude []string) (typesRes, excludeRes []string, errRes error) {
+// with the results found, files encrypted with Ansible Vault are analyzed when ansibleVault is set
+func analyzePaths(paths, types, exclude []string, ansibleVault bool) (typesRes, excludeRes []string, errRes error) {
 	var err error
 	exc := make([]string, 0)
 	if types[0] == "" { // if '--type' flag was given skip file analyzing
-		types, exc, err = analyzer.Analyze(paths)
+		types, exc, err = analyzer.Analyze(paths, ansibleVault)
 		if err != nil {
 			log.Err(err)
 			return []string{}, []string{}, err
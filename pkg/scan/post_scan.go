@@ -10,6 +10,7 @@ import (
 	consoleHelpers "github.com/Checkmarx/kics/internal/console/helpers"
 	consolePrinter "github.com/Checkmarx/kics/internal/console/printer"
 	"github.com/Checkmarx/kics/pkg/descriptions"
+	"github.com/Checkmarx/kics/pkg/engine/secrets"
 	"github.com/Checkmarx/kics/pkg/model"
 	"github.com/Checkmarx/kics/pkg/progress"
 	"github.com/Checkmarx/kics/pkg/report"
@@ -66,15 +67,30 @@ func (c *Client) resolveOutputs(
 		}
 	}
 
-	return printOutput(
+	if err := printOutput(
 		c.ScanParams.OutputPath,
 		c.ScanParams.OutputName,
 		summary, c.ScanParams.ReportFormats,
+		&consoleHelpers.ReportOptions{Markdown: c.markdownOptions()},
 		proBarBuilder,
-	)
+	); err != nil {
+		return err
+	}
+
+	if c.ScanParams.OutputPath == "" {
+		return nil
+	}
+	for _, customReport := range c.CustomReports {
+		if err := customReport.Print(c.ScanParams.OutputPath, c.ScanParams.OutputName, summary); err != nil {
+			log.Error().Msgf("Failed to generate %s report", customReport.Name)
+			return err
+		}
+	}
+	return nil
 }
 
-func printOutput(outputPath, filename string, body interface{}, formats []string, proBarBuilder progress.PbBuilder) error {
+func printOutput(outputPath, filename string, body interface{}, formats []string, opts *consoleHelpers.ReportOptions,
+	proBarBuilder progress.PbBuilder) error {
 	log.Debug().Msg("console.printOutput()")
 	if outputPath == "" {
 		return nil
@@ -84,21 +100,46 @@ func printOutput(outputPath, filename string, body interface{}, formats []string
 	}
 
 	log.Debug().Msgf("Output formats provided [%v]", strings.Join(formats, ","))
-	err := consoleHelpers.GenerateReport(outputPath, filename, body, formats, proBarBuilder)
+	err := consoleHelpers.GenerateReport(outputPath, filename, body, formats, opts, proBarBuilder)
 
 	return err
 }
 
+// payloadFiles returns the scanned files without the files with content decrypted from Ansible Vault,
+// which must not be exported to the payload
+func payloadFiles(files model.FileMetadatas) model.FileMetadatas {
+	payload := make(model.FileMetadatas, 0, len(files))
+	for i := range files {
+		if files[i].Vaulted != nil {
+			log.Debug().Msgf("Omitting vaulted file %s from payload", files[i].FilePath)
+			continue
+		}
+		payload = append(payload, files[i])
+	}
+	return payload
+}
+
 // postScan is responsible for the output results
 func (c *Client) postScan(scanResults *Results) error {
 	summary := c.getSummary(scanResults.Results, time.Now(), model.PathParameters{
 		ScannedPaths:      c.ScanParams.Path,
 		PathExtractionMap: scanResults.ExtractedPaths.ExtractionMap,
 	})
+	summary.AddSuppressed(scanResults.Suppressed, scanResults.ExtractedPaths.ExtractionMap)
+
+	documents := payloadFiles(scanResults.Files).Combine(c.ScanParams.LineInfoPayload)
+	// the secrets are redacted once, before the summary and the payload are given to the printer and the reports
+	if !c.ScanParams.ShowSecrets {
+		redactor := secrets.NewRedactor(scanResults.Secrets)
+		redactor.RedactSummary(&summary)
+		if c.ScanParams.PayloadPath != "" {
+			documents = redactor.RedactDocuments(documents)
+		}
+	}
 
 	if err := c.resolveOutputs(
 		&summary,
-		scanResults.Files.Combine(c.ScanParams.LineInfoPayload),
+		documents,
 		scanResults.FailedQueries,
 		c.Printer,
 		*c.ProBarBuilder); err != nil {
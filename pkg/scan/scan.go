@@ -2,7 +2,9 @@ package scan
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/Checkmarx/kics/assets"
 	"github.com/Checkmarx/kics/pkg/engine"
@@ -12,11 +14,15 @@ import (
 	"github.com/Checkmarx/kics/pkg/kics"
 	"github.com/Checkmarx/kics/pkg/model"
 	"github.com/Checkmarx/kics/pkg/parser"
+	bicepParser "github.com/Checkmarx/kics/pkg/parser/bicep"
 	dockerParser "github.com/Checkmarx/kics/pkg/parser/docker"
+	graphqlParser "github.com/Checkmarx/kics/pkg/parser/graphql"
 	jsonParser "github.com/Checkmarx/kics/pkg/parser/json"
 	terraformParser "github.com/Checkmarx/kics/pkg/parser/terraform"
 	yamlParser "github.com/Checkmarx/kics/pkg/parser/yaml"
+	"github.com/Checkmarx/kics/pkg/parser/yaml/crd"
 	"github.com/Checkmarx/kics/pkg/resolver"
+	"github.com/Checkmarx/kics/pkg/resolver/ansible"
 	"github.com/Checkmarx/kics/pkg/resolver/helm"
 	"github.com/Checkmarx/kics/pkg/scanner"
 
@@ -29,12 +35,17 @@ type Results struct {
 	ExtractedPaths provider.ExtractedPath
 	Files          model.FileMetadatas
 	FailedQueries  map[string]error
+	Secrets        []string
+	// Suppressed are the results ignored by comments or excluded by their similarity ID
+	Suppressed []model.Vulnerability
 }
 
 type executeScanParameters struct {
-	services       []*kics.Service
-	inspector      *engine.Inspector
-	extractedPaths provider.ExtractedPath
+	services         []*kics.Service
+	inspector        *engine.Inspector
+	secretsInspector *secrets.Inspector
+	secretsService   *kics.SecretsService
+	extractedPaths   provider.ExtractedPath
 }
 
 func (c *Client) initScan(ctx context.Context) (*executeScanParameters, error) {
@@ -88,6 +99,7 @@ func (c *Client) initScan(ctx context.Context) (*executeScanParameters, error) {
 		log.Err(err)
 		return nil, err
 	}
+	secretsInspector.SetDecodingDepth(c.ScanParams.SecretsDecodingDepth)
 
 	services, err := c.createService(
 		inspector,
@@ -102,12 +114,20 @@ func (c *Client) initScan(ctx context.Context) (*executeScanParameters, error) {
 		return nil, err
 	}
 
+	secretsService, err := c.createSecretsService(services, secretsInspector)
+	if err != nil {
+		log.Err(err)
+		return nil, err
+	}
+
 	progressBar.Close()
 
 	return &executeScanParameters{
-		services:       services,
-		inspector:      inspector,
-		extractedPaths: extractedPaths,
+		services:         services,
+		inspector:        inspector,
+		secretsInspector: secretsInspector,
+		secretsService:   secretsService,
+		extractedPaths:   extractedPaths,
 	}, nil
 }
 
@@ -118,12 +138,33 @@ func (c *Client) executeScan(ctx context.Context) (*Results, error) {
 		log.Err(err)
 		return nil, err
 	}
+	// the results keep the paths of the sources, their temporary directories are no longer needed after the scan
+	defer executeScanParameters.extractedPaths.RemoveTemporaryPaths()
 
 	if err = scanner.PrepareAndScan(ctx, c.ScanParams.ScanID, *c.ProBarBuilder, executeScanParameters.services); err != nil {
 		log.Err(err)
 		return nil, err
 	}
 
+	if executeScanParameters.secretsService != nil {
+		files, errFiles := c.Storage.GetFiles(ctx, c.ScanParams.ScanID)
+		if errFiles != nil {
+			log.Err(errFiles)
+			return nil, errFiles
+		}
+		if err = executeScanParameters.secretsService.StartScan(ctx, c.ScanParams.ScanID, files); err != nil {
+			log.Err(err)
+			return nil, err
+		}
+	}
+
+	if c.ScanParams.SecretsHistory && !c.ScanParams.DisableSecrets {
+		if err = c.scanSecretsHistory(ctx, executeScanParameters); err != nil {
+			log.Err(err)
+			return nil, err
+		}
+	}
+
 	failedQueries := executeScanParameters.inspector.GetFailedQueries()
 
 	if err != nil {
@@ -147,6 +188,9 @@ func (c *Client) executeScan(ctx context.Context) (*Results, error) {
 		ExtractedPaths: executeScanParameters.extractedPaths,
 		Files:          files,
 		FailedQueries:  failedQueries,
+		Secrets:        executeScanParameters.secretsInspector.GetSecrets(),
+		Suppressed: append(executeScanParameters.inspector.GetSuppressed(),
+			executeScanParameters.secretsInspector.GetSuppressed()...),
 	}, nil
 }
 
@@ -172,6 +216,92 @@ func getSecretsRegexRules(regexRulesPath string) (regexRulesContent string, err
 	return regexRulesContent, nil
 }
 
+// getAnsibleVault returns the vault used to decrypt Ansible Vault files, nil if no password file was given
+func (c *Client) getAnsibleVault() (*ansible.Vault, error) {
+	if len(c.ScanParams.AnsibleVaultPasswordFiles) == 0 {
+		return nil, nil
+	}
+	return ansible.NewVault(c.ScanParams.AnsibleVaultPasswordFiles)
+}
+
+// getCRDs returns the registry of the CustomResourceDefinitions used to type and validate custom resources,
+// nil if no CustomResourceDefinition path was given
+func (c *Client) getCRDs() (*crd.Registry, error) {
+	if len(c.ScanParams.CRDPaths) == 0 {
+		return nil, nil
+	}
+	crds, err := crd.NewRegistry(c.ScanParams.CRDPaths)
+	if err != nil {
+		return nil, err
+	}
+	log.Info().Msgf("Registered %d CustomResourceDefinition versions", crds.Len())
+	return crds, nil
+}
+
+// getDockerBuildArgs returns the build args used to resolve the ARG instructions of Dockerfiles, an arg
+// without value takes the value of the environment variable with the same name
+func (c *Client) getDockerBuildArgs() (map[string]string, error) {
+	buildArgs := make(map[string]string, len(c.ScanParams.DockerBuildArgs))
+	for _, buildArg := range c.ScanParams.DockerBuildArgs {
+		parts := strings.SplitN(buildArg, "=", 2)
+		if parts[0] == "" {
+			return nil, fmt.Errorf("invalid docker build arg '%s', expected 'NAME=value' or 'NAME'", buildArg)
+		}
+		if len(parts) == 2 {
+			buildArgs[parts[0]] = parts[1]
+		} else if value, ok := os.LookupEnv(parts[0]); ok {
+			buildArgs[parts[0]] = value
+		}
+	}
+	return buildArgs, nil
+}
+
+// createSecretsService creates the service checking the secrets of the text files not scanned by the services,
+// it returns nil when only the scanned files are checked
+func (c *Client) createSecretsService(services []*kics.Service, secretsInspector *secrets.Inspector) (*kics.SecretsService, error) {
+	if !c.ScanParams.SecretsAllFiles || c.ScanParams.DisableSecrets || len(services) == 0 {
+		return nil, nil
+	}
+	textProvider, ok := services[0].SourceProvider.(provider.TextSourceProvider)
+	if !ok {
+		log.Warn().Msg("Only the scanned files are checked for secrets, the sources do not provide text files")
+		return nil, nil
+	}
+	filter, err := provider.NewTextFilter(c.ScanParams.SecretsIncludePaths, c.ScanParams.SecretsExcludePaths)
+	if err != nil {
+		return nil, err
+	}
+	return &kics.SecretsService{
+		SourceProvider:   textProvider,
+		Storage:          c.Storage,
+		SecretsInspector: secretsInspector,
+		Tracker:          c.Tracker,
+		Filter:           filter,
+	}, nil
+}
+
+// scanSecretsHistory checks the secrets added by the commits of the scanned paths that are git repositories
+func (c *Client) scanSecretsHistory(ctx context.Context, params *executeScanParameters) error {
+	opts := secrets.HistoryOptions{
+		Range: c.ScanParams.SecretsHistoryRange,
+		Since: c.ScanParams.SecretsHistorySince,
+	}
+	for _, scanPath := range params.extractedPaths.Path {
+		if info, err := os.Stat(scanPath); err != nil || !info.IsDir() || !secrets.IsGitRepository(scanPath) {
+			log.Warn().Msgf("Git history of %s not scanned, it is not a git repository", scanPath)
+			continue
+		}
+		vulnerabilities, err := params.secretsInspector.InspectHistory(ctx, params.extractedPaths.Path, scanPath, opts)
+		if err != nil {
+			return err
+		}
+		if err := c.Storage.SaveVulnerabilities(ctx, vulnerabilities); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (c *Client) createQueryFilter() *source.QueryInspectorParameters {
 	excludeQueries := source.ExcludeQueries{
 		ByIDs:        c.ScanParams.ExcludeQueries,
@@ -200,16 +330,33 @@ func (c *Client) createService(
 	t kics.Tracker,
 	store kics.Storage,
 	querySource *source.FilesystemSource) ([]*kics.Service, error) {
-	filesSource, err := c.getFileSystemSourceProvider(paths)
+	filesSource, err := c.getSourceProvider(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	vault, err := c.getAnsibleVault()
+	if err != nil {
+		return nil, err
+	}
+
+	crds, err := c.getCRDs()
+	if err != nil {
+		return nil, err
+	}
+
+	buildArgs, err := c.getDockerBuildArgs()
 	if err != nil {
 		return nil, err
 	}
 
 	combinedParser, err := parser.NewBuilder().
-		Add(&jsonParser.Parser{}).
-		Add(&yamlParser.Parser{}).
+		Add(&jsonParser.Parser{ScanPaths: paths}).
+		Add(&yamlParser.Parser{Vault: vault, CRDs: crds, ScanPaths: paths}).
 		Add(terraformParser.NewDefault()).
-		Add(&dockerParser.Parser{}).
+		Add(&dockerParser.BuildArgsParser{BuildArgs: buildArgs}).
+		Add(&bicepParser.Parser{}).
+		Add(&graphqlParser.Parser{}).
 		Build(querySource.Types, querySource.CloudProviders)
 	if err != nil {
 		return nil, err
@@ -242,6 +389,14 @@ func (c *Client) createService(
 	return services, nil
 }
 
+// getSourceProvider returns the provider of the files to scan, the content of stdin when the path is '-'
+func (c *Client) getSourceProvider(paths []string) (provider.SourceProvider, error) {
+	if c.stdin != nil {
+		return c.stdin, nil
+	}
+	return c.getFileSystemSourceProvider(paths)
+}
+
 func (c *Client) getFileSystemSourceProvider(paths []string) (*provider.FileSystemSourceProvider, error) {
 	var excludePaths []string
 	if c.ScanParams.PayloadPath != "" {
@@ -13,10 +13,13 @@ import (
 	"github.com/Checkmarx/kics/pkg/engine/source"
 	"github.com/Checkmarx/kics/pkg/model"
 	"github.com/Checkmarx/kics/pkg/parser"
+	bicepParser "github.com/Checkmarx/kics/pkg/parser/bicep"
 	dockerParser "github.com/Checkmarx/kics/pkg/parser/docker"
+	graphqlParser "github.com/Checkmarx/kics/pkg/parser/graphql"
 	jsonParser "github.com/Checkmarx/kics/pkg/parser/json"
 	terraformParser "github.com/Checkmarx/kics/pkg/parser/terraform"
 	yamlParser "github.com/Checkmarx/kics/pkg/parser/yaml"
+	"github.com/Checkmarx/kics/pkg/parser/yaml/crd"
 	"github.com/Checkmarx/kics/pkg/kics"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
@@ -33,6 +36,9 @@ var (
 		"../assets/queries/terraform/kubernetes": {FileKind: []model.FileKind{model.KindTerraform, model.KindJSON}, Platform: "terraform"},
 		"../assets/queries/terraform/general":    {FileKind: []model.FileKind{model.KindTerraform, model.KindJSON}, Platform: "terraform"},
 		"../assets/queries/k8s":                  {FileKind: []model.FileKind{model.KindYAML}, Platform: "k8s"},
+		"../assets/queries/k8s/crossplane/aws":   {FileKind: []model.FileKind{model.KindYAML}, Platform: "k8s"},
+		"../assets/queries/k8s/crossplane/azure": {FileKind: []model.FileKind{model.KindYAML}, Platform: "k8s"},
+		"../assets/queries/k8s/crossplane/gcp":   {FileKind: []model.FileKind{model.KindYAML}, Platform: "k8s"},
 		"../assets/queries/cloudFormation":       {FileKind: []model.FileKind{model.KindYAML, model.KindJSON}, Platform: "cloudFormation"},
 		"../assets/queries/ansible/aws":          {FileKind: []model.FileKind{model.KindYAML}, Platform: "ansible"},
 		"../assets/queries/ansible/gcp":          {FileKind: []model.FileKind{model.KindYAML}, Platform: "ansible"},
@@ -41,7 +47,15 @@ var (
 		"../assets/queries/openAPI/general":      {FileKind: []model.FileKind{model.KindYAML, model.KindJSON}, Platform: "openAPI"},
 		"../assets/queries/openAPI/3.0":          {FileKind: []model.FileKind{model.KindYAML, model.KindJSON}, Platform: "openAPI"},
 		"../assets/queries/openAPI/2.0":          {FileKind: []model.FileKind{model.KindYAML, model.KindJSON}, Platform: "openAPI"},
-		"../assets/queries/azureResourceManager": {FileKind: []model.FileKind{model.KindJSON}, Platform: "azureResourceManager"},
+		"../assets/queries/azureResourceManager": {FileKind: []model.FileKind{model.KindJSON, model.KindBICEP}, Platform: "azureResourceManager"},
+		"../assets/queries/asyncAPI":             {FileKind: []model.FileKind{model.KindYAML, model.KindJSON}, Platform: "asyncAPI"},
+		"../assets/queries/graphql":              {FileKind: []model.FileKind{model.KindGRAPHQL, model.KindYAML}, Platform: "graphql"},
+		"../assets/queries/dockerCompose":        {FileKind: []model.FileKind{model.KindYAML}, Platform: "dockerCompose"},
+		"../assets/queries/cicd/github":          {FileKind: []model.FileKind{model.KindYAML}, Platform: "cicd"},
+		"../assets/queries/cicd/general":         {FileKind: []model.FileKind{model.KindYAML}, Platform: "cicd"},
+		"../assets/queries/pulumi/aws":           {FileKind: []model.FileKind{model.KindYAML}, Platform: "pulumi"},
+		"../assets/queries/pulumi/azure":         {FileKind: []model.FileKind{model.KindYAML}, Platform: "pulumi"},
+		"../assets/queries/pulumi/gcp":           {FileKind: []model.FileKind{model.KindYAML}, Platform: "pulumi"},
 	}
 
 	issueTypes = map[string]string{
@@ -115,6 +129,10 @@ func loadQueries(tb testing.TB) []queryEntry {
 
 		for _, f := range fs {
 			f.Name()
+			if f.IsDir() && isQueriesGroup(path.Join(queriesPath, f.Name())) {
+				// the queries of the group are loaded from their own queries path
+				continue
+			}
 			if f.IsDir() && f.Name() != "test" {
 				queriesDir = appendQueries(queriesDir, filepath.FromSlash(path.Join(queriesPath, f.Name())), queryConfig.FileKind, queryConfig.Platform)
 			} else {
@@ -126,6 +144,16 @@ func loadQueries(tb testing.TB) []queryEntry {
 	return queriesDir
 }
 
+// isQueriesGroup verifies if the directory groups queries paths (e.g. k8s/crossplane groups k8s/crossplane/aws)
+func isQueriesGroup(dir string) bool {
+	for queriesPath := range queriesPaths {
+		if strings.HasPrefix(queriesPath, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
 func getFileMetadatas(t testing.TB, filesPath []string) model.FileMetadatas {
 	fileMetadatas := make(model.FileMetadatas, 0)
 	for _, path := range filesPath {
@@ -159,11 +187,15 @@ func getFilesMetadatasWithContent(t testing.TB, filePath string, content []byte)
 }
 
 func getCombinedParser() []*parser.Parser {
+	// custom resources of the samples are typed and validated with the CustomResourceDefinitions of the fixtures
+	crds, _ := crd.NewRegistry([]string{filepath.FromSlash("fixtures/crds")})
 	bd, _ := parser.NewBuilder().
 		Add(&jsonParser.Parser{}).
-		Add(&yamlParser.Parser{}).
+		Add(&yamlParser.Parser{CRDs: crds}).
 		Add(terraformParser.NewDefault()).
 		Add(&dockerParser.Parser{}).
+		Add(&bicepParser.Parser{}).
+		Add(&graphqlParser.Parser{}).
 		Build([]string{""}, []string{""})
 	return bd
 }
@@ -61,6 +61,11 @@ var (
 		"OpenAPI":              "openAPI",
 		"Terraform":            "terraform",
 		"AzureResourceManager": "azureResourceManager",
+		"AsyncAPI":             "asyncAPI",
+		"GraphQL":              "graphql",
+		"DockerCompose":        "dockerCompose",
+		"CICD":                 "cicd",
+		"Pulumi":               "pulumi",
 	}
 	platformKeys = MapToStringSlice(availablePlatforms)
 
@@ -240,7 +245,7 @@ func testQueryHasGoodReturnParams(t *testing.T, entry queryEntry) { //nolint
 	wg := &sync.WaitGroup{}
 	currentQuery := make(chan int64)
 	proBarBuilder := progress.InitializePbBuilder(true, true, true)
-	platforms := []string{"Ansible", "CloudFormation", "Kubernetes", "OpenAPI", "Terraform", "Dockerfile", "AzureResourceManager"}
+	platforms := []string{"Ansible", "CloudFormation", "Kubernetes", "OpenAPI", "Terraform", "Dockerfile", "AzureResourceManager", "AsyncAPI", "GraphQL", "DockerCompose", "CICD", "Pulumi"}
 	progressBar := proBarBuilder.BuildCounter("Executing queries: ", inspector.LenQueriesByPlat(platforms), wg, currentQuery)
 	go progressBar.Start()
 
@@ -0,0 +1,108 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	reportModel "github.com/Checkmarx/kics/pkg/report/model"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/require"
+)
+
+// sarifFixes holds the fixes of the results of a SARIF report
+type sarifFixes struct {
+	Runs []struct {
+		Results []struct {
+			Locations []struct {
+				PhysicalLocation struct {
+					Region struct {
+						StartLine int `json:"startLine"`
+					} `json:"region"`
+				} `json:"physicalLocation"`
+			} `json:"locations"`
+			Fixes []struct {
+				ArtifactChanges []struct {
+					Replacements []struct {
+						DeletedRegion struct {
+							StartLine int `json:"startLine"`
+						} `json:"deletedRegion"`
+						InsertedContent struct {
+							Text string `json:"text"`
+						} `json:"insertedContent"`
+					} `json:"replacements"`
+				} `json:"artifactChanges"`
+			} `json:"fixes"`
+		} `json:"results"`
+	} `json:"runs"`
+}
+
+// TestQueriesRemediation checks that every positive result of the queries with a remediation is reported
+// with a fix in the SARIF report
+func TestQueriesRemediation(t *testing.T) {
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: io.Discard})
+
+	if testing.Short() {
+		t.Skip("skipping queries remediation test in short mode.")
+	}
+
+	for _, entry := range loadQueries(t) {
+		content, err := getQueryContent(entry.dir)
+		require.NoError(t, err)
+		if !strings.Contains(content, `"remediation"`) {
+			continue
+		}
+
+		entry := entry
+		t.Run(strings.TrimPrefix(entry.dir, BaseTestsScanPath)+"_remediation", func(t *testing.T) {
+			testQueryRemediation(t, entry)
+		})
+	}
+}
+
+func testQueryRemediation(t *testing.T, entry queryEntry) {
+	vulnerabilities := inspectQuery(t, entry, entry.PositiveFiles(t))
+
+	remediations := 0
+	afters := make(map[string]bool)
+	for i := range vulnerabilities {
+		if vulnerabilities[i].Remediation != nil {
+			remediations++
+			afters[vulnerabilities[i].Remediation.After] = true
+		}
+	}
+	require.NotZero(t, remediations, "query %s returns no remediation for its positive samples", entry.dir)
+
+	summary := model.CreateSummary(model.Counters{}, vulnerabilities, scanID,
+		map[string]model.ExtractedPathObject{}, model.Version{})
+	sarifReport := reportModel.NewSarifReport()
+	for idx := range summary.Queries {
+		sarifReport.BuildSarifIssue(&summary.Queries[idx])
+	}
+
+	body, err := json.Marshal(sarifReport)
+	require.NoError(t, err)
+	var report sarifFixes
+	require.NoError(t, json.Unmarshal(body, &report))
+	require.Len(t, report.Runs, 1)
+
+	fixes := 0
+	for _, result := range report.Runs[0].Results {
+		if len(result.Fixes) == 0 {
+			continue
+		}
+		fixes++
+		require.Len(t, result.Fixes, 1)
+		require.Len(t, result.Fixes[0].ArtifactChanges, 1)
+		replacements := result.Fixes[0].ArtifactChanges[0].Replacements
+		require.Len(t, replacements, 1)
+		require.Equal(t, result.Locations[0].PhysicalLocation.Region.StartLine, replacements[0].DeletedRegion.StartLine,
+			"query %s fixes a line other than the line of its result", entry.dir)
+		require.True(t, afters[replacements[0].InsertedContent.Text],
+			"query %s fix inserts '%s'", entry.dir, replacements[0].InsertedContent.Text)
+	}
+	require.Equal(t, remediations, fixes, "query %s has results with a remediation but without a fix", entry.dir)
+}
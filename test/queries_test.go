@@ -129,6 +129,13 @@ func getExpectedVulnerabilities(tb testing.TB, entry queryEntry) []model.Vulnera
 }
 
 func testQuery(tb testing.TB, entry queryEntry, filesPath []string, expectedVulnerabilities []model.Vulnerability) {
+	vulnerabilities := inspectQuery(tb, entry, filesPath)
+	validateQueryResultFields(tb, vulnerabilities)
+	requireEqualVulnerabilities(tb, expectedVulnerabilities, vulnerabilities, entry.dir)
+}
+
+// inspectQuery runs the query of the entry against the files and returns its results
+func inspectQuery(tb testing.TB, entry queryEntry, filesPath []string) []model.Vulnerability {
 	ctrl := gomock.NewController(tb)
 	defer ctrl.Finish()
 
@@ -174,7 +181,7 @@ func testQuery(tb testing.TB, entry queryEntry, filesPath []string, expectedVuln
 	wg := &sync.WaitGroup{}
 	currentQuery := make(chan int64)
 	proBarBuilder := progress.InitializePbBuilder(true, true, true)
-	platforms := []string{"Ansible", "CloudFormation", "Kubernetes", "OpenAPI", "Terraform", "Dockerfile", "AzureResourceManager"}
+	platforms := []string{"Ansible", "CloudFormation", "Kubernetes", "OpenAPI", "Terraform", "Dockerfile", "AzureResourceManager", "AsyncAPI", "GraphQL", "DockerCompose", "CICD", "Pulumi"}
 	progressBar := proBarBuilder.BuildCounter("Executing queries: ", inspector.LenQueriesByPlat(platforms), wg, currentQuery)
 	go progressBar.Start()
 	wg.Add(1)
@@ -196,8 +203,7 @@ func testQuery(tb testing.TB, entry queryEntry, filesPath []string, expectedVuln
 	}()
 
 	require.Nil(tb, err)
-	validateQueryResultFields(tb, vulnerabilities)
-	requireEqualVulnerabilities(tb, expectedVulnerabilities, vulnerabilities, entry.dir)
+	return vulnerabilities
 }
 
 func vulnerabilityCompare(vulnerabilitySlice []model.Vulnerability, i, j int) bool {